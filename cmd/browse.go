@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -18,43 +19,75 @@ import (
 	"bqs/internal/validation"
 )
 
+var (
+	browsePrefetchParallelism int
+	browsePrefetchRate        float64
+)
+
 var browseCmd = &cobra.Command{
-	Use:   "browse <project.dataset>",
+	Use:   "browse [project[.dataset[.table]]]",
 	Short: "Interactive BigQuery dataset browser",
 	Long: `Browse BigQuery datasets interactively with a terminal UI.
 
-Navigate tables and views with keyboard controls, view schemas, and explore
-your BigQuery resources without writing queries.
+Navigate datasets, tables and views with keyboard controls, view schemas, and
+explore your BigQuery resources without writing queries.
+
+Without an argument the browser opens on a project picker listing the
+projects your credentials can see, with BQS_FAVORITE_PROJECTS
+(comma-separated) pinned to the top.
+
+Table metadata is prefetched in the background, the rows on screen first,
+so tables open instantly and the Cache column fills in as you browse.
+Prefetching pauses while you wait on anything else.
+
+Table lists load page by page with a progress count. bq ls doesn't expose
+page tokens, so with the default bq backend the list arrives in one piece
+behind a plain spinner; set BQS_BACKEND=rest for incremental progress.
 
 Examples:
+  bqs browse                               # Pick a project, then drill down
+  bqs browse my-project                    # Browse all datasets in a project
   bqs browse my-project.analytics          # Browse analytics dataset
   bqs browse my-project.analytics.table    # Deep dive into specific table`,
-	Args: cobra.ExactArgs(1),
+	Args: cobra.MaximumNArgs(1),
 	RunE: runBrowse,
 }
 
 func init() {
 	rootCmd.AddCommand(browseCmd)
+
+	browseCmd.Flags().IntVar(&browsePrefetchParallelism, "prefetch-parallelism", config.PrefetchParallelism, "Parallel metadata fetches in the background (0 turns prefetching off)")
+	browseCmd.Flags().Float64Var(&browsePrefetchRate, "prefetch-rate", config.PrefetchRate, "Background metadata fetches per second (0 for no limit)")
 }
 
 func runBrowse(cmd *cobra.Command, args []string) error {
-	input := args[0]
+	var project, dataset, table string
+	if len(args) > 0 {
+		input := args[0]
+
+		// Validate input format
+		if err := validation.ValidateResourcePath(input); err != nil {
+			if bqsErr := errors.WrapValidationError(err, input); bqsErr != nil {
+				return fmt.Errorf("%s", bqsErr.UserFriendlyMessage())
+			}
+			return fmt.Errorf("invalid input: %w", err)
+		}
 
-	// Validate input format
-	if err := validation.ValidateProjectDatasetTable(input); err != nil {
-		if bqsErr := errors.WrapValidationError(err, input); bqsErr != nil {
-			return fmt.Errorf("%s", bqsErr.UserFriendlyMessage())
+		// Parse input - could be project, project.dataset or project.dataset.table
+		parts := strings.Split(input, ".")
+		project = parts[0]
+		if len(parts) > 1 {
+			dataset = parts[1]
+		}
+		if len(parts) > 2 {
+			table = strings.Join(parts[2:], ".")
 		}
-		return fmt.Errorf("invalid input: %w", err)
 	}
-
-	// Parse input - could be project.dataset or project.dataset.table
-	parts := strings.Split(input, ".")
-	project := parts[0]
-	dataset := parts[1]
-	var table string
-	if len(parts) > 2 {
-		table = strings.Join(parts[2:], ".")
+	if browsePrefetchParallelism < 0 {
+		return fmt.Errorf("--prefetch-parallelism must not be negative, got %d", browsePrefetchParallelism)
+	}
+	if browsePrefetchRate < 0 {
+		return fmt.Errorf("--prefetch-rate must not be negative, got %g", browsePrefetchRate)
 	}
 
 	// Initialize cache and BigQuery client
@@ -69,32 +102,46 @@ func runBrowse(cmd *cobra.Command, args []string) error {
 
 	bqClient := bigquery.NewClient(c)
 
+	// Cancelled on exit so in-flight loads don't outlive the browser
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
 	// Try interactive mode first, fallback to static mode
-	model := newBrowserModel(project, dataset, table, bqClient)
+	model := newBrowserModel(ctx, project, dataset, table, bqClient)
+	model.prefetcher = bigquery.NewPrefetcher(bqClient, browsePrefetchParallelism, browsePrefetchRate, operationTimeout)
 	p := tea.NewProgram(model, tea.WithAltScreen())
 
 	if _, err := p.Run(); err != nil {
 		// Fallback to static listing if interactive mode fails
-		return runStaticBrowse(project, dataset, table, bqClient)
+		return runStaticBrowse(ctx, project, dataset, table, bqClient)
 	}
 
 	return nil
 }
 
-func runStaticBrowse(project, dataset, tableName string, client *bigquery.Client) error {
+func runStaticBrowse(ctx context.Context, project, dataset, tableName string, client *bigquery.Client) error {
+	ctx, cancel := withOperationTimeout(ctx)
+	defer cancel()
+
 	if tableName != "" {
 		// Show specific table metadata
-		metadata, err := client.GetTableMetadata(project, dataset, tableName)
+		metadata, err := client.GetFullTableMetadata(ctx, project, dataset, tableName)
 		if err != nil {
 			return fmt.Errorf("failed to get table metadata: %w", err)
 		}
 
-		fmt.Printf("📊 %s.%s.%s (%s)\n", project, dataset, tableName, metadata.Type)
+		fmt.Printf("📊 %s.%s.%s (%s)\n", project, dataset, tableName, metadata.DisplayType())
 		fmt.Printf("📈 %d rows • 💾 %s • 🕒 Modified %s\n\n",
 			metadata.NumRows,
 			bigquery.FormatSize(metadata.NumBytes),
 			bigquery.FormatTime(metadata.LastModifiedTime))
 
+		fmt.Println("🔧 Details:")
+		for _, detail := range bigquery.MetadataDetails(metadata) {
+			fmt.Printf("  %-24s  %s\n", detail.Label, detail.Value)
+		}
+		fmt.Println()
+
 		if metadata.Schema != nil {
 			fmt.Println("🌲 Schema:")
 			for _, field := range metadata.Schema.Fields {
@@ -111,8 +158,16 @@ func runStaticBrowse(project, dataset, tableName string, client *bigquery.Client
 		return nil
 	}
 
+	if project == "" {
+		return runStaticProjectList(ctx, client)
+	}
+
+	if dataset == "" {
+		return runStaticDatasetList(ctx, project, client)
+	}
+
 	// Show table list
-	tables, err := client.ListTables(project, dataset)
+	tables, err := client.ListTables(ctx, project, dataset)
 	if err != nil {
 		return fmt.Errorf("failed to list tables: %w", err)
 	}
@@ -153,16 +208,144 @@ func runStaticBrowse(project, dataset, tableName string, client *bigquery.Client
 	return nil
 }
 
+func runStaticProjectList(ctx context.Context, client *bigquery.Client) error {
+	projects, err := client.ListProjects(ctx, utils.FavoriteProjects())
+	if err != nil {
+		return fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	if len(projects) == 0 {
+		fmt.Println("No projects found - set BQS_FAVORITE_PROJECTS to list projects explicitly")
+		return nil
+	}
+
+	t := prettytable.NewWriter()
+	t.SetStyle(prettytable.StyleRounded)
+
+	t.AppendHeader(prettytable.Row{"", "Project", "Name"})
+
+	for _, p := range projects {
+		row := prettytable.Row{}
+		for _, col := range projectRow(p) {
+			row = append(row, col)
+		}
+		t.AppendRow(row)
+	}
+
+	fmt.Println(t.Render())
+	fmt.Println("\nUse 'bqs browse PROJECT' to explore a project's datasets")
+
+	return nil
+}
+
+// projectRow formats the project list columns shared by the static and interactive views
+func projectRow(p bigquery.ProjectInfo) []string {
+	favorite := ""
+	if p.Favorite {
+		favorite = "★"
+	}
+	return []string{favorite, p.ProjectReference.ProjectID, p.FriendlyName}
+}
+
+func runStaticDatasetList(ctx context.Context, project string, client *bigquery.Client) error {
+	datasets, err := client.ListDatasets(ctx, project)
+	if err != nil {
+		return fmt.Errorf("failed to list datasets: %w", err)
+	}
+
+	fmt.Printf("📁 %s\n\n", project)
+
+	if len(datasets) == 0 {
+		fmt.Println("No datasets found in this project")
+		return nil
+	}
 
-func newBrowserModel(project, dataset, tableName string, client *bigquery.Client) *browserModel {
+	t := prettytable.NewWriter()
+	t.SetStyle(prettytable.StyleRounded)
+
+	t.AppendHeader(prettytable.Row{"Dataset", "Location", "Created", "Expiration", "Tables", "Labels"})
+
+	for _, ds := range datasets {
+		row := prettytable.Row{}
+		for _, col := range datasetRow(ds) {
+			row = append(row, col)
+		}
+		t.AppendRow(row)
+	}
+
+	fmt.Println(t.Render())
+	fmt.Printf("\nUse 'bqs browse %s.DATASET' to explore specific datasets\n", project)
+
+	return nil
+}
+
+// datasetRow formats the dataset list columns shared by the static and interactive views
+func datasetRow(ds bigquery.DatasetInfo) []string {
+	// Backends that can't count tables cheaply leave the count unset
+	tableCount := "N/A"
+	if ds.TableCount != nil {
+		tableCount = fmt.Sprintf("%d", *ds.TableCount)
+	}
+	return []string{
+		ds.DatasetReference.DatasetID,
+		ds.Location,
+		bigquery.FormatTime(ds.CreationTime),
+		bigquery.FormatExpiration(ds.DefaultTableExpirationMs),
+		tableCount,
+		bigquery.FormatLabels(ds.Labels),
+	}
+}
+
+
+func newBrowserModel(ctx context.Context, project, dataset, tableName string, client *bigquery.Client) *browserModel {
 	// Initialize the table component with better column order
-	columns := []table.Column{
+	t := newStyledTable([]table.Column{
 		{Title: "Table", Width: config.TableColumnWidth},
 		{Title: "Type", Width: config.TypeColumnWidth},
 		{Title: "Created", Width: config.CreatedColumnWidth},
 		{Title: "Cache", Width: config.CacheColumnWidth},
+	})
+
+	projectTable := newStyledTable([]table.Column{
+		{Title: "", Width: config.FavoriteColumnWidth},
+		{Title: "Project", Width: config.ProjectColumnWidth},
+		{Title: "Name", Width: config.FriendlyNameColumnWidth},
+	})
+
+	datasetTable := newStyledTable([]table.Column{
+		{Title: "Dataset", Width: config.DatasetColumnWidth},
+		{Title: "Location", Width: config.LocationColumnWidth},
+		{Title: "Created", Width: config.CreatedColumnWidth},
+		{Title: "Expiration", Width: config.ExpirationColumnWidth},
+		{Title: "Tables", Width: config.TableCountColumnWidth},
+		{Title: "Labels", Width: config.LabelsColumnWidth},
+	})
+
+	model := &browserModel{
+		project:        project,
+		dataset:        dataset,
+		table:          tableName,
+		client:         client,
+		ctx:            ctx,
+		loading:        true,
+		homeLevel:      project == "",
+		projectLevel:   dataset == "",
+		projectModel:   projectTable,
+		datasetModel:   datasetTable,
+		tableModel:     t,
+		expandedNodes:  make(map[string]bool),
+		cachedMetadata: newCachedTables(),
+		keyDispatcher:  NewKeyDispatcher(),
 	}
 
+	// Always start in loading state when data needs to be fetched
+	model.state = stateLoading
+
+	return model
+}
+
+// newStyledTable creates a focused table component with the browser's styling
+func newStyledTable(columns []table.Column) table.Model {
 	t := table.New(
 		table.WithColumns(columns),
 		table.WithFocused(true),
@@ -185,30 +368,33 @@ func newBrowserModel(project, dataset, tableName string, client *bigquery.Client
 		Foreground(lightGray)
 	t.SetStyles(s)
 
-	model := &browserModel{
-		project:        project,
-		dataset:        dataset,
-		table:          tableName,
-		client:         client,
-		loading:        true,
-		tableModel:     t,
-		expandedNodes:  make(map[string]bool),
-		cachedMetadata: make(map[string]*bigquery.TableMetadata),
-		keyDispatcher:  NewKeyDispatcher(),
-	}
-
-	// Always start in loading state when data needs to be fetched
-	model.state = stateLoading
-
-	return model
+	return t
 }
 
 // Init implements tea.Model
 func (m *browserModel) Init() tea.Cmd {
+	var prefetchCmd tea.Cmd
+	if m.prefetcher != nil {
+		m.prefetcher.Start(m.baseContext())
+		prefetchCmd = waitForPrefetch(m.prefetcher.Results())
+	}
+	return tea.Batch(m.initialLoad(), prefetchCmd)
+}
+
+// initialLoad starts loading the level the browser was opened at
+func (m *browserModel) initialLoad() tea.Cmd {
+	ctx, seq := m.startLoad()
+	if m.project == "" {
+		m.favorites = utils.FavoriteProjects()
+		return loadProjectList(ctx, seq, m.client, m.favorites)
+	}
+	if m.dataset == "" {
+		return loadDatasetList(ctx, seq, m.client, m.project)
+	}
 	if m.table != "" {
-		return loadTableMetadata(m.client, m.project, m.dataset, m.table)
+		return loadTableMetadata(ctx, seq, m.client, m.project, m.dataset, m.table)
 	}
-	return loadTableList(m.client, m.project, m.dataset)
+	return loadTableList(ctx, seq, m.client, m.project, m.dataset)
 }
 
 // Update implements tea.Model
@@ -221,9 +407,14 @@ func (m *browserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.statusTimeout = time.Time{}
 	}
 
-	// Update the table model for table list state
+	// Update the table model for list states
+	cursor := m.tableModel.Cursor()
 	if m.state == stateTableList {
 		m.tableModel, cmd = m.tableModel.Update(msg)
+	} else if m.state == stateDatasetList {
+		m.datasetModel, cmd = m.datasetModel.Update(msg)
+	} else if m.state == stateProjectList {
+		m.projectModel, cmd = m.projectModel.Update(msg)
 	}
 
 	switch msg := msg.(type) {
@@ -231,45 +422,284 @@ func (m *browserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		// Update table model height based on available space
-		if m.state == stateTableList {
-			tableHeight := m.height - config.HeaderFooterPadding
-			if tableHeight < config.MinTableHeight {
-				tableHeight = config.MinTableHeight
-			}
-			m.tableModel.SetHeight(tableHeight)
+		// Update list heights based on available space
+		tableHeight := m.height - config.HeaderFooterPadding
+		if tableHeight < config.MinTableHeight {
+			tableHeight = config.MinTableHeight
 		}
+		m.tableModel.SetHeight(tableHeight)
+		m.datasetModel.SetHeight(tableHeight)
+		m.projectModel.SetHeight(tableHeight)
 		return m, cmd
 
 	case tea.KeyMsg:
 		newModel, keyCmd := m.handleKeyPress(msg)
+		if m.prefetcher != nil && m.state == stateTableList && m.tableModel.Cursor() != cursor {
+			// Fetch the rows scrolled into view next
+			m.prefetcher.Prioritize(m.visibleTableRefs())
+		}
 		// Combine commands
 		return newModel, tea.Batch(cmd, keyCmd)
 
+	case projectListLoadedMsg:
+		if msg.seq != m.loadSeq {
+			return m, nil // Abandoned load
+		}
+		m.finishLoad()
+		m.loading = false
+		m.projects = msg.projects
+		m.state = stateProjectList
+		m.updateProjectRows()
+		m.projectModel.SetCursor(0)
+		return m, nil
+
+	case datasetListLoadedMsg:
+		if msg.seq != m.loadSeq {
+			return m, nil // Abandoned load
+		}
+		m.finishLoad()
+		m.loading = false
+		m.datasets = msg.datasets
+		m.state = stateDatasetList
+		m.updateDatasetRows()
+		m.datasetModel.SetCursor(0)
+		return m, nil
+
+	case datasetMetadataLoadedMsg:
+		if msg.seq != m.loadSeq {
+			return m, nil // Abandoned load
+		}
+		m.finishLoad()
+		if msg.err != nil {
+			errorMessage := msg.err.Error()
+			if bqsErr, ok := msg.err.(*errors.BQSError); ok {
+				errorMessage = bqsErr.UserFriendlyMessage()
+			}
+			m.setStatusMessage(fmt.Sprintf("✗ %s", errorMessage))
+			return m, nil
+		}
+		// Only open the pane if the user is still on the list it was requested from
+		if m.state == stateDatasetList || m.state == stateTableList {
+			m.statusMessage = ""
+			m.datasetMetadata = msg.metadata
+			m.refreshDatasetDetails()
+			m.datasetDetailReturn = m.state
+			m.state = stateDatasetDetail
+		}
+		return m, nil
+
+	case tablePreviewLoadedMsg:
+		if msg.seq != m.loadSeq {
+			return m, nil // Abandoned load
+		}
+		m.finishLoad()
+		if msg.err != nil {
+			errorMessage := msg.err.Error()
+			if bqsErr, ok := msg.err.(*errors.BQSError); ok {
+				errorMessage = bqsErr.UserFriendlyMessage()
+			}
+			m.setStatusMessage(fmt.Sprintf("✗ %s", errorMessage))
+			return m, nil
+		}
+		// Only open the grid if the user is still on the table it was requested for
+		if m.state == stateTableDetail {
+			m.statusMessage = ""
+			m.preview = msg.preview
+			m.previewRow, m.previewCol = 0, 0
+			m.previewExpanded = false
+			m.state = statePreview
+		}
+		return m, nil
+
+	case lineageLoadedMsg:
+		if msg.seq != m.loadSeq {
+			return m, nil // Abandoned load
+		}
+		m.finishLoad()
+		if msg.err != nil {
+			errorMessage := msg.err.Error()
+			if bqsErr, ok := msg.err.(*errors.BQSError); ok {
+				errorMessage = bqsErr.UserFriendlyMessage()
+			}
+			m.setStatusMessage(fmt.Sprintf("✗ %s", errorMessage))
+			return m, nil
+		}
+		// Only open the pane if the user is still on the table it was requested for
+		if m.state == stateTableDetail {
+			m.statusMessage = ""
+			m.lineage = msg.entries
+			m.lineageSelected = 0
+			m.state = stateLineage
+		}
+		return m, nil
+
+	case staleTablesLoadedMsg:
+		if msg.seq != m.loadSeq {
+			return m, nil // Abandoned load
+		}
+		m.finishLoad()
+		if msg.err != nil {
+			errorMessage := msg.err.Error()
+			if bqsErr, ok := msg.err.(*errors.BQSError); ok {
+				errorMessage = bqsErr.UserFriendlyMessage()
+			}
+			m.setStatusMessage(fmt.Sprintf("✗ %s", errorMessage))
+			return m, nil
+		}
+		m.staleTables = make(map[string]bool, len(msg.report.Tables))
+		for _, t := range msg.report.Tables {
+			m.staleTables[t.TableID] = true
+		}
+		m.showStale = true
+		m.checkCacheStatus() // Finding them may have cached the dataset
+		m.setStatusMessage(m.staleSummary(msg.report))
+		return m, nil
+
+	case activityLoadedMsg:
+		if msg.seq != m.loadSeq {
+			return m, nil // Abandoned load
+		}
+		m.finishLoad()
+		if msg.err != nil {
+			// Typically a missing bigquery.jobs.listAll; the rest of the detail view still works
+			errorMessage := msg.err.Error()
+			if bqsErr, ok := msg.err.(*errors.BQSError); ok {
+				errorMessage = bqsErr.UserFriendlyMessage()
+			}
+			m.setStatusMessage(fmt.Sprintf("✗ %s", errorMessage))
+			return m, nil
+		}
+		// Only open the pane if the user is still on the table it was requested for
+		if m.state == stateTableDetail {
+			if len(msg.jobs) == 0 {
+				m.setStatusMessage(fmt.Sprintf("No jobs read or wrote %s in the last %d days", m.table, config.ActivityDays))
+				return m, nil
+			}
+			m.statusMessage = ""
+			m.activity = msg.jobs
+			m.activitySelected = 0
+			m.state = stateActivity
+		}
+		return m, nil
+
+	case datasetCacheWarmedMsg:
+		if msg.seq != m.loadSeq {
+			return m, nil // Abandoned load
+		}
+		m.finishLoad()
+		if msg.err != nil {
+			errorMessage := msg.err.Error()
+			if bqsErr, ok := msg.err.(*errors.BQSError); ok {
+				errorMessage = bqsErr.UserFriendlyMessage()
+			}
+			m.setStatusMessage(fmt.Sprintf("✗ %s", errorMessage))
+			return m, nil
+		}
+		m.checkCacheStatus() // Light up the ✓ indicators
+		m.setStatusMessage(fmt.Sprintf("Cached %d of %d tables", msg.cached, msg.total))
+		return m, m.loadSnapshots() // Clones can be found now that everything is cached
+
+	case tableListProgressMsg:
+		if msg.seq != m.loadSeq {
+			return m, nil // Abandoned load
+		}
+		m.progress = loadProgress{loaded: msg.loaded, total: msg.total}
+		return m, waitForTableList(msg.updates)
+
 	case tableListLoadedMsg:
+		if msg.seq != m.loadSeq {
+			return m, nil // Abandoned load
+		}
+		m.finishLoad()
 		m.loading = false
+		m.progress = loadProgress{}
 		m.tables = msg.tables
+		m.snapshots = nil
+		m.staleTables = nil
+		m.showStale = false
 		m.state = stateTableList
 		m.checkCacheStatus() // Check for existing cached metadata
 		m.updateTableRows()  // Update Bubbletea table component
+		m.tableModel.SetCursor(0)
+		m.prefetchTables()
+		return m, m.loadSnapshots()
+
+	case tablePrefetchedMsg:
+		next := waitForPrefetch(m.prefetcher.Results())
+		// Failures are left to the foreground load that opens the table
+		ref := msg.result.Ref
+		if msg.result.Err != nil || ref.ProjectID != m.project || ref.DatasetID != m.dataset {
+			return m, next
+		}
+		// Only a placeholder, like checkCacheStatus's; opening the table reads the cache
+		if m.cachedMetadata.markCached(ref.TableID) {
+			m.updateTableRows()
+			// With the whole dataset cached, every clone can be grouped
+			if m.cachedMetadata.len() == len(m.tables) {
+				return m, tea.Batch(next, m.loadSnapshots())
+			}
+		}
+		return m, next
+
+	case snapshotsLoadedMsg:
+		// Grouping is best effort; the flat list stays if it can't be loaded
+		if msg.err != nil || msg.project != m.project || msg.dataset != m.dataset || len(m.tables) == 0 {
+			return m, nil
+		}
+		m.groupSnapshots(msg.snapshots)
 		return m, nil
 
 	case tableMetadataLoadedMsg:
+		if msg.seq != m.loadSeq {
+			return m, nil // Abandoned load, e.g. the user went back
+		}
+		m.finishLoad()
 		m.loading = false
 		m.metadata = msg.metadata
 		m.state = stateTableDetail
+		annotateCmd := m.annotateTableDetail()
 		m.buildSchemaTree()
 		// Cache the metadata for future use
 		if m.table != "" {
-			m.cachedMetadata[m.table] = msg.metadata
+			m.cachedMetadata.set(m.table, msg.metadata)
 			// Update table rows to show the new cache status
 			if len(m.tables) > 0 {
 				m.updateTableRows()
 			}
 		}
+		return m, annotateCmd
+
+	case columnLineageLoadedMsg:
+		// Annotations are best effort; a view that can't be traced just has none
+		current := bigquery.TableReference{ProjectID: m.project, DatasetID: m.dataset, TableID: m.table}
+		if msg.err != nil || msg.ref != current || m.metadata == nil {
+			return m, nil
+		}
+		m.columnLineage = make(map[string][]bigquery.ColumnSource, len(msg.columns))
+		for _, column := range msg.columns {
+			m.columnLineage[column.Name] = column.Sources
+		}
+		m.buildSchemaTree()
+		return m, nil
+
+	case partitionsLoadedMsg:
+		current := bigquery.TableReference{ProjectID: m.project, DatasetID: m.dataset, TableID: m.table}
+		if msg.ref != m.partitionsRef || msg.ref != current {
+			return m, nil
+		}
+		m.partitionsErr = msg.err
+		// Newest first, the order the partitions pane lists them in
+		m.partitions = make([]bigquery.PartitionInfo, 0, len(msg.partitions))
+		for i := len(msg.partitions) - 1; i >= 0; i-- {
+			m.partitions = append(m.partitions, msg.partitions[i])
+		}
 		return m, nil
 
 	case errorMsg:
+		if msg.seq != m.loadSeq {
+			return m, nil // Abandoned load
+		}
+		m.finishLoad()
 		m.loading = false
 		m.err = msg.err
 		m.state = stateError
@@ -281,7 +711,7 @@ func (m *browserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			
 			// Cache the metadata if it was fetched (only happens from dataset level export)
 			if msg.metadata != nil && m.state == stateTableList {
-				m.cachedMetadata[msg.tableID] = msg.metadata
+				m.cachedMetadata.set(msg.tableID, msg.metadata)
 				// Update table rows to show the new cache status
 				if len(m.tables) > 0 {
 					m.updateTableRows()
@@ -310,10 +740,26 @@ func (m *browserModel) View() string {
 	switch m.state {
 	case stateLoading:
 		return m.renderLoading()
+	case stateProjectList:
+		return m.renderProjectList()
+	case stateDatasetList:
+		return m.renderDatasetList()
+	case stateDatasetDetail:
+		return m.renderDatasetDetail()
 	case stateTableList:
 		return m.renderTableList()
 	case stateTableDetail:
 		return m.renderTableDetail()
+	case stateViewSQL:
+		return m.renderViewSQL()
+	case statePreview:
+		return m.renderPreview()
+	case stateLineage:
+		return m.renderLineage()
+	case statePartitions:
+		return m.renderPartitions()
+	case stateActivity:
+		return m.renderActivity()
 	case stateError:
 		return m.renderError()
 	case stateHelp:
@@ -334,6 +780,474 @@ func (m *browserModel) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 
 
+// updateRows refreshes whichever list the current state shows
+func (m *browserModel) updateRows() {
+	if m.state == stateProjectList {
+		m.updateProjectRows()
+		return
+	}
+	if m.state == stateDatasetList {
+		m.updateDatasetRows()
+		return
+	}
+	m.updateTableRows()
+}
+
+// updateProjectRows populates the project list component with current project data
+func (m *browserModel) updateProjectRows() {
+	projectsToShow := m.projects
+	if m.ui.Search.FilteredProjects != nil {
+		projectsToShow = m.ui.Search.FilteredProjects
+	}
+
+	rows := make([]table.Row, len(projectsToShow))
+	for i, p := range projectsToShow {
+		rows[i] = table.Row(projectRow(p))
+	}
+
+	m.projectModel.SetRows(rows)
+}
+
+// openProject switches from the project list to the dataset list of a project
+func (m *browserModel) openProject(project string) tea.Cmd {
+	m.clearSearchState()
+	m.project = project
+	m.dataset = ""
+	m.datasets = nil
+
+	m.loading = true
+	m.state = stateLoading
+	ctx, seq := m.startLoad()
+	return loadDatasetList(ctx, seq, m.client, m.project)
+}
+
+// closeProject returns from a project's dataset list to the project list
+func (m *browserModel) closeProject() {
+	m.clearSearchState()
+	m.loading = false
+	m.project = ""
+	m.dataset = ""
+	m.datasets = nil
+	m.state = stateProjectList
+	m.updateProjectRows()
+}
+
+// refreshDatasetDetails fills in dataset list columns from metadata cached
+// since the list loaded, e.g. by opening a dataset or its detail pane
+func (m *browserModel) refreshDatasetDetails() {
+	m.datasets = m.client.WithCachedDatasetDetails(m.datasets)
+	if m.ui.Search.FilteredDatasets != nil {
+		m.ui.Search.FilteredDatasets = m.client.WithCachedDatasetDetails(m.ui.Search.FilteredDatasets)
+	}
+	m.updateDatasetRows()
+}
+
+// updateDatasetRows populates the dataset list component with current dataset data
+func (m *browserModel) updateDatasetRows() {
+	datasetsToShow := m.datasets
+	if m.ui.Search.FilteredDatasets != nil {
+		datasetsToShow = m.ui.Search.FilteredDatasets
+	}
+
+	rows := make([]table.Row, len(datasetsToShow))
+	for i, ds := range datasetsToShow {
+		rows[i] = table.Row(datasetRow(ds))
+	}
+
+	m.datasetModel.SetRows(rows)
+}
+
+// loadSnapshots starts grouping the dataset's snapshots and clones from the
+// metadata cached so far. Opening a dataset never runs the billed bulk query;
+// copies not cached yet are grouped once the user warms the cache with w.
+func (m *browserModel) loadSnapshots() tea.Cmd {
+	if len(m.tables) == 0 {
+		return nil
+	}
+	return loadSnapshots(m.baseContext(), m.client, m.project, m.dataset)
+}
+
+// groupSnapshots moves each snapshot and clone right after its base table,
+// oldest first, keeping the cursor on the selected table. Copies whose base
+// table is in another dataset, gone, or itself a copy stay where they are.
+func (m *browserModel) groupSnapshots(snapshots []bigquery.TableSnapshot) {
+	tableIDOf := func(tbl bigquery.TableInfo) string {
+		if tbl.TableID != "" {
+			return tbl.TableID
+		}
+		return tbl.TableReference.TableID
+	}
+
+	selected := ""
+	if cursor := m.tableModel.Cursor(); cursor >= 0 && cursor < len(m.tables) {
+		selected = tableIDOf(m.tables[cursor])
+	}
+
+	index := make(map[string]int, len(m.tables))
+	for i, tbl := range m.tables {
+		index[tableIDOf(tbl)] = i
+	}
+	copies := make(map[string]bool, len(snapshots))
+	for _, snapshot := range snapshots {
+		copies[snapshot.Table.TableID] = true
+	}
+
+	m.snapshots = make(map[string]bigquery.TableSnapshot)
+	children := make(map[string][]bigquery.TableInfo)
+	for _, snapshot := range snapshots {
+		base := snapshot.BaseTable
+		i, listed := index[snapshot.Table.TableID]
+		if _, baseListed := index[base.TableID]; !listed || !baseListed || copies[base.TableID] ||
+			base.ProjectID != m.project || base.DatasetID != m.dataset {
+			continue
+		}
+		m.snapshots[snapshot.Table.TableID] = snapshot
+		children[base.TableID] = append(children[base.TableID], m.tables[i])
+	}
+
+	grouped := make([]bigquery.TableInfo, 0, len(m.tables))
+	for _, tbl := range m.tables {
+		id := tableIDOf(tbl)
+		if _, ok := m.snapshots[id]; ok {
+			continue
+		}
+		grouped = append(grouped, tbl)
+		grouped = append(grouped, children[id]...)
+	}
+	m.tables = grouped
+
+	m.updateTableRows()
+	for i, tbl := range m.tables {
+		if tableIDOf(tbl) == selected {
+			m.tableModel.SetCursor(i)
+			break
+		}
+	}
+}
+
+// annotateTableDetail starts the background loads that fill in the table
+// detail view once it's open: view column sources and partitions
+func (m *browserModel) annotateTableDetail() tea.Cmd {
+	return tea.Batch(m.traceColumnLineage(), m.loadPartitions())
+}
+
+// traceColumnLineage clears the schema tree's source annotations and, when
+// the current table is a view, starts tracing its columns to fill them in
+func (m *browserModel) traceColumnLineage() tea.Cmd {
+	m.columnLineage = nil
+	if m.metadata == nil || m.metadata.DefinitionQuery() == "" {
+		return nil
+	}
+	ref := bigquery.TableReference{ProjectID: m.project, DatasetID: m.dataset, TableID: m.table}
+	return loadColumnLineage(m.baseContext(), m.client, ref)
+}
+
+// loadPartitions clears the partitions of the previous table and, when the
+// current one is partitioned, starts loading its partitions
+func (m *browserModel) loadPartitions() tea.Cmd {
+	m.partitions = nil
+	m.partitionsErr = nil
+	m.partitionSelected = 0
+	m.partitionsRef = bigquery.TableReference{}
+	if m.metadata == nil || !m.metadata.Partitioned() {
+		return nil
+	}
+	m.partitionsRef = bigquery.TableReference{ProjectID: m.project, DatasetID: m.dataset, TableID: m.table}
+	return loadPartitions(m.baseContext(), m.client, m.partitionsRef)
+}
+
+// openDataset switches from the dataset list to the table list of a dataset
+func (m *browserModel) openDataset(dataset string) tea.Cmd {
+	m.clearSearchState()
+	m.dataset = dataset
+	m.table = ""
+	m.tables = nil
+	m.metadata = nil
+	m.prefetcher.Clear()
+	// Cache indicators are keyed by table ID, so they don't carry across datasets
+	m.cachedMetadata.reset()
+
+	m.loading = true
+	m.state = stateLoading
+	ctx, seq := m.startLoad()
+	return loadTableList(ctx, seq, m.client, m.project, m.dataset)
+}
+
+// showDatasetDetail loads the detail pane for the selected dataset in the
+// dataset list, or the current dataset in the table list
+func (m *browserModel) showDatasetDetail() tea.Cmd {
+	var dataset string
+	switch m.state {
+	case stateDatasetList:
+		datasetsToShow := m.datasets
+		if m.ui.Search.FilteredDatasets != nil {
+			datasetsToShow = m.ui.Search.FilteredDatasets
+		}
+		selectedIdx := m.datasetModel.Cursor()
+		if selectedIdx >= 0 && selectedIdx < len(datasetsToShow) {
+			dataset = datasetsToShow[selectedIdx].DatasetReference.DatasetID
+		}
+	case stateTableList:
+		dataset = m.dataset
+	}
+
+	if dataset == "" {
+		return nil
+	}
+
+	m.setStatusMessage(fmt.Sprintf("Loading %s.%s details...", m.project, dataset))
+	ctx, seq := m.startLoad()
+	return loadDatasetMetadata(ctx, seq, m.client, m.project, dataset)
+}
+
+// showViewSQL opens the SQL pane for the current view or materialized view
+func (m *browserModel) showViewSQL() {
+	if m.metadata == nil || m.metadata.DefinitionQuery() == "" {
+		return
+	}
+	m.sqlLines = highlightSQL(m.metadata.DefinitionQuery())
+	m.sqlOffset = 0
+	m.state = stateViewSQL
+}
+
+// showPreview loads the preview grid for the current table
+func (m *browserModel) showPreview() tea.Cmd {
+	if m.metadata == nil || m.table == "" {
+		return nil
+	}
+	if !bigquery.Previewable(m.metadata.Type) {
+		m.setStatusMessage("Preview is only available for tables; views need a query")
+		return nil
+	}
+
+	m.setStatusMessage(fmt.Sprintf("Loading preview of %s...", m.table))
+	ctx, seq := m.startLoad()
+	return loadTablePreview(ctx, seq, m.client, m.project, m.dataset, m.table)
+}
+
+// closePreview returns from the preview grid to the table detail view
+func (m *browserModel) closePreview() {
+	m.preview = nil
+	m.previewExpanded = false
+	m.state = stateTableDetail
+}
+
+// movePreviewColumn moves the selected preview column by delta
+func (m *browserModel) movePreviewColumn(delta int) {
+	col := m.previewCol + delta
+	if col >= 0 && col < len(m.preview.Columns()) {
+		m.previewCol = col
+	}
+}
+
+// showLineage loads the lineage pane for the current table
+func (m *browserModel) showLineage() tea.Cmd {
+	if m.metadata == nil || m.table == "" {
+		return nil
+	}
+
+	m.setStatusMessage(fmt.Sprintf("Tracing lineage of %s...", m.table))
+	ctx, seq := m.startLoad()
+	ref := bigquery.TableReference{ProjectID: m.project, DatasetID: m.dataset, TableID: m.table}
+	return loadLineage(ctx, seq, m.client, ref)
+}
+
+// closeLineage returns from the lineage pane to the table detail view
+func (m *browserModel) closeLineage() {
+	m.lineage = nil
+	m.lineageSelected = 0
+	m.state = stateTableDetail
+}
+
+// jumpToLineage opens the table selected in the lineage pane, remembering
+// the current one so b can come back to it
+func (m *browserModel) jumpToLineage() tea.Cmd {
+	if m.lineageSelected < 0 || m.lineageSelected >= len(m.lineage) {
+		return nil
+	}
+	target := m.lineage[m.lineageSelected].ref
+	m.lineageTrail = append(m.lineageTrail, bigquery.TableReference{ProjectID: m.project, DatasetID: m.dataset, TableID: m.table})
+	m.closeLineage()
+	return m.openTable(target)
+}
+
+// returnFromLineage goes back to the table the last lineage jump left
+func (m *browserModel) returnFromLineage() tea.Cmd {
+	previous := m.lineageTrail[len(m.lineageTrail)-1]
+	m.lineageTrail = m.lineageTrail[:len(m.lineageTrail)-1]
+	m.cancelLoad()
+	return m.openTable(previous)
+}
+
+// openTable loads the detail view of any table, switching project and
+// dataset if needed. The table list of another dataset is loaded lazily when
+// going back to it.
+func (m *browserModel) openTable(ref bigquery.TableReference) tea.Cmd {
+	m.clearSearchState()
+	if ref.ProjectID != m.project || ref.DatasetID != m.dataset {
+		m.project = ref.ProjectID
+		m.dataset = ref.DatasetID
+		m.tables = nil
+		m.prefetcher.Clear()
+		// Cache indicators are keyed by table ID, so they don't carry across datasets
+		m.cachedMetadata.reset()
+	}
+	m.table = ref.TableID
+	m.metadata = nil
+	m.schemaNodes = nil
+	m.selectedSchema = 0
+
+	m.loading = true
+	m.state = stateLoading
+	ctx, seq := m.startLoad()
+	return loadTableMetadata(ctx, seq, m.client, m.project, m.dataset, m.table)
+}
+
+// showPartitions opens the partitions pane once the current table's
+// partitions have loaded
+func (m *browserModel) showPartitions() {
+	if m.metadata == nil || m.table == "" {
+		return
+	}
+	switch {
+	case !m.metadata.Partitioned():
+		m.setStatusMessage(m.table + " is not partitioned")
+	case m.partitionsErr != nil:
+		errorMessage := m.partitionsErr.Error()
+		if bqsErr, ok := m.partitionsErr.(*errors.BQSError); ok {
+			errorMessage = bqsErr.UserFriendlyMessage()
+		}
+		m.setStatusMessage(fmt.Sprintf("✗ %s", errorMessage))
+	case m.partitions == nil:
+		m.setStatusMessage(fmt.Sprintf("Loading partitions of %s...", m.table))
+	default:
+		m.statusMessage = ""
+		m.partitionSelected = 0
+		m.state = statePartitions
+	}
+}
+
+// closePartitions returns from the partitions pane to the table detail view.
+// The partitions stay loaded for the histogram.
+func (m *browserModel) closePartitions() {
+	m.state = stateTableDetail
+}
+
+// partitionsHeight returns the number of partitions visible in the partitions pane
+func (m *browserModel) partitionsHeight() int {
+	height := m.height - config.PartitionsPadding
+	if height < config.MinTableHeight {
+		height = config.MinTableHeight
+	}
+	return height
+}
+
+// showActivity loads the activity pane for the current table. The jobs view
+// bills the bytes it scans, so it's only queried on request.
+func (m *browserModel) showActivity() tea.Cmd {
+	if m.metadata == nil || m.table == "" {
+		return nil
+	}
+
+	m.setStatusMessage(fmt.Sprintf("Loading jobs of %s...", m.table))
+	ctx, seq := m.startLoad()
+	ref := bigquery.TableReference{ProjectID: m.project, DatasetID: m.dataset, TableID: m.table}
+	return loadActivity(ctx, seq, m.client, ref)
+}
+
+// closeActivity returns from the activity pane to the table detail view
+func (m *browserModel) closeActivity() {
+	m.activity = nil
+	m.activitySelected = 0
+	m.state = stateTableDetail
+}
+
+// activityHeight returns the number of jobs visible in the activity pane
+func (m *browserModel) activityHeight() int {
+	height := m.height - config.ActivityPadding
+	if height < config.MinTableHeight {
+		height = config.MinTableHeight
+	}
+	return height
+}
+
+// closeViewSQL returns from the SQL pane to the table detail view
+func (m *browserModel) closeViewSQL() {
+	m.sqlLines = nil
+	m.sqlOffset = 0
+	m.state = stateTableDetail
+}
+
+// viewSQLHeight returns the number of query lines visible in the SQL pane
+func (m *browserModel) viewSQLHeight() int {
+	height := m.height - config.ViewSQLPadding
+	if height < config.MinTableHeight {
+		height = config.MinTableHeight
+	}
+	return height
+}
+
+// warmCache bulk-loads metadata and schemas for every table in the current
+// dataset so they open instantly
+func (m *browserModel) warmCache() tea.Cmd {
+	if m.state != stateTableList || m.dataset == "" {
+		return nil
+	}
+
+	m.setStatusMessage(fmt.Sprintf("Caching schemas for %s.%s...", m.project, m.dataset))
+	ctx, seq := m.startLoad()
+	return warmDatasetCache(ctx, seq, m.client, m.project, m.dataset)
+}
+
+// toggleStale dims or undims the tables matching the default stale
+// criteria, looking for them the first time
+func (m *browserModel) toggleStale() tea.Cmd {
+	if m.state != stateTableList || m.dataset == "" {
+		return nil
+	}
+	if m.showStale {
+		m.showStale = false
+		m.setStatusMessage("Stale tables no longer dimmed")
+		return nil
+	}
+	if m.staleTables != nil {
+		m.showStale = true
+		m.setStatusMessage(fmt.Sprintf("%d stale tables dimmed", len(m.staleTables)))
+		return nil
+	}
+
+	m.setStatusMessage(fmt.Sprintf("Looking for stale tables in %s.%s...", m.project, m.dataset))
+	ctx, seq := m.startLoad()
+	return loadStaleTables(ctx, seq, m.client, m.project, m.dataset)
+}
+
+// staleSummary describes the outcome of looking for stale tables
+func (m *browserModel) staleSummary(report *bigquery.StaleReport) string {
+	criteria := bigquery.DefaultStaleCriteria()
+	summary := fmt.Sprintf("%d of %d tables unmodified for %s and unread for %s dimmed",
+		len(report.Tables), report.Checked, formatAge(criteria.Unmodified), formatAge(criteria.Unqueried))
+	if report.HistoryErr != nil {
+		// Typically a missing bigquery.jobs.listAll
+		summary = fmt.Sprintf("%d of %d tables unmodified for %s dimmed; job history unavailable",
+			len(report.Tables), report.Checked, formatAge(criteria.Unmodified))
+	}
+	return summary
+}
+
+// closeDataset returns from a dataset's table list to the project's dataset list
+func (m *browserModel) closeDataset() {
+	m.clearSearchState()
+	m.loading = false
+	m.progress = loadProgress{}
+	m.dataset = ""
+	m.table = ""
+	m.tables = nil
+	m.prefetcher.Clear()
+	m.state = stateDatasetList
+	// Opening the dataset cached its table list, so its count can now show
+	m.refreshDatasetDetails()
+}
+
 // updateTableRows populates the Bubbletea table component with current table data
 func (m *browserModel) updateTableRows() {
 	// Use filtered tables if searching, otherwise use all tables
@@ -356,18 +1270,82 @@ func (m *browserModel) updateTableRows() {
 
 		// Add cache status indicator with color
 		cacheStatus := ""
-		if _, isCached := m.cachedMetadata[tableID]; isCached {
+		if _, isCached := m.cachedMetadata.get(tableID); isCached {
 			cacheStatus = "✓" // Cached - will be colored green in the view
 		}
 
+		// Snapshots and clones are listed under their base table
+		name, tableType := m.tableRowName(tableID), tbl.Type
+		if snapshot, ok := m.snapshots[tableID]; ok && snapshot.Clone {
+			tableType = "CLONE"
+		}
+
 		// Always show basic, fast info - creation time is always available
 		created := bigquery.FormatTime(tbl.CreationTime)
-		rows[i] = table.Row{tableID, tbl.Type, created, cacheStatus}
+		rows[i] = table.Row{name, tableType, created, cacheStatus}
 	}
 
 	m.tableModel.SetRows(rows)
 }
 
+// tableRowName is the name a table is listed under in the table list
+func (m *browserModel) tableRowName(tableID string) string {
+	if _, ok := m.snapshots[tableID]; ok {
+		return "└ " + tableID
+	}
+	return tableID
+}
+
+// prefetchTables queues the metadata of the listed tables not cached yet for
+// prefetching, the rows on screen first
+func (m *browserModel) prefetchTables() {
+	if m.prefetcher == nil {
+		return
+	}
+	var rest []bigquery.TableReference
+	for _, tbl := range m.tables {
+		tableID := tbl.TableID
+		if tableID == "" {
+			tableID = tbl.TableReference.TableID
+		}
+		if _, isCached := m.cachedMetadata.get(tableID); !isCached {
+			rest = append(rest, bigquery.TableReference{ProjectID: m.project, DatasetID: m.dataset, TableID: tableID})
+		}
+	}
+	m.prefetcher.Prefetch(m.visibleTableRefs(), rest)
+}
+
+// visibleTableRefs returns the uncached tables of the rows that may be on
+// screen. The table component doesn't expose its scroll offset, so that's
+// every row within a screen's height of the cursor, nearest first.
+func (m *browserModel) visibleTableRefs() []bigquery.TableReference {
+	tablesToShow := m.tables
+	if m.ui.Search.FilteredTables != nil {
+		tablesToShow = m.ui.Search.FilteredTables
+	}
+
+	var refs []bigquery.TableReference
+	add := func(i int) {
+		if i < 0 || i >= len(tablesToShow) {
+			return
+		}
+		tableID := tablesToShow[i].TableID
+		if tableID == "" {
+			tableID = tablesToShow[i].TableReference.TableID
+		}
+		if _, isCached := m.cachedMetadata.get(tableID); !isCached {
+			refs = append(refs, bigquery.TableReference{ProjectID: m.project, DatasetID: m.dataset, TableID: tableID})
+		}
+	}
+	cursor := m.tableModel.Cursor()
+	add(cursor)
+	for offset := 1; offset < m.tableModel.Height(); offset++ {
+		add(cursor + offset)
+		add(cursor - offset)
+	}
+	return refs
+}
+
 // checkCacheStatus scans the underlying cache to see which tables are already cached
 func (m *browserModel) checkCacheStatus() {
 	if len(m.tables) == 0 {
@@ -387,12 +1365,9 @@ func (m *browserModel) checkCacheStatus() {
 			// Mark this table as having cached metadata
 			// We don't load the actual metadata yet (lazy loading)
 			// but we mark it as cached for UI purposes
-			if m.cachedMetadata == nil {
-				m.cachedMetadata = make(map[string]*bigquery.TableMetadata)
+			if m.cachedMetadata.markCached(tableID) {
+				cacheUpdated = true
 			}
-			// Use a placeholder to indicate it's cached
-			m.cachedMetadata[tableID] = &bigquery.TableMetadata{}
-			cacheUpdated = true
 		}
 	}
 
@@ -406,7 +1381,22 @@ func (m *browserModel) checkCacheStatus() {
 func (m *browserModel) copyCurrentTable() {
 	var tableID string
 	
-	if m.state == stateTableList && len(m.tables) > 0 {
+	if m.state == stateDatasetDetail && m.datasetMetadata != nil {
+		ref := m.datasetMetadata.DatasetReference
+		tableID = ref.ProjectID + "." + ref.DatasetID
+	} else if m.state == stateProjectList && len(m.projects) > 0 {
+		// Get selected project from project list
+		selectedIdx := m.projectModel.Cursor()
+		if selectedIdx >= 0 && selectedIdx < len(m.projects) {
+			tableID = m.projects[selectedIdx].ProjectReference.ProjectID
+		}
+	} else if m.state == stateDatasetList && len(m.datasets) > 0 {
+		// Get selected dataset from dataset list
+		selectedIdx := m.datasetModel.Cursor()
+		if selectedIdx >= 0 && selectedIdx < len(m.datasets) {
+			tableID = m.project + "." + m.datasets[selectedIdx].DatasetReference.DatasetID
+		}
+	} else if m.state == stateTableList && len(m.tables) > 0 {
 		// Get selected table from table list
 		selectedIdx := m.tableModel.Cursor()
 		if selectedIdx >= 0 && selectedIdx < len(m.tables) {
@@ -422,6 +1412,34 @@ func (m *browserModel) copyCurrentTable() {
 	} else if m.state == stateTableDetail && m.table != "" {
 		// Use current table in detail view
 		tableID = m.project + "." + m.dataset + "." + m.table
+	} else if m.state == stateLineage && m.lineageSelected < len(m.lineage) {
+		tableID = m.lineage[m.lineageSelected].ref.String()
+	} else if m.state == statePartitions && m.partitionSelected < len(m.partitions) {
+		// Partition decorator, e.g. project.dataset.table$20261001
+		tableID = m.partitionsRef.String()
+		if id := m.partitions[m.partitionSelected].PartitionID; id != "" {
+			tableID += "$" + id
+		}
+	} else if m.state == stateActivity && m.activitySelected < len(m.activity) {
+		tableID = m.activity[m.activitySelected].JobID
+	} else if m.state == statePreview {
+		value, column, ok := m.selectedPreviewValue()
+		if !ok {
+			return
+		}
+		if err := utils.CopyToClipboard(bigquery.FormatCellValue(value)); err != nil {
+			m.setStatusMessage("Clipboard not available (install xclip/xsel)")
+		} else {
+			m.setStatusMessage("Copied " + column + " value")
+		}
+		return
+	} else if m.state == stateViewSQL && m.metadata != nil {
+		if err := utils.CopyToClipboard(m.metadata.DefinitionQuery()); err != nil {
+			m.setStatusMessage("Clipboard not available (install xclip/xsel)")
+		} else {
+			m.setStatusMessage("Copied view SQL")
+		}
+		return
 	}
 	
 	if tableID != "" {
@@ -434,6 +1452,44 @@ func (m *browserModel) copyCurrentTable() {
 	}
 }
 
+// startLoad cancels any in-flight load and returns the context and sequence
+// number for a new one. Prefetching pauses until the load finishes.
+func (m *browserModel) startLoad() (context.Context, int) {
+	m.cancelLoad()
+	m.prefetcher.Pause()
+
+	ctx, cancel := context.WithCancel(m.baseContext())
+	m.loadCancel = cancel
+	return ctx, m.loadSeq
+}
+
+// baseContext is the parent context of the browser's loads
+func (m *browserModel) baseContext() context.Context {
+	if m.ctx == nil {
+		return context.Background()
+	}
+	return m.ctx
+}
+
+// cancelLoad aborts the in-flight load, if any, and invalidates its pending result
+func (m *browserModel) cancelLoad() {
+	if m.loadCancel != nil {
+		m.loadCancel()
+		m.loadCancel = nil
+	}
+	m.loadSeq++
+	m.prefetcher.Resume()
+}
+
+// finishLoad releases the context of a load whose result has arrived
+func (m *browserModel) finishLoad() {
+	if m.loadCancel != nil {
+		m.loadCancel()
+		m.loadCancel = nil
+	}
+	m.prefetcher.Resume()
+}
+
 // setStatusMessage sets a temporary status message with timeout
 func (m *browserModel) setStatusMessage(message string) {
 	m.statusMessage = message
@@ -484,7 +1540,7 @@ func (m *browserModel) exportTable() (tea.Model, tea.Cmd) {
 	}
 
 	// Start async export
-	return m, exportTableMetadata(m.client, m.project, m.dataset, tableID, tableMetadata)
+	return m, exportTableMetadata(m.ctx, m.client, m.project, m.dataset, tableID, tableMetadata)
 }
 
 // clearSearchState resets all search-related state
@@ -495,7 +1551,33 @@ func (m *browserModel) clearSearchState() {
 // selectCurrentSearchResult selects the currently highlighted item from search results
 // and maps it back to the full list for proper highlighting
 func (m *browserModel) selectCurrentSearchResult() {
-	if m.state == stateTableList && m.ui.Search.FilteredTables != nil && len(m.ui.Search.FilteredTables) > 0 {
+	if m.state == stateProjectList && len(m.ui.Search.FilteredProjects) > 0 {
+		selectedIdx := m.projectModel.Cursor()
+		if selectedIdx >= 0 && selectedIdx < len(m.ui.Search.FilteredProjects) {
+			selectedID := m.ui.Search.FilteredProjects[selectedIdx].ProjectReference.ProjectID
+
+			// Find this project in the full list and set cursor there
+			for i, p := range m.projects {
+				if p.ProjectReference.ProjectID == selectedID {
+					m.projectModel.SetCursor(i)
+					break
+				}
+			}
+		}
+	} else if m.state == stateDatasetList && len(m.ui.Search.FilteredDatasets) > 0 {
+		selectedIdx := m.datasetModel.Cursor()
+		if selectedIdx >= 0 && selectedIdx < len(m.ui.Search.FilteredDatasets) {
+			selectedID := m.ui.Search.FilteredDatasets[selectedIdx].DatasetReference.DatasetID
+
+			// Find this dataset in the full list and set cursor there
+			for i, ds := range m.datasets {
+				if ds.DatasetReference.DatasetID == selectedID {
+					m.datasetModel.SetCursor(i)
+					break
+				}
+			}
+		}
+	} else if m.state == stateTableList && m.ui.Search.FilteredTables != nil && len(m.ui.Search.FilteredTables) > 0 {
 		// Get the currently selected item from filtered results
 		selectedIdx := m.tableModel.Cursor()
 		if selectedIdx >= 0 && selectedIdx < len(m.ui.Search.FilteredTables) {
@@ -542,7 +1624,7 @@ func (m *browserModel) handleSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if key == "escape" || key == "esc" || msg.Type == tea.KeyEscape {
 		// Exit search mode and clear all search state
 		m.clearSearchState()
-		m.updateTableRows()
+		m.updateRows()
 		return m, nil
 	}
 	
@@ -551,20 +1633,20 @@ func (m *browserModel) handleSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// fzf-style: select current item and return to full view with selection
 		m.selectCurrentSearchResult()
 		m.clearSearchState()
-		m.updateTableRows()
+		m.updateRows()
 		return m, nil
 		
 	case "ctrl+c", "ctrl+g":
 		// Exit search mode and clear all search state
 		m.clearSearchState()
-		m.updateTableRows()
+		m.updateRows()
 		return m, nil
 		
 	case "backspace":
 		if len(m.ui.Search.Query) > 0 {
 			m.ui.Search.Query = m.ui.Search.Query[:len(m.ui.Search.Query)-1]
 			m.filterTables()
-			m.updateTableRows()
+			m.updateRows()
 		}
 		return m, nil
 		
@@ -580,7 +1662,7 @@ func (m *browserModel) handleSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if len(key) == 1 { // Only single printable characters (including space)
 			m.ui.Search.Query += key
 			m.filterTables()
-			m.updateTableRows()
+			m.updateRows()
 		}
 		return m, nil
 	}
@@ -592,6 +1674,16 @@ func (m *browserModel) handleNavigation(direction string) {
 	case "up":
 		if m.state == stateTableDetail && m.selectedSchema > 0 {
 			m.selectedSchema--
+		} else if m.state == stateViewSQL && m.sqlOffset > 0 {
+			m.sqlOffset--
+		} else if m.state == statePreview && m.previewRow > 0 {
+			m.previewRow--
+		} else if m.state == stateLineage && m.lineageSelected > 0 {
+			m.lineageSelected--
+		} else if m.state == statePartitions && m.partitionSelected > 0 {
+			m.partitionSelected--
+		} else if m.state == stateActivity && m.activitySelected > 0 {
+			m.activitySelected--
 		}
 		// For table list, navigation is handled by the table model automatically
 		
@@ -605,18 +1697,46 @@ func (m *browserModel) handleNavigation(direction string) {
 			if m.selectedSchema < maxNodes-1 {
 				m.selectedSchema++
 			}
+		} else if m.state == stateViewSQL && m.sqlOffset < len(m.sqlLines)-m.viewSQLHeight() {
+			m.sqlOffset++
+		} else if m.state == statePreview && m.previewRow < len(m.preview.Rows)-1 {
+			m.previewRow++
+		} else if m.state == stateLineage && m.lineageSelected < len(m.lineage)-1 {
+			m.lineageSelected++
+		} else if m.state == statePartitions && m.partitionSelected < len(m.partitions)-1 {
+			m.partitionSelected++
+		} else if m.state == stateActivity && m.activitySelected < len(m.activity)-1 {
+			m.activitySelected++
 		}
 		// For table list, navigation is handled by the table model automatically
 		
 	case "top":
-		if m.state == stateTableList {
+		if m.state == stateProjectList {
+			m.projectModel.GotoTop()
+		} else if m.state == stateDatasetList {
+			m.datasetModel.GotoTop()
+		} else if m.state == stateTableList {
 			m.tableModel.GotoTop()
 		} else if m.state == stateTableDetail {
 			m.selectedSchema = 0
+		} else if m.state == stateViewSQL {
+			m.sqlOffset = 0
+		} else if m.state == statePreview {
+			m.previewRow = 0
+		} else if m.state == stateLineage {
+			m.lineageSelected = 0
+		} else if m.state == statePartitions {
+			m.partitionSelected = 0
+		} else if m.state == stateActivity {
+			m.activitySelected = 0
 		}
 		
 	case "bottom":
-		if m.state == stateTableList {
+		if m.state == stateProjectList {
+			m.projectModel.GotoBottom()
+		} else if m.state == stateDatasetList {
+			m.datasetModel.GotoBottom()
+		} else if m.state == stateTableList {
 			m.tableModel.GotoBottom()
 		} else if m.state == stateTableDetail {
 			maxNodes := len(m.schemaNodes)
@@ -626,6 +1746,16 @@ func (m *browserModel) handleNavigation(direction string) {
 			if maxNodes > 0 {
 				m.selectedSchema = maxNodes - 1
 			}
+		} else if m.state == stateViewSQL && len(m.sqlLines) > m.viewSQLHeight() {
+			m.sqlOffset = len(m.sqlLines) - m.viewSQLHeight()
+		} else if m.state == statePreview && len(m.preview.Rows) > 0 {
+			m.previewRow = len(m.preview.Rows) - 1
+		} else if m.state == stateLineage && len(m.lineage) > 0 {
+			m.lineageSelected = len(m.lineage) - 1
+		} else if m.state == statePartitions && len(m.partitions) > 0 {
+			m.partitionSelected = len(m.partitions) - 1
+		} else if m.state == stateActivity && len(m.activity) > 0 {
+			m.activitySelected = len(m.activity) - 1
 		}
 	}
 }
@@ -635,10 +1765,33 @@ func (m *browserModel) filterTables() {
 	if m.ui.Search.Query == "" {
 		m.ui.Search.FilteredTables = nil
 		m.ui.Search.FilteredNodes = nil
+		m.ui.Search.FilteredDatasets = nil
+		m.ui.Search.FilteredProjects = nil
 		return
 	}
 	
 	query := strings.ToLower(m.ui.Search.Query)
+
+	// Filter projects if in project list view, matching ID or friendly name
+	if m.ui.Search.Context == SearchProjects && len(m.projects) > 0 {
+		m.ui.Search.FilteredProjects = make([]bigquery.ProjectInfo, 0)
+		for _, p := range m.projects {
+			if strings.Contains(strings.ToLower(p.ProjectReference.ProjectID), query) ||
+				strings.Contains(strings.ToLower(p.FriendlyName), query) {
+				m.ui.Search.FilteredProjects = append(m.ui.Search.FilteredProjects, p)
+			}
+		}
+	}
+
+	// Filter datasets if in dataset list view
+	if m.ui.Search.Context == SearchDatasets && len(m.datasets) > 0 {
+		m.ui.Search.FilteredDatasets = make([]bigquery.DatasetInfo, 0)
+		for _, ds := range m.datasets {
+			if strings.Contains(strings.ToLower(ds.DatasetReference.DatasetID), query) {
+				m.ui.Search.FilteredDatasets = append(m.ui.Search.FilteredDatasets, ds)
+			}
+		}
+	}
 	
 	// Filter tables if in table list view
 	if m.ui.Search.Context == SearchTables && len(m.tables) > 0 {
@@ -27,12 +27,21 @@ func NewKeyDispatcher() *KeyDispatcher {
 			"G":        &navigationHandler{key: "G"},
 			"y":        &yankHandler{},
 			"e":        &exportHandler{},
+			"i":        &infoHandler{},
+			"v":        &sqlHandler{},
+			"w":        &warmHandler{},
+			"p":        &previewHandler{},
+			"L":        &lineageHandler{},
+			"P":        &partitionsHandler{},
+			"A":        &activityHandler{},
+			"S":        &staleHandler{},
 			"up":       &navigationHandler{key: "up"},
 			"k":        &navigationHandler{key: "up"},
 			"down":     &navigationHandler{key: "down"},
 			"j":        &navigationHandler{key: "down"},
 			"enter":    &enterHandler{},
 			"space":    &expandHandler{},
+			" ":        &expandHandler{},
 			"right":    &expandHandler{},
 			"l":        &expandHandler{},
 			"left":     &collapseHandler{},
@@ -80,6 +89,7 @@ type quitHandler struct{}
 
 func (h *quitHandler) HandleKey(m *browserModel, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	m.lastKey = ""
+	m.cancelLoad()
 	return m, tea.Quit
 }
 
@@ -103,9 +113,13 @@ func (h *helpHandler) HandleKey(m *browserModel, msg tea.KeyMsg) (tea.Model, tea
 type searchHandler struct{}
 
 func (h *searchHandler) HandleKey(m *browserModel, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	// Enter search mode (for table list and table detail)
-	if (m.state == stateTableList || m.state == stateTableDetail) && m.ui.IsNormalMode() {
-		if m.state == stateTableList {
+	// Enter search mode (for project list, dataset list, table list and table detail)
+	if (m.state == stateProjectList || m.state == stateDatasetList || m.state == stateTableList || m.state == stateTableDetail) && m.ui.IsNormalMode() {
+		if m.state == stateProjectList {
+			m.ui.EnterSearchMode(SearchProjects)
+		} else if m.state == stateDatasetList {
+			m.ui.EnterSearchMode(SearchDatasets)
+		} else if m.state == stateTableList {
 			m.ui.EnterSearchMode(SearchTables)
 		} else {
 			m.ui.EnterSearchMode(SearchSchema)
@@ -188,11 +202,138 @@ func (h *exportHandler) HandleKey(m *browserModel, msg tea.KeyMsg) (tea.Model, t
 	return m.exportTable()
 }
 
+// infoHandler opens the dataset detail pane (i key)
+type infoHandler struct{}
+
+func (h *infoHandler) HandleKey(m *browserModel, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.lastKey = ""
+	return m, m.showDatasetDetail()
+}
+
+// warmHandler bulk-caches the schemas of the current dataset (w key)
+type warmHandler struct{}
+
+func (h *warmHandler) HandleKey(m *browserModel, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.lastKey = ""
+	return m, m.warmCache()
+}
+
+// previewHandler toggles the table preview grid (p key)
+type previewHandler struct{}
+
+func (h *previewHandler) HandleKey(m *browserModel, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.lastKey = ""
+	if m.state == stateTableDetail {
+		return m, m.showPreview()
+	}
+	if m.state == statePreview {
+		m.closePreview()
+	}
+	return m, nil
+}
+
+// sqlHandler toggles the view SQL pane (v key)
+type sqlHandler struct{}
+
+func (h *sqlHandler) HandleKey(m *browserModel, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.lastKey = ""
+	if m.state == stateTableDetail {
+		m.showViewSQL()
+	} else if m.state == stateViewSQL {
+		m.closeViewSQL()
+	}
+	return m, nil
+}
+
+// lineageHandler toggles the lineage pane (L key)
+type lineageHandler struct{}
+
+func (h *lineageHandler) HandleKey(m *browserModel, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.lastKey = ""
+	if m.state == stateTableDetail {
+		return m, m.showLineage()
+	}
+	if m.state == stateLineage {
+		m.closeLineage()
+	}
+	return m, nil
+}
+
+// partitionsHandler toggles the partitions pane (P key)
+type partitionsHandler struct{}
+
+func (h *partitionsHandler) HandleKey(m *browserModel, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.lastKey = ""
+	if m.state == stateTableDetail {
+		m.showPartitions()
+	} else if m.state == statePartitions {
+		m.closePartitions()
+	}
+	return m, nil
+}
+
+// activityHandler toggles the activity pane (A key)
+type activityHandler struct{}
+
+func (h *activityHandler) HandleKey(m *browserModel, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.lastKey = ""
+	if m.state == stateTableDetail {
+		return m, m.showActivity()
+	}
+	if m.state == stateActivity {
+		m.closeActivity()
+	}
+	return m, nil
+}
+
+// staleHandler toggles dimming of stale tables in the table list (S key)
+type staleHandler struct{}
+
+func (h *staleHandler) HandleKey(m *browserModel, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.lastKey = ""
+	return m, m.toggleStale()
+}
+
 // enterHandler handles enter key
 type enterHandler struct{}
 
 func (h *enterHandler) HandleKey(m *browserModel, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	m.lastKey = ""
+	if m.state == statePreview {
+		m.previewExpanded = !m.previewExpanded
+		return m, nil
+	}
+	if m.state == stateLineage {
+		return m, m.jumpToLineage()
+	}
+	if m.state == stateProjectList && len(m.projects) > 0 {
+		selectedIdx := m.projectModel.Cursor()
+
+		// Use filtered projects if searching, otherwise use all projects
+		projectsToShow := m.projects
+		if m.ui.Search.FilteredProjects != nil {
+			projectsToShow = m.ui.Search.FilteredProjects
+		}
+
+		if selectedIdx >= 0 && selectedIdx < len(projectsToShow) {
+			return m, m.openProject(projectsToShow[selectedIdx].ProjectReference.ProjectID)
+		}
+		return m, nil
+	}
+	if m.state == stateDatasetList && len(m.datasets) > 0 {
+		selectedIdx := m.datasetModel.Cursor()
+
+		// Use filtered datasets if searching, otherwise use all datasets
+		datasetsToShow := m.datasets
+		if m.ui.Search.FilteredDatasets != nil {
+			datasetsToShow = m.ui.Search.FilteredDatasets
+		}
+
+		if selectedIdx >= 0 && selectedIdx < len(datasetsToShow) {
+			return m, m.openDataset(datasetsToShow[selectedIdx].DatasetReference.DatasetID)
+		}
+		return m, nil
+	}
 	if m.state == stateTableList && len(m.tables) > 0 {
 		// Get selected table from the table model cursor
 		selectedIdx := m.tableModel.Cursor()
@@ -216,17 +357,19 @@ func (h *enterHandler) HandleKey(m *browserModel, msg tea.KeyMsg) (tea.Model, te
 			m.clearSearchState()
 
 			// Check if we have real cached metadata (not just a placeholder)
-			if cached, exists := m.cachedMetadata[tableID]; exists && cached != nil && cached.Schema != nil {
+			if cached, exists := m.cachedMetadata.get(tableID); exists && cached != nil && cached.Schema != nil {
 				// Use cached data immediately (real metadata, not placeholder)
 				m.metadata = cached
 				m.state = stateTableDetail
+				annotateCmd := m.annotateTableDetail()
 				m.buildSchemaTree()
-				return m, nil
+				return m, annotateCmd
 			} else {
 				// Load metadata and cache it (this will be fast if persistently cached)
 				m.loading = true
 				m.state = stateLoading
-				return m, loadTableMetadata(m.client, m.project, m.dataset, tableID)
+				ctx, seq := m.startLoad()
+				return m, loadTableMetadata(ctx, seq, m.client, m.project, m.dataset, tableID)
 			}
 		}
 	}
@@ -238,6 +381,15 @@ type expandHandler struct{}
 
 func (h *expandHandler) HandleKey(m *browserModel, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	m.lastKey = ""
+	// In the preview grid space expands the cell, l/→ scroll right
+	if m.state == statePreview {
+		if msg.String() == " " || msg.String() == "space" {
+			m.previewExpanded = !m.previewExpanded
+		} else {
+			m.movePreviewColumn(1)
+		}
+		return m, nil
+	}
 	// Expand/collapse schema nodes
 	if m.state == stateTableDetail && len(m.schemaNodes) > 0 {
 		node := m.schemaNodes[m.selectedSchema]
@@ -254,6 +406,10 @@ type collapseHandler struct{}
 
 func (h *collapseHandler) HandleKey(m *browserModel, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	m.lastKey = ""
+	if m.state == statePreview {
+		m.movePreviewColumn(-1)
+		return m, nil
+	}
 	// Collapse current node or go to parent
 	if m.state == stateTableDetail && len(m.schemaNodes) > 0 {
 		node := m.schemaNodes[m.selectedSchema]
@@ -272,6 +428,64 @@ type backHandler struct{}
 
 func (h *backHandler) HandleKey(m *browserModel, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	m.lastKey = ""
+	if m.state == stateViewSQL {
+		m.closeViewSQL()
+		return m, nil
+	}
+	if m.state == statePreview {
+		m.closePreview()
+		return m, nil
+	}
+	if m.state == stateLineage {
+		m.closeLineage()
+		return m, nil
+	}
+	if m.state == statePartitions {
+		m.closePartitions()
+		return m, nil
+	}
+	if m.state == stateActivity {
+		m.closeActivity()
+		return m, nil
+	}
+	if (m.state == stateTableDetail || m.state == stateLoading || m.state == stateError) && len(m.lineageTrail) > 0 {
+		// Retrace a jump made from the lineage pane
+		return m, m.returnFromLineage()
+	}
+	if m.state == stateDatasetDetail {
+		m.state = m.datasetDetailReturn
+		m.datasetMetadata = nil
+		return m, nil
+	}
+	if m.state == stateLoading && m.table == "" && m.projectLevel && m.dataset != "" {
+		// Abandon a slow table list load and return to the dataset list
+		m.cancelLoad()
+		m.closeDataset()
+		return m, nil
+	}
+	if (m.state == stateTableList || m.state == stateError) && m.projectLevel && m.dataset != "" && m.table == "" {
+		// Failed or loaded dataset: go back to pick another one
+		m.closeDataset()
+		return m, nil
+	}
+	if m.state == stateLoading && m.dataset == "" && m.homeLevel && m.project != "" {
+		// Abandon a slow dataset list load and return to the project list
+		m.cancelLoad()
+		m.closeProject()
+		return m, nil
+	}
+	if (m.state == stateDatasetList || m.state == stateError) && m.homeLevel && m.project != "" && m.dataset == "" {
+		m.closeProject()
+		return m, nil
+	}
+	if m.state == stateLoading && len(m.tables) > 0 {
+		// Abandon a slow metadata load and return to the table list
+		m.cancelLoad()
+		m.loading = false
+		m.state = stateTableList
+		m.table = ""
+		return m, nil
+	}
 	if m.state == stateTableDetail {
 		// Clear search state when going back to table list
 		m.clearSearchState()
@@ -280,6 +494,10 @@ func (h *backHandler) HandleKey(m *browserModel, msg tea.KeyMsg) (tea.Model, tea
 		m.metadata = nil
 		m.schemaNodes = nil
 		m.selectedSchema = 0
+		if len(m.tables) == 0 {
+			// Reached through lineage jumps; the dataset's tables aren't loaded yet
+			return m, m.openDataset(m.dataset)
+		}
 	}
 	return m, nil
 }
\ No newline at end of file
@@ -1,14 +1,17 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 	
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/bubbles/table"
 
 	"bqs/internal/bigquery"
+	"bqs/internal/config"
 	"bqs/internal/errors"
 	"bqs/internal/utils"
 )
@@ -18,8 +21,16 @@ type browserState int
 
 const (
 	stateLoading browserState = iota
+	stateProjectList
+	stateDatasetList
+	stateDatasetDetail
 	stateTableList
 	stateTableDetail
+	stateViewSQL
+	statePreview
+	stateLineage
+	statePartitions
+	stateActivity
 	stateError
 	stateHelp
 )
@@ -40,32 +51,93 @@ type browserModel struct {
 	table   string
 	client  *bigquery.Client
 
-	// Table list state
+	// Cancellation of in-flight loads. ctx is cancelled when the browser exits;
+	// loadSeq identifies the current load so results of abandoned ones are dropped.
+	ctx        context.Context
+	loadCancel context.CancelFunc
+	loadSeq    int
+
+	// Project list state, used when started without a project
+	homeLevel    bool     // Started without a project; b returns to the project list
+	favorites    []string // Projects pinned to the top of the list
+	projects     []bigquery.ProjectInfo
+	projectModel table.Model
+
+	// Dataset list state, used when browsing a whole project
+	projectLevel bool // Started without a dataset; b returns to the dataset list
+	datasets     []bigquery.DatasetInfo
+	datasetModel table.Model
+
+	// Dataset detail state; datasetDetailReturn is the list the pane was opened from
+	datasetMetadata     *bigquery.DatasetMetadata
+	datasetDetailReturn browserState
+
+	// Table list state; snapshots holds the snapshots and clones listed
+	// under their base table, by table ID
 	tables     []bigquery.TableInfo
 	tableModel table.Model // Bubbletea table component
+	snapshots  map[string]bigquery.TableSnapshot
 
 	// Table detail state
 	metadata *bigquery.TableMetadata
 
+	// View SQL pane state; sqlLines holds the highlighted query, one entry per line
+	sqlLines  []string
+	sqlOffset int
+
+	// Preview grid state; previewExpanded shows the selected cell as indented JSON
+	preview         *bigquery.TablePreview
+	previewRow      int
+	previewCol      int
+	previewExpanded bool
+
+	// Lineage pane state; lineageTrail holds the tables jumped from, newest last
+	lineage         []lineageEntry
+	lineageSelected int
+	lineageTrail    []bigquery.TableReference
+
+	// Partitions of the current table, newest first, loaded in the background
+	// for the histogram; partitionsRef is the table they're loaded for
+	partitions        []bigquery.PartitionInfo
+	partitionsRef     bigquery.TableReference
+	partitionsErr     error
+	partitionSelected int
+
+	// Stale table toggle: table IDs matching the default stale criteria,
+	// loaded on the first toggle per dataset, and whether they're dimmed
+	staleTables map[string]bool
+	showStale   bool
+
+	// Activity pane state: recent jobs that read or wrote the current table,
+	// newest first
+	activity         []bigquery.TableJob
+	activitySelected int
+
 	// Schema tree state
 	schemaNodes    []schemaNode
 	selectedSchema int
 	expandedNodes  map[string]bool
 
+	// Source columns of the current view's top-level columns, keyed by name
+	columnLineage map[string][]bigquery.ColumnSource
+
 	// Consolidated UI interaction state
 	ui UIState
 	
 	// Key handling
 	keyDispatcher *KeyDispatcher
 
-	// Cache state (lazy loading)
-	cachedMetadata map[string]*bigquery.TableMetadata
+	// Cache state (lazy loading); the prefetcher fills the cache in the
+	// background and is nil when prefetching is off
+	cachedMetadata *cachedTables
+	prefetcher     *bigquery.Prefetcher
 
 	// UI rendering state
-	loading bool
-	err     error
-	width   int
-	height  int
+	loading  bool
+	progress loadProgress // Paging progress while the table list loads
+	err      error
+	width    int
+	height   int
 	
 	// Vim-style navigation state
 	lastKey string // For tracking key sequences like 'gg'
@@ -78,12 +150,27 @@ type browserModel struct {
 	previousState browserState // Store previous state when showing help
 }
 
+// loadProgress tracks how much of a paged table list has arrived
+type loadProgress struct {
+	loaded int
+	total  int // 0 when the backend can't estimate the total
+}
+
+// lineageEntry is one table in the lineage pane: a direct source of the
+// current table (upstream) or a view reading it
+type lineageEntry struct {
+	ref      bigquery.TableReference
+	kind     string // Table type, "" if unknown
+	upstream bool
+}
+
 // schemaNode represents a node in the schema tree
 type schemaNode struct {
 	Field       bigquery.SchemaField
 	Path        string // Unique path for tracking expansion state
 	Level       int    // Nesting level for indentation
 	HasChildren bool
+	Sources     []bigquery.ColumnSource // Columns a view column is computed from
 }
 
 // SearchContext represents what type of content is being searched
@@ -92,6 +179,8 @@ type SearchContext int
 const (
 	SearchTables SearchContext = iota
 	SearchSchema
+	SearchDatasets
+	SearchProjects
 )
 
 // UIState consolidates all user interface interaction state
@@ -132,8 +221,10 @@ type SearchState struct {
 	Query          string
 	Context        SearchContext
 	SelectedIndex  int
-	FilteredTables []bigquery.TableInfo
-	FilteredNodes  []schemaNode
+	FilteredTables   []bigquery.TableInfo
+	FilteredNodes    []schemaNode
+	FilteredDatasets []bigquery.DatasetInfo
+	FilteredProjects []bigquery.ProjectInfo
 }
 
 // Clear resets the search state
@@ -143,6 +234,8 @@ func (s *SearchState) Clear() {
 	s.SelectedIndex = 0
 	s.FilteredTables = nil
 	s.FilteredNodes = nil
+	s.FilteredDatasets = nil
+	s.FilteredProjects = nil
 }
 
 // IsEmpty returns true if no search is active
@@ -152,8 +245,13 @@ func (s *SearchState) IsEmpty() bool {
 
 // ResultCount returns the number of filtered results
 func (s *SearchState) ResultCount() int {
-	if s.Context == SearchTables {
+	switch s.Context {
+	case SearchTables:
 		return len(s.FilteredTables)
+	case SearchDatasets:
+		return len(s.FilteredDatasets)
+	case SearchProjects:
+		return len(s.FilteredProjects)
 	}
 	return len(s.FilteredNodes)
 }
@@ -164,16 +262,167 @@ func (s *SearchState) HasResults() bool {
 }
 
 // Messages for async operations
+type projectListLoadedMsg struct {
+	projects []bigquery.ProjectInfo
+	seq      int
+}
+
+type datasetListLoadedMsg struct {
+	datasets []bigquery.DatasetInfo
+	seq      int
+}
+
+// cachedTables records which tables of the current dataset are cached, by
+// table ID, with their metadata once loaded. Tables only known to be cached
+// have an empty placeholder. It is safe for concurrent use.
+type cachedTables struct {
+	mu       sync.RWMutex
+	metadata map[string]*bigquery.TableMetadata
+}
+
+func newCachedTables() *cachedTables {
+	return &cachedTables{metadata: make(map[string]*bigquery.TableMetadata)}
+}
+
+// get returns a table's metadata, or the placeholder, and whether it's cached
+func (c *cachedTables) get(tableID string) (*bigquery.TableMetadata, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	metadata, ok := c.metadata[tableID]
+	return metadata, ok
+}
+
+// set records a table's loaded metadata
+func (c *cachedTables) set(tableID string, metadata *bigquery.TableMetadata) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.metadata[tableID] = metadata
+}
+
+// markCached records that a table is cached without loading its metadata,
+// keeping metadata already loaded. It reports whether the table is new.
+func (c *cachedTables) markCached(tableID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.metadata[tableID]; ok {
+		return false
+	}
+	c.metadata[tableID] = &bigquery.TableMetadata{}
+	return true
+}
+
+// len returns the number of tables known to be cached
+func (c *cachedTables) len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.metadata)
+}
+
+// reset forgets every table, e.g. when switching datasets
+func (c *cachedTables) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.metadata = make(map[string]*bigquery.TableMetadata)
+}
+
+// datasetMetadataLoadedMsg carries the dataset detail pane's data, or the
+// error to show as a status message
+type datasetMetadataLoadedMsg struct {
+	metadata *bigquery.DatasetMetadata
+	err      error
+	seq      int
+}
+
+// datasetCacheWarmedMsg reports the outcome of bulk-caching a dataset's schemas
+type datasetCacheWarmedMsg struct {
+	cached int
+	total  int
+	err    error
+	seq    int
+}
+
+// tablePrefetchedMsg reports a table whose metadata the prefetcher cached
+type tablePrefetchedMsg struct {
+	result bigquery.PrefetchResult
+}
+
+// tablePreviewLoadedMsg carries the preview grid's rows, or the error to show
+// as a status message
+type tablePreviewLoadedMsg struct {
+	preview *bigquery.TablePreview
+	err     error
+	seq     int
+}
+
+// lineageLoadedMsg carries the lineage pane's entries, or the error to show
+// as a status message
+type lineageLoadedMsg struct {
+	entries []lineageEntry
+	err     error
+	seq     int
+}
+
+// columnLineageLoadedMsg carries the column lineage of a view. It isn't tied
+// to a load sequence: it's applied if the view is still open when it arrives.
+type columnLineageLoadedMsg struct {
+	ref     bigquery.TableReference
+	columns []bigquery.ViewColumn
+	err     error
+}
+
+// snapshotsLoadedMsg carries the snapshots and clones of a dataset. Like
+// column lineage it's applied only if the dataset is still open.
+type snapshotsLoadedMsg struct {
+	project   string
+	dataset   string
+	snapshots []bigquery.TableSnapshot
+	err       error
+}
+
+// partitionsLoadedMsg carries the partitions of a table. Like column lineage
+// it's applied only if the table is still open.
+type partitionsLoadedMsg struct {
+	ref        bigquery.TableReference
+	partitions []bigquery.PartitionInfo
+	err        error
+}
+
 type tableListLoadedMsg struct {
 	tables []bigquery.TableInfo
+	seq    int
+}
+
+// staleTablesLoadedMsg carries the stale tables of the current dataset
+type staleTablesLoadedMsg struct {
+	report *bigquery.StaleReport
+	err    error
+	seq    int
+}
+
+// activityLoadedMsg carries the activity pane's jobs, or the error to show as
+// a status message
+type activityLoadedMsg struct {
+	jobs []bigquery.TableJob
+	err  error
+	seq  int
+}
+
+// tableListProgressMsg reports paging progress while a table list loads
+type tableListProgressMsg struct {
+	loaded  int
+	total   int
+	seq     int
+	updates <-chan tea.Msg // Source of the next progress or completion message
 }
 
 type tableMetadataLoadedMsg struct {
 	metadata *bigquery.TableMetadata
+	seq      int
 }
 
 type errorMsg struct {
 	err error
+	seq int
 }
 
 type exportCompletedMsg struct {
@@ -185,29 +434,215 @@ type exportCompletedMsg struct {
 }
 
 // Commands for async operations
-func loadTableList(client *bigquery.Client, project, dataset string) tea.Cmd {
+func loadProjectList(ctx context.Context, seq int, client *bigquery.Client, favorites []string) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
-		// Always start with fast basic table list
-		tables, err := client.ListTables(project, dataset)
+		ctx, cancel := withOperationTimeout(ctx)
+		defer cancel()
+
+		projects, err := client.ListProjects(ctx, favorites)
 		if err != nil {
-			return errorMsg{err}
+			return errorMsg{err: err, seq: seq}
 		}
-		return tableListLoadedMsg{tables}
+		return projectListLoadedMsg{projects: projects, seq: seq}
 	})
 }
 
-func loadTableMetadata(client *bigquery.Client, project, dataset, table string) tea.Cmd {
+func loadDatasetList(ctx context.Context, seq int, client *bigquery.Client, project string) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
-		metadata, err := client.GetTableMetadata(project, dataset, table)
+		ctx, cancel := withOperationTimeout(ctx)
+		defer cancel()
+
+		datasets, err := client.ListDatasets(ctx, project)
 		if err != nil {
-			return errorMsg{err}
+			return errorMsg{err: err, seq: seq}
 		}
-		return tableMetadataLoadedMsg{metadata}
+		return datasetListLoadedMsg{datasets: datasets, seq: seq}
 	})
 }
 
-func exportTableMetadata(client *bigquery.Client, project, dataset, tableID string, existingMetadata *bigquery.TableMetadata) tea.Cmd {
+func warmDatasetCache(ctx context.Context, seq int, client *bigquery.Client, project, dataset string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		ctx, cancel := withOperationTimeout(ctx)
+		defer cancel()
+
+		cached, total, err := client.WarmDatasetCache(ctx, project, dataset)
+		return datasetCacheWarmedMsg{cached: cached, total: total, err: err, seq: seq}
+	})
+}
+
+// waitForPrefetch waits for the prefetcher's next result. It isn't tied to a
+// load, so it keeps running across them until the browser exits.
+func waitForPrefetch(results <-chan bigquery.PrefetchResult) tea.Cmd {
+	return func() tea.Msg {
+		result, ok := <-results
+		if !ok {
+			return nil
+		}
+		return tablePrefetchedMsg{result: result}
+	}
+}
+
+func loadTablePreview(ctx context.Context, seq int, client *bigquery.Client, project, dataset, table string) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
+		ctx, cancel := withOperationTimeout(ctx)
+		defer cancel()
+
+		preview, err := client.PreviewTable(ctx, project, dataset, table, config.PreviewRows)
+		return tablePreviewLoadedMsg{preview: preview, err: err, seq: seq}
+	})
+}
+
+// loadLineage finds the direct sources of a table and the views reading it
+// in its own dataset
+func loadLineage(ctx context.Context, seq int, client *bigquery.Client, ref bigquery.TableReference) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		ctx, cancel := withOperationTimeout(ctx)
+		defer cancel()
+
+		graph := bigquery.NewLineageGraph()
+		var entries []lineageEntry
+		for _, direction := range []bigquery.LineageDirection{bigquery.LineageUpstream, bigquery.LineageDownstream} {
+			if err := client.TraceLineage(ctx, graph, ref, direction, 1); err != nil {
+				return lineageLoadedMsg{err: err, seq: seq}
+			}
+			for _, related := range graph.Neighbours(ref, direction) {
+				entries = append(entries, lineageEntry{
+					ref:      related,
+					kind:     graph.Type(related),
+					upstream: direction == bigquery.LineageUpstream,
+				})
+			}
+		}
+		return lineageLoadedMsg{entries: entries, seq: seq}
+	})
+}
+
+// loadColumnLineage traces the columns of a view to their source columns for
+// the schema tree annotations
+func loadColumnLineage(ctx context.Context, client *bigquery.Client, ref bigquery.TableReference) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		ctx, cancel := withOperationTimeout(ctx)
+		defer cancel()
+
+		columns, err := client.ColumnLineage(ctx, ref.ProjectID, ref.DatasetID, ref.TableID)
+		return columnLineageLoadedMsg{ref: ref, columns: columns, err: err}
+	})
+}
+
+// loadPartitions lists a table's partitions for the histogram and partitions pane
+func loadPartitions(ctx context.Context, client *bigquery.Client, ref bigquery.TableReference) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		ctx, cancel := withOperationTimeout(ctx)
+		defer cancel()
+
+		partitions, err := client.ListPartitions(ctx, ref.ProjectID, ref.DatasetID, ref.TableID)
+		return partitionsLoadedMsg{ref: ref, partitions: partitions, err: err}
+	})
+}
+
+// loadActivity lists the recent jobs that read or wrote a table for the
+// activity pane
+func loadActivity(ctx context.Context, seq int, client *bigquery.Client, ref bigquery.TableReference) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		ctx, cancel := withOperationTimeout(ctx)
+		defer cancel()
+
+		jobs, err := client.TableJobs(ctx, ref.ProjectID, ref.DatasetID, ref.TableID, config.ActivityDays)
+		return activityLoadedMsg{jobs: jobs, err: err, seq: seq}
+	})
+}
+
+// loadStaleTables looks for the dataset's tables matching the default stale
+// criteria, bulk-loading metadata that isn't cached
+func loadStaleTables(ctx context.Context, seq int, client *bigquery.Client, project, dataset string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		ctx, cancel := withOperationTimeout(ctx)
+		defer cancel()
+
+		report, err := client.FindStaleTables(ctx, project, dataset, bigquery.DefaultStaleCriteria(), time.Now())
+		return staleTablesLoadedMsg{report: report, err: err, seq: seq}
+	})
+}
+
+// loadSnapshots lists a dataset's snapshots and clones for grouping the table list
+func loadSnapshots(ctx context.Context, client *bigquery.Client, project, dataset string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		ctx, cancel := withOperationTimeout(ctx)
+		defer cancel()
+
+		snapshots, err := client.ListSnapshots(ctx, project, dataset)
+		return snapshotsLoadedMsg{project: project, dataset: dataset, snapshots: snapshots, err: err}
+	})
+}
+
+func loadDatasetMetadata(ctx context.Context, seq int, client *bigquery.Client, project, dataset string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		ctx, cancel := withOperationTimeout(ctx)
+		defer cancel()
+
+		metadata, err := client.GetDatasetMetadata(ctx, project, dataset)
+		return datasetMetadataLoadedMsg{metadata: metadata, err: err, seq: seq}
+	})
+}
+
+func loadTableList(ctx context.Context, seq int, client *bigquery.Client, project, dataset string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		// Page through the table list in the background, streaming progress.
+		// The channel is closed once the load ends, so a receiver left waiting
+		// on an abandoned load gets a nil message instead of blocking forever.
+		updates := make(chan tea.Msg, 1)
+		go func() {
+			defer close(updates)
+			opCtx, cancel := withOperationTimeout(ctx)
+			defer cancel()
+
+			tables, err := client.ListTablesWithProgress(opCtx, project, dataset, func(loaded, total int) {
+				// Drop progress updates the UI hasn't caught up with yet
+				select {
+				case updates <- tableListProgressMsg{loaded: loaded, total: total, seq: seq, updates: updates}:
+				default:
+				}
+			})
+			var final tea.Msg = tableListLoadedMsg{tables: tables, seq: seq}
+			if err != nil {
+				final = errorMsg{err: err, seq: seq}
+			}
+			// Nobody reads the result of a load that was replaced or cancelled
+			select {
+			case updates <- final:
+			case <-ctx.Done():
+			}
+		}()
+		return <-updates
+	})
+}
+
+// waitForTableList waits for the next message from a table list load in
+// progress, or returns nil once the load has ended
+func waitForTableList(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-updates
+	}
+}
+
+func loadTableMetadata(ctx context.Context, seq int, client *bigquery.Client, project, dataset, table string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		ctx, cancel := withOperationTimeout(ctx)
+		defer cancel()
+
+		metadata, err := client.GetFullTableMetadata(ctx, project, dataset, table)
+		if err != nil {
+			return errorMsg{err: err, seq: seq}
+		}
+		return tableMetadataLoadedMsg{metadata: metadata, seq: seq}
+	})
+}
+
+func exportTableMetadata(ctx context.Context, client *bigquery.Client, project, dataset, tableID string, existingMetadata *bigquery.TableMetadata) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		ctx, cancel := withOperationTimeout(ctx)
+		defer cancel()
+
 		var tableMetadata *bigquery.TableMetadata
 		var err error
 		
@@ -215,7 +650,7 @@ func exportTableMetadata(client *bigquery.Client, project, dataset, tableID stri
 		if existingMetadata != nil {
 			tableMetadata = existingMetadata
 		} else {
-			tableMetadata, err = client.GetTableMetadata(project, dataset, tableID)
+			tableMetadata, err = client.GetFullTableMetadata(ctx, project, dataset, tableID)
 			if err != nil {
 				// Determine if error is retryable and get user-friendly message
 				errorMessage := err.Error()
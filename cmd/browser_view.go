@@ -5,9 +5,11 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 
 	"bqs/internal/bigquery"
 	"bqs/internal/config"
+	"bqs/internal/utils"
 )
 
 // Color palette for consistent theming
@@ -45,6 +47,7 @@ var (
 	datasetStyle = lipgloss.NewStyle().Foreground(primaryBlue)
 	datasetBoldStyle = lipgloss.NewStyle().Foreground(primaryBlue).Bold(true)
 	tableStyle = lipgloss.NewStyle().Foreground(primaryGreen).Bold(true)
+	breadcrumbSeparator = lipgloss.NewStyle().Foreground(darkGray).Render(" › ")
 	
 	// Metadata element styles
 	rowsStyle = lipgloss.NewStyle().Foreground(primaryBlue).Bold(true)
@@ -63,6 +66,16 @@ var (
 	collapseKeyStyle = lipgloss.NewStyle().Foreground(accentOrange)
 )
 
+// SQL syntax highlighting styles, keyed by token kind
+var sqlStyles = map[utils.SQLTokenKind]lipgloss.Style{
+	utils.SQLText:             lipgloss.NewStyle().Foreground(lightGray),
+	utils.SQLKeyword:          lipgloss.NewStyle().Foreground(primaryBlue).Bold(true),
+	utils.SQLString:           lipgloss.NewStyle().Foreground(primaryGreen),
+	utils.SQLNumber:           lipgloss.NewStyle().Foreground(accentOrange),
+	utils.SQLComment:          lipgloss.NewStyle().Foreground(secondaryGray).Italic(true),
+	utils.SQLQuotedIdentifier: lipgloss.NewStyle().Foreground(accentCyan),
+}
+
 func (m *browserModel) renderLoading() string {
 	loadingStyle := lipgloss.NewStyle().
 		Width(m.width).
@@ -73,9 +86,100 @@ func (m *browserModel) renderLoading() string {
 
 	spinner := "🔄"
 	content := fmt.Sprintf("%s Loading BigQuery metadata...", spinner)
+	if m.progress.loaded > 0 {
+		if m.progress.total > m.progress.loaded {
+			content = fmt.Sprintf("%s Loaded %d of ~%d tables...", spinner, m.progress.loaded, m.progress.total)
+		} else {
+			content = fmt.Sprintf("%s Loaded %d tables...", spinner, m.progress.loaded)
+		}
+	}
 	return loadingStyle.Render(content)
 }
 
+// renderBreadcrumb renders the navigation path for list and detail headers,
+// e.g. Projects › my-project › analytics › events
+func (m *browserModel) renderBreadcrumb() string {
+	var crumbs []string
+	if m.homeLevel {
+		crumbs = append(crumbs, backKeyStyle.Render("Projects"))
+	}
+	if m.project != "" {
+		if m.dataset == "" {
+			crumbs = append(crumbs, projectStyle.Bold(true).Render(m.project))
+		} else {
+			crumbs = append(crumbs, projectStyle.Render(m.project))
+		}
+	}
+	if m.dataset != "" {
+		if m.table == "" {
+			crumbs = append(crumbs, datasetBoldStyle.Render(m.dataset))
+		} else {
+			crumbs = append(crumbs, datasetStyle.Render(m.dataset))
+		}
+	}
+	if m.table != "" {
+		crumbs = append(crumbs, tableStyle.Render(m.table))
+	}
+	return strings.Join(crumbs, breadcrumbSeparator)
+}
+
+func (m *browserModel) renderProjectList() string {
+	var content strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryBlue).
+		Padding(0, 1).
+		MarginBottom(1)
+
+	content.WriteString(headerStyle.Render("🏠 Projects"))
+	content.WriteString("\n\n")
+
+	if len(m.projects) == 0 {
+		emptyStyle := lipgloss.NewStyle().
+			Foreground(secondaryGray).
+			Italic(true).
+			Padding(2, 4)
+		content.WriteString(emptyStyle.Render("🏠 No projects found - set BQS_FAVORITE_PROJECTS to list projects explicitly"))
+	} else {
+		content.WriteString(m.projectModel.View())
+	}
+
+	content.WriteString(m.renderStatusMessage())
+	content.WriteString(m.renderFooter())
+
+	return content.String()
+}
+
+func (m *browserModel) renderDatasetList() string {
+	var content strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryBlue).
+		Padding(0, 1).
+		MarginBottom(1)
+
+	headerText := fmt.Sprintf("📁 %s", m.renderBreadcrumb())
+	content.WriteString(headerStyle.Render(headerText))
+	content.WriteString("\n\n")
+
+	if len(m.datasets) == 0 {
+		emptyStyle := lipgloss.NewStyle().
+			Foreground(secondaryGray).
+			Italic(true).
+			Padding(2, 4)
+		content.WriteString(emptyStyle.Render("📁 No datasets found in this project"))
+	} else {
+		content.WriteString(m.datasetModel.View())
+	}
+
+	content.WriteString(m.renderStatusMessage())
+	content.WriteString(m.renderFooter())
+
+	return content.String()
+}
+
 func (m *browserModel) renderTableList() string {
 	var content strings.Builder
 
@@ -86,10 +190,8 @@ func (m *browserModel) renderTableList() string {
 		Padding(0, 1).
 		MarginBottom(1)
 
-	// Project.dataset with color hierarchy (using reusable styles)
-	headerText := fmt.Sprintf("📊 %s.%s", 
-		projectStyle.Render(m.project),
-		datasetBoldStyle.Render(m.dataset))
+	// Project › dataset with color hierarchy (using reusable styles)
+	headerText := fmt.Sprintf("📊 %s", m.renderBreadcrumb())
 	content.WriteString(headerStyle.Render(headerText))
 	content.WriteString("\n\n")
 
@@ -110,7 +212,7 @@ func (m *browserModel) renderTableList() string {
 		content.WriteString(emptyStyle.Render("📋 No tables found in this dataset"))
 	} else {
 		// Render the table component
-		content.WriteString(m.tableModel.View())
+		content.WriteString(m.dimStaleRows(m.tableModel.View()))
 	}
 
 	// Status message with enhanced styling
@@ -122,6 +224,552 @@ func (m *browserModel) renderTableList() string {
 	return content.String()
 }
 
+// dimStaleRows fades the rendered table list rows of stale tables, except the
+// selected one. The table component can't style single rows, so rows are
+// recognised by the name they start with.
+func (m *browserModel) dimStaleRows(view string) string {
+	if !m.showStale || len(m.staleTables) == 0 {
+		return view
+	}
+
+	tablesToShow := m.tables
+	if m.ui.Search.FilteredTables != nil {
+		tablesToShow = m.ui.Search.FilteredTables
+	}
+	selected := ""
+	if cursor := m.tableModel.Cursor(); cursor >= 0 && cursor < len(tablesToShow) {
+		selected = tablesToShow[cursor].TableID
+		if selected == "" {
+			selected = tablesToShow[cursor].TableReference.TableID
+		}
+	}
+
+	// Each row starts with the cell padding and the name, cut to the column
+	prefixes := make(map[string]bool, len(m.staleTables))
+	for id := range m.staleTables {
+		if id != selected {
+			prefixes[" "+ansi.Truncate(m.tableRowName(id), config.TableColumnWidth, "…")+" "] = true
+		}
+	}
+
+	dimStyle := lipgloss.NewStyle().Foreground(darkGray)
+	lines := strings.Split(view, "\n")
+	for i, line := range lines {
+		plain := ansi.Strip(line)
+		for prefix := range prefixes {
+			if strings.HasPrefix(plain, prefix) {
+				lines[i] = dimStyle.Render(plain)
+				break
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *browserModel) renderDatasetDetail() string {
+	if m.datasetMetadata == nil {
+		return "No metadata available"
+	}
+	ds := m.datasetMetadata
+
+	var content strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryBlue).
+		Padding(0, 1).
+		MarginBottom(1)
+
+	// Opened from the dataset list, the breadcrumb doesn't include the dataset yet
+	breadcrumb := m.renderBreadcrumb()
+	if m.dataset == "" {
+		breadcrumb += breadcrumbSeparator + datasetBoldStyle.Render(ds.DatasetReference.DatasetID)
+	}
+	content.WriteString(headerStyle.Render("📁 " + breadcrumb))
+	content.WriteString("\n\n")
+
+	metaStyle := lipgloss.NewStyle().
+		Foreground(secondaryGray).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(darkGray).
+		Padding(1, 2)
+
+	meta := fmt.Sprintf("📍 %s • 🕒 Created %s • Modified %s",
+		rowsStyle.Render(ds.Location),
+		timeStyle.Render(bigquery.FormatTime(ds.CreationTime)),
+		timeStyle.Render(bigquery.FormatTime(ds.LastModifiedTime)))
+	if ds.Description != "" {
+		meta = ds.Description + "\n\n" + meta
+	}
+	content.WriteString(metaStyle.Render(meta))
+	content.WriteString("\n\n")
+
+	billingModel := ds.StorageBillingModel
+	if billingModel == "" {
+		billingModel = "LOGICAL (default)"
+	}
+	properties := [][]string{
+		{"Default table expiration", bigquery.FormatExpiration(ds.DefaultTableExpirationMs)},
+		{"Default partition expiration", bigquery.FormatExpiration(ds.DefaultPartitionExpirationMs)},
+		{"Default collation", valueOrNone(ds.DefaultCollation)},
+		{"Storage billing model", billingModel},
+		{"Labels", valueOrNone(bigquery.FormatLabels(ds.Labels))},
+	}
+
+	keyStyle := lipgloss.NewStyle().Foreground(primaryBlue).Bold(true)
+	valueStyle := lipgloss.NewStyle().Foreground(lightGray)
+	for _, property := range properties {
+		content.WriteString(fmt.Sprintf("  %s  %s\n",
+			keyStyle.Render(fmt.Sprintf("%-29s", property[0])),
+			valueStyle.Render(property[1])))
+	}
+
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryBlue).
+		Padding(0, 1).
+		MarginTop(1)
+	content.WriteString(sectionStyle.Render(fmt.Sprintf("🔐 Access (%d entries):", len(ds.Access))))
+	content.WriteString("\n")
+
+	// Keep the pane on one screen; bqs show lists every entry
+	maxEntries := m.height - config.DatasetDetailPadding
+	if maxEntries < config.MinTableHeight {
+		maxEntries = config.MinTableHeight
+	}
+	roleStyle := lipgloss.NewStyle().Foreground(accentPurple)
+	for i, entry := range ds.Access {
+		if i == maxEntries {
+			content.WriteString(valueStyle.Italic(true).Render(fmt.Sprintf("  … and %d more (use bqs show for the full list)", len(ds.Access)-i)))
+			content.WriteString("\n")
+			break
+		}
+		role := entry.Role
+		if role == "" {
+			role = "AUTHORIZED" // Authorized views, routines and datasets carry no role
+		}
+		content.WriteString(fmt.Sprintf("  %s  %s\n",
+			roleStyle.Render(fmt.Sprintf("%-10s", role)),
+			valueStyle.Render(entry.Grantee())))
+	}
+
+	content.WriteString(m.renderStatusMessage())
+	content.WriteString(m.renderFooter())
+
+	return content.String()
+}
+
+// renderTableDetails renders the "Details" section of the table detail view
+func (m *browserModel) renderTableDetails() string {
+	var content strings.Builder
+
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryBlue).
+		Padding(0, 1)
+	content.WriteString(sectionStyle.Render("🔧 Details:"))
+	content.WriteString("\n")
+
+	keyStyle := lipgloss.NewStyle().Foreground(primaryBlue).Bold(true)
+	valueStyle := lipgloss.NewStyle().Foreground(lightGray)
+	for _, detail := range bigquery.MetadataDetails(m.metadata) {
+		content.WriteString(fmt.Sprintf("  %s  %s\n",
+			keyStyle.Render(fmt.Sprintf("%-24s", detail.Label)),
+			valueStyle.Render(detail.Value)))
+	}
+
+	return content.String()
+}
+
+// sparkBlocks draw a sparkline, from the lowest non-zero value to the highest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// renderPartitionHistogram renders rows per day of a DAY or HOUR partitioned
+// table as a sparkline, with days without rows marked so gaps stand out.
+// Other tables, and tables whose partitions are still loading, have none.
+func (m *browserModel) renderPartitionHistogram() string {
+	days := config.PartitionHistogramDays
+	if m.width > 0 && m.width-6 < days {
+		days = m.width - 6
+	}
+	first, rows, ok := bigquery.DailyRows(m.partitions, days)
+	if !ok {
+		return ""
+	}
+
+	var content strings.Builder
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryBlue).
+		Padding(0, 1).
+		MarginTop(1)
+	last := first.AddDate(0, 0, len(rows)-1)
+	content.WriteString(sectionStyle.Render(fmt.Sprintf("📈 Rows per day (%s – %s):",
+		first.Format("Jan 2"), last.Format("Jan 2"))))
+	content.WriteString("\n")
+
+	var peak int64
+	empty := 0
+	for _, n := range rows {
+		if n > peak {
+			peak = n
+		}
+		if n == 0 {
+			empty++
+		}
+	}
+
+	barStyle := lipgloss.NewStyle().Foreground(primaryGreen)
+	emptyStyle := lipgloss.NewStyle().Foreground(primaryRed)
+	var line strings.Builder
+	for _, n := range rows {
+		if n == 0 {
+			line.WriteString(emptyStyle.Render("·"))
+			continue
+		}
+		level := int((n*int64(len(sparkBlocks)) - 1) / peak)
+		line.WriteString(barStyle.Render(string(sparkBlocks[level])))
+	}
+
+	summary := fmt.Sprintf("peak %d", peak)
+	if empty > 0 {
+		summary += fmt.Sprintf(" • %d empty", empty)
+	}
+	summaryStyle := lipgloss.NewStyle().Foreground(secondaryGray)
+	content.WriteString(fmt.Sprintf("  %s  %s\n", line.String(), summaryStyle.Render(summary)))
+
+	return content.String()
+}
+
+// highlightSQL renders a query with syntax highlighting, one entry per line.
+// Tokens spanning lines (comments, triple-quoted strings) are styled per line
+// so each line can be scrolled and rendered on its own.
+func highlightSQL(sql string) []string {
+	lines := []string{""}
+	for _, token := range utils.TokenizeSQL(strings.ReplaceAll(sql, "\t", "    ")) {
+		style := sqlStyles[token.Kind]
+		for i, part := range strings.Split(token.Text, "\n") {
+			if i > 0 {
+				lines = append(lines, "")
+			}
+			if part != "" {
+				lines[len(lines)-1] += style.Render(part)
+			}
+		}
+	}
+	return lines
+}
+
+// renderViewSQL renders the scrollable SQL pane of a view or materialized view
+func (m *browserModel) renderViewSQL() string {
+	var content strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryBlue).
+		Padding(0, 1).
+		MarginBottom(1)
+
+	icon := bigquery.GetTableTypeIcon(m.metadata.DisplayType())
+	content.WriteString(headerStyle.Render(fmt.Sprintf("%s %s", icon, m.renderBreadcrumb())))
+	content.WriteString("\n\n")
+
+	if mv := m.metadata.MaterializedView; mv != nil {
+		metaStyle := lipgloss.NewStyle().
+			Foreground(secondaryGray).
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(darkGray).
+			Padding(0, 2)
+
+		refresh := "Disabled"
+		if mv.RefreshEnabled() {
+			// BigQuery refreshes every 30 minutes unless told otherwise
+			interval := "30m"
+			if mv.RefreshIntervalMs > 0 {
+				interval = bigquery.FormatExpiration(mv.RefreshIntervalMs)
+			}
+			refresh = "Every " + interval
+		}
+		meta := fmt.Sprintf("🔄 Refresh %s • 🕒 Last refreshed %s",
+			rowsStyle.Render(refresh),
+			timeStyle.Render(bigquery.FormatTime(mv.LastRefreshTime)))
+		content.WriteString(metaStyle.Render(meta))
+		content.WriteString("\n\n")
+	}
+
+	height := m.viewSQLHeight()
+	end := m.sqlOffset + height
+	if end > len(m.sqlLines) {
+		end = len(m.sqlLines)
+	}
+
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryBlue).
+		Padding(0, 1)
+	title := "📜 Query:"
+	if len(m.sqlLines) > height {
+		title = fmt.Sprintf("📜 Query (lines %d-%d of %d):", m.sqlOffset+1, end, len(m.sqlLines))
+	}
+	content.WriteString(sectionStyle.Render(title))
+	content.WriteString("\n\n")
+
+	gutterStyle := lipgloss.NewStyle().Foreground(darkGray)
+	gutterWidth := len(fmt.Sprintf("%d", len(m.sqlLines)))
+	for i := m.sqlOffset; i < end; i++ {
+		content.WriteString(fmt.Sprintf("  %s  %s\n",
+			gutterStyle.Render(fmt.Sprintf("%*d", gutterWidth, i+1)),
+			m.sqlLines[i]))
+	}
+
+	content.WriteString(m.renderStatusMessage())
+	content.WriteString(m.renderFooter())
+
+	return content.String()
+}
+
+// renderPreview renders the preview grid of the current table's first rows
+func (m *browserModel) renderPreview() string {
+	var content strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryBlue).
+		Padding(0, 1).
+		MarginBottom(1)
+
+	icon := bigquery.GetTableTypeIcon(m.metadata.DisplayType())
+	content.WriteString(headerStyle.Render(fmt.Sprintf("%s %s", icon, m.renderBreadcrumb())))
+	content.WriteString("\n\n")
+
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryBlue).
+		Padding(0, 1)
+	columns := m.preview.Columns()
+	if len(m.preview.Rows) == 0 || len(columns) == 0 {
+		content.WriteString(sectionStyle.Render("📋 Preview: table is empty"))
+		content.WriteString("\n")
+	} else {
+		title := fmt.Sprintf("📋 Preview: %d rows • column %d of %d", len(m.preview.Rows), m.previewCol+1, len(columns))
+		content.WriteString(sectionStyle.Render(title))
+		content.WriteString("\n\n")
+		content.WriteString(m.renderPreviewGrid())
+	}
+
+	content.WriteString(m.renderStatusMessage())
+	content.WriteString(m.renderFooter())
+
+	return content.String()
+}
+
+// renderLineage renders the current table's direct sources and the views
+// reading it, as a single selectable list
+func (m *browserModel) renderLineage() string {
+	var content strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryBlue).
+		Padding(0, 1).
+		MarginBottom(1)
+
+	icon := bigquery.GetTableTypeIcon(m.metadata.DisplayType())
+	content.WriteString(headerStyle.Render(fmt.Sprintf("%s %s", icon, m.renderBreadcrumb())))
+	content.WriteString("\n\n")
+
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryBlue).
+		Padding(0, 1)
+	emptyStyle := lipgloss.NewStyle().Foreground(secondaryGray).Italic(true).Padding(0, 3)
+	typeStyle := lipgloss.NewStyle().Foreground(lightGray)
+
+	for _, upstream := range []bool{true, false} {
+		title := "⬆️  Reads from:"
+		if !upstream {
+			title = "⬇️  Read by (views in this dataset):"
+		}
+		content.WriteString(sectionStyle.Render(title))
+		content.WriteString("\n\n")
+
+		found := false
+		for i, entry := range m.lineage {
+			if entry.upstream != upstream {
+				continue
+			}
+			found = true
+
+			style := lipgloss.NewStyle().Padding(0, 1)
+			if i == m.lineageSelected {
+				style = style.Background(selectedBg).Foreground(selectedFg).Bold(true)
+			}
+			line := fmt.Sprintf("  %s %s", bigquery.GetTableTypeIcon(entry.kind), entry.ref.String())
+			if entry.kind != "" {
+				line += " " + typeStyle.Render(entry.kind)
+			}
+			content.WriteString(style.Render(line))
+			content.WriteString("\n")
+		}
+		if !found {
+			content.WriteString(emptyStyle.Render("None found"))
+			content.WriteString("\n")
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString(m.renderStatusMessage())
+	content.WriteString(m.renderFooter())
+
+	return content.String()
+}
+
+// renderPartitions renders the partitions of the current table, newest first,
+// as a scrolling list
+func (m *browserModel) renderPartitions() string {
+	var content strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryBlue).
+		Padding(0, 1).
+		MarginBottom(1)
+
+	icon := bigquery.GetTableTypeIcon(m.metadata.DisplayType())
+	content.WriteString(headerStyle.Render(fmt.Sprintf("%s %s", icon, m.renderBreadcrumb())))
+	content.WriteString("\n\n")
+
+	// Keep the selection in view
+	height := m.partitionsHeight()
+	offset := 0
+	if m.partitionSelected >= height {
+		offset = m.partitionSelected - height + 1
+	}
+	end := offset + height
+	if end > len(m.partitions) {
+		end = len(m.partitions)
+	}
+
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryBlue).
+		Padding(0, 1)
+	title := fmt.Sprintf("🧩 Partitions (%d):", len(m.partitions))
+	if len(m.partitions) > height {
+		title = fmt.Sprintf("🧩 Partitions (%d-%d of %d):", offset+1, end, len(m.partitions))
+	}
+	content.WriteString(sectionStyle.Render(title))
+	content.WriteString("\n\n")
+
+	columnStyle := lipgloss.NewStyle().Foreground(secondaryGray).Bold(true).Padding(0, 1)
+	content.WriteString(columnStyle.Render(fmt.Sprintf("  %-17s %12s %10s %10s  %-14s %s",
+		"Partition", "Rows", "Logical", "Billable", "Modified", "Tier")))
+	content.WriteString("\n")
+
+	emptyStyle := lipgloss.NewStyle().Foreground(primaryRed)
+	for i := offset; i < end; i++ {
+		p := m.partitions[i]
+		id := p.PartitionID
+		if id == "" {
+			id = "(unpartitioned)"
+		}
+		line := fmt.Sprintf("  %-17s %12d %10s %10s  %-14s %s", id, p.TotalRows,
+			bigquery.FormatSize(p.TotalLogicalBytes), bigquery.FormatSize(p.TotalBillableBytes),
+			bigquery.FormatTime(p.LastModifiedTime), p.StorageTier)
+
+		style := lipgloss.NewStyle().Padding(0, 1)
+		if i == m.partitionSelected {
+			style = style.Background(selectedBg).Foreground(selectedFg).Bold(true)
+		} else if p.TotalRows == 0 {
+			style = style.Inherit(emptyStyle)
+		}
+		content.WriteString(style.Render(line))
+		content.WriteString("\n")
+	}
+
+	content.WriteString(m.renderStatusMessage())
+	content.WriteString(m.renderFooter())
+
+	return content.String()
+}
+
+// renderActivity renders the recent jobs that read or wrote the current
+// table, newest first, as a scrolling list
+func (m *browserModel) renderActivity() string {
+	var content strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryBlue).
+		Padding(0, 1).
+		MarginBottom(1)
+
+	icon := bigquery.GetTableTypeIcon(m.metadata.DisplayType())
+	content.WriteString(headerStyle.Render(fmt.Sprintf("%s %s", icon, m.renderBreadcrumb())))
+	content.WriteString("\n\n")
+
+	// Keep the selection in view
+	height := m.activityHeight()
+	offset := 0
+	if m.activitySelected >= height {
+		offset = m.activitySelected - height + 1
+	}
+	end := offset + height
+	if end > len(m.activity) {
+		end = len(m.activity)
+	}
+
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryBlue).
+		Padding(0, 1)
+	usage := bigquery.SummarizeTableJobs(m.activity)
+	title := fmt.Sprintf("📈 Activity, last %d days: %d reads, %d writes", config.ActivityDays, usage.Reads, usage.Writes)
+	if len(m.activity) > height {
+		title += fmt.Sprintf(" (%d-%d of %d)", offset+1, end, len(m.activity))
+	}
+	content.WriteString(sectionStyle.Render(title + ":"))
+	content.WriteString("\n\n")
+
+	columnStyle := lipgloss.NewStyle().Foreground(secondaryGray).Bold(true).Padding(0, 1)
+	content.WriteString(columnStyle.Render(fmt.Sprintf("  %-12s %-32s %-14s %-5s %10s",
+		"Time", "User", "Operation", "R/W", "Processed")))
+	content.WriteString("\n")
+
+	failedStyle := lipgloss.NewStyle().Foreground(primaryRed)
+	for i := offset; i < end; i++ {
+		job := m.activity[i]
+		access := "read"
+		if job.Wrote {
+			access = "write"
+		}
+		line := fmt.Sprintf("  %-12s %s %-14s %-5s %10s", bigquery.FormatTime(job.CreationTime),
+			fitCell(job.User, 32), job.Operation(), access, bigquery.FormatSize(job.TotalBytesProcessed))
+
+		style := lipgloss.NewStyle().Padding(0, 1)
+		if i == m.activitySelected {
+			style = style.Background(selectedBg).Foreground(selectedFg).Bold(true)
+		} else if job.Failed {
+			style = style.Inherit(failedStyle)
+		}
+		content.WriteString(style.Render(line))
+		content.WriteString("\n")
+	}
+
+	content.WriteString(m.renderStatusMessage())
+	content.WriteString(m.renderFooter())
+
+	return content.String()
+}
+
+// valueOrNone substitutes a placeholder for unset detail values
+func valueOrNone(value string) string {
+	if value == "" {
+		return "None"
+	}
+	return value
+}
+
 func (m *browserModel) renderTableDetail() string {
 	if m.metadata == nil {
 		if m.loading {
@@ -143,12 +791,9 @@ func (m *browserModel) renderTableDetail() string {
 		Padding(0, 1).
 		MarginBottom(1)
 
-	icon := bigquery.GetTableTypeIcon(m.metadata.Type)
-	// Project.dataset.table with color hierarchy (using reusable styles)
-	headerText := fmt.Sprintf("%s %s.%s.%s", icon,
-		projectStyle.Render(m.project),
-		datasetStyle.Render(m.dataset),
-		tableStyle.Render(m.table))
+	icon := bigquery.GetTableTypeIcon(m.metadata.DisplayType())
+	// Project › dataset › table with color hierarchy (using reusable styles)
+	headerText := fmt.Sprintf("%s %s", icon, m.renderBreadcrumb())
 	content.WriteString(headerStyle.Render(headerText))
 	content.WriteString("\n\n")
 
@@ -171,6 +816,10 @@ func (m *browserModel) renderTableDetail() string {
 	content.WriteString(metaStyle.Render(meta))
 	content.WriteString("\n\n")
 
+	// Partitioning, clustering, lifecycle and storage details
+	content.WriteString(m.renderTableDetails())
+	content.WriteString(m.renderPartitionHistogram())
+
 	// Schema with enhanced styling
 	if m.metadata.Schema != nil && len(m.schemaNodes) > 0 {
 		schemaStyle := lipgloss.NewStyle().
@@ -211,57 +860,163 @@ func (m *browserModel) renderError() string {
 	return errorStyle.Render(errorText)
 }
 
-func (m *browserModel) renderHelp() string {
-	// Create help content based on the previous state
-	var helpContent strings.Builder
-	
-	// Main help container
-	helpStyle := lipgloss.NewStyle().
-		Padding(2, 4).
-		Margin(2, 4).
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(primaryBlue).
-		Background(lipgloss.Color("235")).
-		Width(m.width - 16)
+func (m *browserModel) renderHelp() string {
+	// Create help content based on the previous state
+	var helpContent strings.Builder
+	
+	// Main help container
+	helpStyle := lipgloss.NewStyle().
+		Padding(2, 4).
+		Margin(2, 4).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryBlue).
+		Background(lipgloss.Color("235")).
+		Width(m.width - 16)
+
+	// Title
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryBlue).
+		Align(lipgloss.Center).
+		Width(m.width - 24)
+	
+	helpContent.WriteString(titleStyle.Render("🆘 BQS Help"))
+	helpContent.WriteString("\n\n")
+
+	// Context-sensitive shortcuts
+	if m.previousState == stateProjectList {
+		helpContent.WriteString(m.renderProjectListHelp())
+	} else if m.previousState == stateDatasetList {
+		helpContent.WriteString(m.renderDatasetListHelp())
+	} else if m.previousState == stateDatasetDetail {
+		helpContent.WriteString(m.renderDatasetDetailHelp())
+	} else if m.previousState == stateTableList {
+		helpContent.WriteString(m.renderTableListHelp())
+	} else if m.previousState == stateTableDetail {
+		helpContent.WriteString(m.renderTableDetailHelp())
+	} else if m.previousState == stateViewSQL {
+		helpContent.WriteString(m.renderViewSQLHelp())
+	} else if m.previousState == statePreview {
+		helpContent.WriteString(m.renderPreviewHelp())
+	} else if m.previousState == stateLineage {
+		helpContent.WriteString(m.renderLineageHelp())
+	} else if m.previousState == statePartitions {
+		helpContent.WriteString(m.renderPartitionsHelp())
+	} else if m.previousState == stateActivity {
+		helpContent.WriteString(m.renderActivityHelp())
+	}
+
+	// Universal shortcuts
+	helpContent.WriteString("\n")
+	universalStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(accentCyan).
+		MarginTop(1)
+	helpContent.WriteString(universalStyle.Render("Universal Commands:"))
+	helpContent.WriteString("\n")
+	helpContent.WriteString(m.renderUniversalHelp())
+
+	// Footer
+	helpContent.WriteString("\n\n")
+	footerStyle := lipgloss.NewStyle().
+		Foreground(secondaryGray).
+		Italic(true).
+		Align(lipgloss.Center).
+		Width(m.width - 24)
+	helpContent.WriteString(footerStyle.Render("Press ? or Esc to close help"))
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, 
+		helpStyle.Render(helpContent.String()))
+}
+
+func (m *browserModel) renderProjectListHelp() string {
+	var content strings.Builder
+
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryGreen).
+		MarginBottom(1)
+	content.WriteString(sectionStyle.Render("Project List Navigation:"))
+	content.WriteString("\n")
+
+	shortcuts := [][]string{
+		{"hjkl, ↑↓", "Navigate project list"},
+		{"gg", "Jump to top"},
+		{"G", "Jump to bottom"},
+		{"/", "Search projects (Enter to select)"},
+		{"Enter", "Explore selected project"},
+		{"yy", "Copy project identifier"},
+	}
+
+	for _, shortcut := range shortcuts {
+		keyStyle := lipgloss.NewStyle().Foreground(primaryYellow).Bold(true)
+		descStyle := lipgloss.NewStyle().Foreground(lightGray)
+		content.WriteString(fmt.Sprintf("  %s  %s\n",
+			keyStyle.Render(fmt.Sprintf("%-8s", shortcut[0])),
+			descStyle.Render(shortcut[1])))
+	}
+
+	return content.String()
+}
+
+func (m *browserModel) renderDatasetListHelp() string {
+	var content strings.Builder
+
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryGreen).
+		MarginBottom(1)
+	content.WriteString(sectionStyle.Render("Dataset List Navigation:"))
+	content.WriteString("\n")
+
+	shortcuts := [][]string{
+		{"hjkl, ↑↓", "Navigate dataset list"},
+		{"gg", "Jump to top"},
+		{"G", "Jump to bottom"},
+		{"/", "Search datasets (Enter to select)"},
+		{"Enter", "Explore selected dataset"},
+		{"i", "Show dataset details"},
+		{"yy", "Copy dataset identifier"},
+	}
+	if m.homeLevel {
+		shortcuts = append(shortcuts, []string{"b", "Back to project list"})
+	}
+
+	for _, shortcut := range shortcuts {
+		keyStyle := lipgloss.NewStyle().Foreground(primaryYellow).Bold(true)
+		descStyle := lipgloss.NewStyle().Foreground(lightGray)
+		content.WriteString(fmt.Sprintf("  %s  %s\n",
+			keyStyle.Render(fmt.Sprintf("%-8s", shortcut[0])),
+			descStyle.Render(shortcut[1])))
+	}
+
+	return content.String()
+}
+
+func (m *browserModel) renderDatasetDetailHelp() string {
+	var content strings.Builder
 
-	// Title
-	titleStyle := lipgloss.NewStyle().
+	sectionStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(primaryBlue).
-		Align(lipgloss.Center).
-		Width(m.width - 24)
-	
-	helpContent.WriteString(titleStyle.Render("🆘 BQS Help"))
-	helpContent.WriteString("\n\n")
+		Foreground(primaryGreen).
+		MarginBottom(1)
+	content.WriteString(sectionStyle.Render("Dataset Details:"))
+	content.WriteString("\n")
 
-	// Context-sensitive shortcuts
-	if m.previousState == stateTableList {
-		helpContent.WriteString(m.renderTableListHelp())
-	} else if m.previousState == stateTableDetail {
-		helpContent.WriteString(m.renderTableDetailHelp())
+	shortcuts := [][]string{
+		{"yy", "Copy dataset identifier"},
+		{"b", "Back to list"},
 	}
 
-	// Universal shortcuts
-	helpContent.WriteString("\n")
-	universalStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(accentCyan).
-		MarginTop(1)
-	helpContent.WriteString(universalStyle.Render("Universal Commands:"))
-	helpContent.WriteString("\n")
-	helpContent.WriteString(m.renderUniversalHelp())
-
-	// Footer
-	helpContent.WriteString("\n\n")
-	footerStyle := lipgloss.NewStyle().
-		Foreground(secondaryGray).
-		Italic(true).
-		Align(lipgloss.Center).
-		Width(m.width - 24)
-	helpContent.WriteString(footerStyle.Render("Press ? or Esc to close help"))
+	for _, shortcut := range shortcuts {
+		keyStyle := lipgloss.NewStyle().Foreground(primaryYellow).Bold(true)
+		descStyle := lipgloss.NewStyle().Foreground(lightGray)
+		content.WriteString(fmt.Sprintf("  %s  %s\n",
+			keyStyle.Render(fmt.Sprintf("%-8s", shortcut[0])),
+			descStyle.Render(shortcut[1])))
+	}
 
-	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, 
-		helpStyle.Render(helpContent.String()))
+	return content.String()
 }
 
 func (m *browserModel) renderTableListHelp() string {
@@ -280,9 +1035,17 @@ func (m *browserModel) renderTableListHelp() string {
 		{"G", "Jump to bottom"},
 		{"/", "Search items (Enter to select)"},
 		{"Enter", "Explore selected table"},
+		{"i", "Show dataset details"},
+		{"w", "Cache schemas of every table"},
 		{"yy", "Copy table identifier"},
 		{"e", "Copy table metadata to clipboard"},
 	}
+	criteria := bigquery.DefaultStaleCriteria()
+	shortcuts = append(shortcuts, []string{"S", fmt.Sprintf("Dim stale tables (unmodified %s, unread %s)",
+		formatAge(criteria.Unmodified), formatAge(criteria.Unqueried))})
+	if m.projectLevel {
+		shortcuts = append(shortcuts, []string{"b", "Back to dataset list"})
+	}
 
 	for _, shortcut := range shortcuts {
 		keyStyle := lipgloss.NewStyle().Foreground(primaryYellow).Bold(true)
@@ -314,7 +1077,22 @@ func (m *browserModel) renderTableDetailHelp() string {
 		{"←, h", "Collapse field"},
 		{"yy", "Copy table identifier"},
 		{"e", "Copy table metadata to clipboard"},
-		{"b", "Back to table list"},
+	}
+	if m.metadata != nil && m.metadata.DefinitionQuery() != "" {
+		shortcuts = append(shortcuts, []string{"v", "Show view SQL"})
+	}
+	if m.metadata != nil && bigquery.Previewable(m.metadata.Type) {
+		shortcuts = append(shortcuts, []string{"p", "Preview table rows"})
+	}
+	shortcuts = append(shortcuts, []string{"L", "Show lineage"})
+	if m.metadata != nil && m.metadata.Partitioned() {
+		shortcuts = append(shortcuts, []string{"P", "Show partitions"})
+	}
+	shortcuts = append(shortcuts, []string{"A", "Show recent jobs reading or writing it"})
+	if len(m.lineageTrail) > 0 {
+		shortcuts = append(shortcuts, []string{"b", "Back to previous table"})
+	} else {
+		shortcuts = append(shortcuts, []string{"b", "Back to table list"})
 	}
 
 	for _, shortcut := range shortcuts {
@@ -328,6 +1106,159 @@ func (m *browserModel) renderTableDetailHelp() string {
 	return content.String()
 }
 
+func (m *browserModel) renderViewSQLHelp() string {
+	var content strings.Builder
+
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryGreen).
+		MarginBottom(1)
+	content.WriteString(sectionStyle.Render("View SQL:"))
+	content.WriteString("\n")
+
+	shortcuts := [][]string{
+		{"jk, ↑↓", "Scroll query"},
+		{"gg", "Jump to top"},
+		{"G", "Jump to bottom"},
+		{"yy", "Copy view SQL"},
+		{"v, b", "Back to table details"},
+	}
+
+	for _, shortcut := range shortcuts {
+		keyStyle := lipgloss.NewStyle().Foreground(primaryYellow).Bold(true)
+		descStyle := lipgloss.NewStyle().Foreground(lightGray)
+		content.WriteString(fmt.Sprintf("  %s  %s\n",
+			keyStyle.Render(fmt.Sprintf("%-8s", shortcut[0])),
+			descStyle.Render(shortcut[1])))
+	}
+
+	return content.String()
+}
+
+func (m *browserModel) renderPreviewHelp() string {
+	var content strings.Builder
+
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryGreen).
+		MarginBottom(1)
+	content.WriteString(sectionStyle.Render("Table Preview:"))
+	content.WriteString("\n")
+
+	shortcuts := [][]string{
+		{"jk, ↑↓", "Move between rows"},
+		{"hl, ←→", "Scroll columns"},
+		{"gg", "Jump to first row"},
+		{"G", "Jump to last row"},
+		{"Space, ⏎", "Expand/collapse cell as JSON"},
+		{"yy", "Copy cell value"},
+		{"p, b", "Back to table details"},
+	}
+
+	for _, shortcut := range shortcuts {
+		keyStyle := lipgloss.NewStyle().Foreground(primaryYellow).Bold(true)
+		descStyle := lipgloss.NewStyle().Foreground(lightGray)
+		content.WriteString(fmt.Sprintf("  %s  %s\n",
+			keyStyle.Render(fmt.Sprintf("%-8s", shortcut[0])),
+			descStyle.Render(shortcut[1])))
+	}
+
+	return content.String()
+}
+
+func (m *browserModel) renderLineageHelp() string {
+	var content strings.Builder
+
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryGreen).
+		MarginBottom(1)
+	content.WriteString(sectionStyle.Render("Lineage:"))
+	content.WriteString("\n")
+
+	shortcuts := [][]string{
+		{"jk, ↑↓", "Move between related tables"},
+		{"gg", "Jump to top"},
+		{"G", "Jump to bottom"},
+		{"Enter", "Open selected table (b returns)"},
+		{"yy", "Copy table identifier"},
+		{"L, b", "Back to table details"},
+	}
+
+	for _, shortcut := range shortcuts {
+		keyStyle := lipgloss.NewStyle().Foreground(primaryYellow).Bold(true)
+		descStyle := lipgloss.NewStyle().Foreground(lightGray)
+		content.WriteString(fmt.Sprintf("  %s  %s\n",
+			keyStyle.Render(fmt.Sprintf("%-8s", shortcut[0])),
+			descStyle.Render(shortcut[1])))
+	}
+
+	return content.String()
+}
+
+func (m *browserModel) renderPartitionsHelp() string {
+	var content strings.Builder
+
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryGreen).
+		MarginBottom(1)
+	content.WriteString(sectionStyle.Render("Partitions:"))
+	content.WriteString("\n")
+
+	shortcuts := [][]string{
+		{"jk, ↑↓", "Move between partitions"},
+		{"gg", "Jump to newest"},
+		{"G", "Jump to oldest"},
+		{"yy", "Copy partition decorator (table$partition)"},
+		{"P, b", "Back to table details"},
+	}
+
+	for _, shortcut := range shortcuts {
+		keyStyle := lipgloss.NewStyle().Foreground(primaryYellow).Bold(true)
+		descStyle := lipgloss.NewStyle().Foreground(lightGray)
+		content.WriteString(fmt.Sprintf("  %s  %s\n",
+			keyStyle.Render(fmt.Sprintf("%-8s", shortcut[0])),
+			descStyle.Render(shortcut[1])))
+	}
+
+	return content.String()
+}
+
+func (m *browserModel) renderActivityHelp() string {
+	var content strings.Builder
+
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryGreen).
+		MarginBottom(1)
+	content.WriteString(sectionStyle.Render("Activity:"))
+	content.WriteString("\n")
+
+	shortcuts := [][]string{
+		{"jk, ↑↓", "Move between jobs"},
+		{"gg", "Jump to newest"},
+		{"G", "Jump to oldest"},
+		{"yy", "Copy job ID"},
+		{"A, b", "Back to table details"},
+	}
+
+	for _, shortcut := range shortcuts {
+		keyStyle := lipgloss.NewStyle().Foreground(primaryYellow).Bold(true)
+		descStyle := lipgloss.NewStyle().Foreground(lightGray)
+		content.WriteString(fmt.Sprintf("  %s  %s\n",
+			keyStyle.Render(fmt.Sprintf("%-8s", shortcut[0])),
+			descStyle.Render(shortcut[1])))
+	}
+
+	content.WriteString("\n")
+	noteStyle := lipgloss.NewStyle().Foreground(secondaryGray)
+	content.WriteString(noteStyle.Render("Failed jobs are shown in red. Only jobs run in the table's project are listed."))
+	content.WriteString("\n")
+
+	return content.String()
+}
+
 func (m *browserModel) renderUniversalHelp() string {
 	var content strings.Builder
 
@@ -391,10 +1322,26 @@ func (m *browserModel) renderFooter() string {
 	
 	
 	// Normal footer with shortcuts
-	if m.state == stateTableList {
+	if m.state == stateProjectList {
+		content.WriteString(m.renderProjectListFooter(footerStyle))
+	} else if m.state == stateDatasetList {
+		content.WriteString(m.renderDatasetListFooter(footerStyle))
+	} else if m.state == stateDatasetDetail {
+		content.WriteString(m.renderDatasetDetailFooter(footerStyle))
+	} else if m.state == stateTableList {
 		content.WriteString(m.renderTableListFooter(footerStyle))
 	} else if m.state == stateTableDetail {
 		content.WriteString(m.renderTableDetailFooter(footerStyle))
+	} else if m.state == stateViewSQL {
+		content.WriteString(m.renderViewSQLFooter(footerStyle))
+	} else if m.state == statePreview {
+		content.WriteString(m.renderPreviewFooter(footerStyle))
+	} else if m.state == stateLineage {
+		content.WriteString(m.renderLineageFooter(footerStyle))
+	} else if m.state == statePartitions {
+		content.WriteString(m.renderPartitionsFooter(footerStyle))
+	} else if m.state == stateActivity {
+		content.WriteString(m.renderActivityFooter(footerStyle))
 	}
 	
 	return content.String()
@@ -405,7 +1352,11 @@ func (m *browserModel) renderSearchBarInFooter() string {
 	// Show different prompts based on search state and current view
 	var searchText string
 	if m.ui.Search.Query == "" {
-		if m.state == stateTableList {
+		if m.state == stateProjectList {
+			searchText = "🔍 Search projects (Esc to cancel): _"
+		} else if m.state == stateDatasetList {
+			searchText = "🔍 Search datasets (Esc to cancel): _"
+		} else if m.state == stateTableList {
 			searchText = "🔍 Search tables/views (Esc to cancel): _"
 		} else {
 			searchText = "🔍 Search schema fields (Esc to cancel): _"
@@ -414,7 +1365,19 @@ func (m *browserModel) renderSearchBarInFooter() string {
 		var resultsCount int
 		var searchType string
 		
-		if m.state == stateTableList {
+		if m.state == stateProjectList {
+			resultsCount = len(m.projects)
+			if m.ui.Search.FilteredProjects != nil {
+				resultsCount = len(m.ui.Search.FilteredProjects)
+			}
+			searchType = "projects"
+		} else if m.state == stateDatasetList {
+			resultsCount = len(m.datasets)
+			if m.ui.Search.FilteredDatasets != nil {
+				resultsCount = len(m.ui.Search.FilteredDatasets)
+			}
+			searchType = "datasets"
+		} else if m.state == stateTableList {
 			resultsCount = len(m.tables)
 			if m.ui.Search.FilteredTables != nil {
 				resultsCount = len(m.ui.Search.FilteredTables)
@@ -441,18 +1404,68 @@ func renderShortcutFooter(shortcuts []string, footerStyle lipgloss.Style) string
 	return footerStyle.Render(footer)
 }
 
+// renderProjectListFooter renders the normal project list footer with shortcuts
+func (m *browserModel) renderProjectListFooter(footerStyle lipgloss.Style) string {
+	shortcuts := []string{
+		navKeyStyle.Render("[hjkl/↑↓]") + " Navigate",
+		actionKeyStyle.Render("[Enter]") + " Explore",
+		copyKeyStyle.Render("[yy]") + " Copy",
+		searchKeyStyle.Render("[/]") + " Search",
+		quitKeyStyle.Render("[q]") + " Quit",
+		lipgloss.NewStyle().Foreground(primaryYellow).Render("★") + " = Favourite",
+	}
+
+	return renderShortcutFooter(shortcuts, footerStyle)
+}
+
+// renderDatasetDetailFooter renders the dataset detail footer with shortcuts
+func (m *browserModel) renderDatasetDetailFooter(footerStyle lipgloss.Style) string {
+	shortcuts := []string{
+		copyKeyStyle.Render("[yy]") + " Copy",
+		backKeyStyle.Render("[b]") + " Back",
+		quitKeyStyle.Render("[q]") + " Quit",
+	}
+
+	return renderShortcutFooter(shortcuts, footerStyle)
+}
+
+// renderDatasetListFooter renders the normal dataset list footer with shortcuts
+func (m *browserModel) renderDatasetListFooter(footerStyle lipgloss.Style) string {
+	shortcuts := []string{
+		navKeyStyle.Render("[hjkl/↑↓]") + " Navigate",
+		actionKeyStyle.Render("[Enter]") + " Explore",
+		actionKeyStyle.Render("[i]") + " Info",
+		copyKeyStyle.Render("[yy]") + " Copy",
+		searchKeyStyle.Render("[/]") + " Search",
+	}
+	if m.homeLevel {
+		shortcuts = append(shortcuts, backKeyStyle.Render("[b]")+" Back")
+	}
+	shortcuts = append(shortcuts, quitKeyStyle.Render("[q]")+" Quit")
+
+	return renderShortcutFooter(shortcuts, footerStyle)
+}
+
 // renderTableListFooter renders the normal table list footer with shortcuts
 func (m *browserModel) renderTableListFooter(footerStyle lipgloss.Style) string {
 	// Color-coded shortcuts (using reusable styles)
 	shortcuts := []string{
 		navKeyStyle.Render("[hjkl/↑↓]") + " Navigate",
 		actionKeyStyle.Render("[Enter]") + " Explore",
+		actionKeyStyle.Render("[i]") + " Info",
+		actionKeyStyle.Render("[w]") + " Warm",
+		actionKeyStyle.Render("[S]") + " Stale",
 		copyKeyStyle.Render("[yy]") + " Copy",
 		exportKeyStyle.Render("[e]") + " Export",
 		searchKeyStyle.Render("[/]") + " Search",
-		quitKeyStyle.Render("[q]") + " Quit",
-		lipgloss.NewStyle().Foreground(cachedColor).Render("✓") + " = Cached",
 	}
+	if m.projectLevel {
+		shortcuts = append(shortcuts, backKeyStyle.Render("[b]")+" Back")
+	}
+	shortcuts = append(shortcuts,
+		quitKeyStyle.Render("[q]")+" Quit",
+		lipgloss.NewStyle().Foreground(cachedColor).Render("✓")+" = Cached",
+	)
 	
 	return renderShortcutFooter(shortcuts, footerStyle)
 }
@@ -467,9 +1480,84 @@ func (m *browserModel) renderTableDetailFooter(footerStyle lipgloss.Style) strin
 		searchKeyStyle.Render("[/]") + " Search",
 		copyKeyStyle.Render("[yy]") + " Copy",
 		exportKeyStyle.Render("[e]") + " Export",
-		backKeyStyle.Render("[b]") + " Back",
-		quitKeyStyle.Render("[q]") + " Quit",
 	}
+	if m.metadata != nil && m.metadata.DefinitionQuery() != "" {
+		shortcuts = append(shortcuts, actionKeyStyle.Render("[v]")+" SQL")
+	}
+	if m.metadata != nil && bigquery.Previewable(m.metadata.Type) {
+		shortcuts = append(shortcuts, actionKeyStyle.Render("[p]")+" Preview")
+	}
+	shortcuts = append(shortcuts, actionKeyStyle.Render("[L]")+" Lineage")
+	if m.metadata != nil && m.metadata.Partitioned() {
+		shortcuts = append(shortcuts, actionKeyStyle.Render("[P]")+" Partitions")
+	}
+	shortcuts = append(shortcuts, actionKeyStyle.Render("[A]")+" Activity")
+	shortcuts = append(shortcuts,
+		backKeyStyle.Render("[b]")+" Back",
+		quitKeyStyle.Render("[q]")+" Quit",
+	)
 	
 	return renderShortcutFooter(shortcuts, footerStyle)
 }
+
+// renderViewSQLFooter renders the view SQL pane footer with shortcuts
+func (m *browserModel) renderViewSQLFooter(footerStyle lipgloss.Style) string {
+	shortcuts := []string{
+		navKeyStyle.Render("[jk/↑↓]") + " Scroll",
+		copyKeyStyle.Render("[yy]") + " Copy SQL",
+		backKeyStyle.Render("[v/b]") + " Back",
+		quitKeyStyle.Render("[q]") + " Quit",
+	}
+
+	return renderShortcutFooter(shortcuts, footerStyle)
+}
+
+// renderPreviewFooter renders the preview grid footer with shortcuts
+func (m *browserModel) renderPreviewFooter(footerStyle lipgloss.Style) string {
+	shortcuts := []string{
+		navKeyStyle.Render("[hjkl/↑↓←→]") + " Move",
+		actionKeyStyle.Render("[Space]") + " Expand",
+		copyKeyStyle.Render("[yy]") + " Copy cell",
+		backKeyStyle.Render("[p/b]") + " Back",
+		quitKeyStyle.Render("[q]") + " Quit",
+	}
+
+	return renderShortcutFooter(shortcuts, footerStyle)
+}
+
+// renderLineageFooter renders the lineage pane footer with shortcuts
+func (m *browserModel) renderLineageFooter(footerStyle lipgloss.Style) string {
+	shortcuts := []string{
+		navKeyStyle.Render("[jk/↑↓]") + " Navigate",
+		actionKeyStyle.Render("[Enter]") + " Open",
+		copyKeyStyle.Render("[yy]") + " Copy",
+		backKeyStyle.Render("[L/b]") + " Back",
+		quitKeyStyle.Render("[q]") + " Quit",
+	}
+
+	return renderShortcutFooter(shortcuts, footerStyle)
+}
+
+// renderPartitionsFooter renders the partitions pane footer with shortcuts
+func (m *browserModel) renderPartitionsFooter(footerStyle lipgloss.Style) string {
+	shortcuts := []string{
+		navKeyStyle.Render("[jk/↑↓]") + " Navigate",
+		copyKeyStyle.Render("[yy]") + " Copy",
+		backKeyStyle.Render("[P/b]") + " Back",
+		quitKeyStyle.Render("[q]") + " Quit",
+	}
+
+	return renderShortcutFooter(shortcuts, footerStyle)
+}
+
+// renderActivityFooter renders the activity pane footer with shortcuts
+func (m *browserModel) renderActivityFooter(footerStyle lipgloss.Style) string {
+	shortcuts := []string{
+		navKeyStyle.Render("[jk/↑↓]") + " Navigate",
+		copyKeyStyle.Render("[yy]") + " Copy job ID",
+		backKeyStyle.Render("[A/b]") + " Back",
+		quitKeyStyle.Render("[q]") + " Quit",
+	}
+
+	return renderShortcutFooter(shortcuts, footerStyle)
+}
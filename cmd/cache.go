@@ -2,10 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"bqs/internal/bigquery"
+	"bqs/internal/errors"
 	"bqs/internal/utils"
+	"bqs/internal/validation"
 )
 
 var cacheCmd = &cobra.Command{
@@ -38,8 +42,21 @@ var cacheCleanupCmd = &cobra.Command{
 	RunE:  runCacheCleanup,
 }
 
+var cacheWarmCmd = &cobra.Command{
+	Use:   "warm <project.dataset>",
+	Short: "Cache schemas of every table in a dataset",
+	Long: `Load metadata and schemas for every table in a dataset with a single
+INFORMATION_SCHEMA query and cache them, instead of one call per table.
+
+Tables that are already cached are left alone. The query is billed like any
+other INFORMATION_SCHEMA query (at least 10 MB).`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCacheWarm,
+}
+
 func init() {
 	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheWarmCmd)
 	cacheCmd.AddCommand(cacheStatsCmd)
 	cacheCmd.AddCommand(cacheClearCmd)
 	cacheCmd.AddCommand(cacheCleanupCmd)
@@ -127,3 +144,37 @@ func runCacheCleanup(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+
+func runCacheWarm(cmd *cobra.Command, args []string) error {
+	if err := validation.ValidateProjectDatasetTable(args[0]); err != nil {
+		return fmt.Errorf("invalid input: %w", err)
+	}
+	parts := strings.Split(args[0], ".")
+	if len(parts) != 2 {
+		return fmt.Errorf("cache warm requires project.dataset format, got %s", args[0])
+	}
+	project, dataset := parts[0], parts[1]
+
+	c, err := utils.NewCache()
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache: %w", err)
+	}
+	defer c.Close()
+
+	ctx, cancel := withOperationTimeout(cmd.Context())
+	defer cancel()
+
+	cached, total, err := bigquery.NewClient(c).WarmDatasetCache(ctx, project, dataset)
+	if err != nil {
+		if bqsErr, ok := err.(*errors.BQSError); ok {
+			return fmt.Errorf("%s", bqsErr.UserFriendlyMessage())
+		}
+		return err
+	}
+
+	fmt.Printf("Cached %d of %d tables in %s.%s\n", cached, total, project, dataset)
+	if skipped := total - cached; skipped > 0 {
+		fmt.Printf("%d already cached\n", skipped)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	prettytable "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"bqs/internal/bigquery"
+	"bqs/internal/utils"
+	"bqs/internal/validation"
+)
+
+var (
+	columnLineageFormat string
+	columnLineageSource string
+)
+
+var columnLineageCmd = &cobra.Command{
+	Use:   "column-lineage [flags] <project.dataset.view>",
+	Short: "Show which source columns feed each column of a view",
+	Long: `Trace each output column of a view or materialized view to the columns it
+is computed from, through aliases, CTEs, subqueries, UNNEST and SELECT *.
+
+Source schemas are read from the cache where possible to expand SELECT * and
+place unqualified columns. Columns computed only from constants have no
+sources. Use --source to list the columns that would be affected by dropping
+or changing a source column.
+
+Common usage:
+  bqs column-lineage project.dataset.view                        # Every column
+  bqs column-lineage --source raw.events.user_id project.ds.view # Impact of one column
+  bqs column-lineage -f json project.dataset.view`,
+	Args: cobra.ExactArgs(1),
+	RunE: runColumnLineage,
+}
+
+func init() {
+	rootCmd.AddCommand(columnLineageCmd)
+
+	columnLineageCmd.Flags().StringVarP(&columnLineageFormat, "format", "f", "table", "Output format: table, json")
+	columnLineageCmd.Flags().StringVar(&columnLineageSource, "source", "", "Only show columns reading this [project.]dataset.table.column")
+}
+
+func runColumnLineage(cmd *cobra.Command, args []string) error {
+	if err := validation.ValidateProjectDatasetTable(args[0]); err != nil {
+		return fmt.Errorf("invalid input: %w", err)
+	}
+	parts := strings.Split(args[0], ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("column-lineage requires project.dataset.view format, got %s", args[0])
+	}
+	switch columnLineageFormat {
+	case "table", "json":
+	default:
+		return fmt.Errorf("unsupported format %q: use table or json", columnLineageFormat)
+	}
+	if columnLineageSource != "" && strings.Count(columnLineageSource, ".") < 2 {
+		return fmt.Errorf("invalid --source %q: expected [project.]dataset.table.column", columnLineageSource)
+	}
+
+	c, err := utils.NewCache()
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache: %w", err)
+	}
+	defer c.Close()
+
+	ctx, cancel := withOperationTimeout(cmd.Context())
+	defer cancel()
+
+	columns, err := bigquery.NewClient(c).ColumnLineage(ctx, parts[0], parts[1], parts[2])
+	if err != nil {
+		return queryError(err)
+	}
+
+	if columnLineageSource != "" {
+		columns = columnsReading(columns, parts[0], columnLineageSource)
+		if len(columns) == 0 && columnLineageFormat == "table" {
+			fmt.Printf("No columns of %s read %s\n", args[0], columnLineageSource)
+			return nil
+		}
+	}
+
+	if columnLineageFormat == "json" {
+		return writeColumnLineageJSON(os.Stdout, columns)
+	}
+	writeColumnLineageTable(os.Stdout, columns, parts[0])
+	return nil
+}
+
+// columnsReading keeps the columns with source among their sources. source may
+// leave out the project when it is the view's.
+func columnsReading(columns []bigquery.ViewColumn, project, source string) []bigquery.ViewColumn {
+	var matched []bigquery.ViewColumn
+	for _, column := range columns {
+		for _, s := range column.Sources {
+			if strings.EqualFold(s.String(), source) || strings.EqualFold(s.ShortName(project), source) {
+				matched = append(matched, column)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// writeColumnLineageTable prints one row per view column with its sources,
+// leaving out the project when it is the view's
+func writeColumnLineageTable(w io.Writer, columns []bigquery.ViewColumn, project string) {
+	t := prettytable.NewWriter()
+	t.SetStyle(prettytable.StyleRounded)
+	t.AppendHeader(prettytable.Row{"Column", "Sources"})
+
+	for _, column := range columns {
+		sources := make([]string, 0, len(column.Sources))
+		for _, source := range column.Sources {
+			sources = append(sources, source.ShortName(project))
+		}
+		if len(sources) == 0 {
+			sources = append(sources, "—")
+		}
+		t.AppendRow(prettytable.Row{column.Name, strings.Join(sources, "\n")})
+	}
+
+	fmt.Fprintln(w, t.Render())
+}
+
+// writeColumnLineageJSON prints the columns as a JSON array with fully
+// qualified sources
+func writeColumnLineageJSON(w io.Writer, columns []bigquery.ViewColumn) error {
+	type columnJSON struct {
+		Column  string   `json:"column"`
+		Sources []string `json:"sources"`
+	}
+	out := make([]columnJSON, 0, len(columns))
+	for _, column := range columns {
+		entry := columnJSON{Column: column.Name, Sources: []string{}}
+		for _, source := range column.Sources {
+			entry.Sources = append(entry.Sources, source.String())
+		}
+		out = append(out, entry)
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode column lineage: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	prettytable "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"bqs/internal/bigquery"
+	"bqs/internal/utils"
+	"bqs/internal/validation"
+)
+
+var (
+	duDepth  int
+	duFormat string
+)
+
+var duCmd = &cobra.Command{
+	Use:   "du [flags] <project[.dataset]>",
+	Short: "Summarize storage per dataset and table",
+	Long: `Summarize the storage of a project or dataset from
+INFORMATION_SCHEMA.TABLE_STORAGE: active and long-term logical bytes, physical
+bytes, and the time travel and fail-safe bytes of changed and dropped data.
+
+Like du, each level is listed largest first with the total last, and --depth
+limits how far below the argument to go: 0 shows only the total, 1 datasets
+(or tables of a dataset), 2 the tables of every dataset. The monthly cost is
+estimated under both the logical and the physical storage billing model at US
+multi-region list prices, so you can see which one is cheaper.
+
+TABLE_STORAGE is queried once per region the datasets live in and cached.
+Its numbers can lag behind recent changes by a few hours.
+
+Common usage:
+  bqs du my-project                # Storage per dataset
+  bqs du --depth 2 my-project      # ... and per table
+  bqs du my-project.analytics      # Storage per table of one dataset
+  bqs du -f json my-project`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDu,
+}
+
+func init() {
+	rootCmd.AddCommand(duCmd)
+
+	duCmd.Flags().IntVarP(&duDepth, "depth", "d", 1, "Levels below the argument to show (0 for the total only)")
+	duCmd.Flags().StringVarP(&duFormat, "format", "f", "table", "Output format: table, json")
+}
+
+func runDu(cmd *cobra.Command, args []string) error {
+	if err := validation.ValidateResourcePath(args[0]); err != nil {
+		return fmt.Errorf("invalid input: %w", err)
+	}
+	parts := strings.Split(args[0], ".")
+	if len(parts) > 2 {
+		return fmt.Errorf("du requires project or project.dataset format, got %s", args[0])
+	}
+	if duDepth < 0 {
+		return fmt.Errorf("--depth must not be negative, got %d", duDepth)
+	}
+	switch duFormat {
+	case "table", "json":
+	default:
+		return fmt.Errorf("unsupported format %q: use table or json", duFormat)
+	}
+	project, dataset := parts[0], ""
+	if len(parts) == 2 {
+		dataset = parts[1]
+	}
+
+	c, err := utils.NewCache()
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache: %w", err)
+	}
+	defer c.Close()
+
+	ctx, cancel := withOperationTimeout(cmd.Context())
+	defer cancel()
+
+	tables, err := bigquery.NewClient(c).ListTableStorage(ctx, project, dataset)
+	if err != nil {
+		return queryError(err)
+	}
+
+	root := bigquery.StorageTree(project, tables)
+	if dataset != "" {
+		// The dataset is the only child, unless it has no tables
+		root = bigquery.StorageUsage{Name: dataset}
+		if len(tables) > 0 {
+			root = bigquery.StorageTree(project, tables).Children[0]
+		}
+		root.Name = project + "." + dataset
+	}
+
+	if duFormat == "json" {
+		return writeDuJSON(os.Stdout, root, duDepth)
+	}
+	if len(tables) == 0 {
+		fmt.Printf("No table storage found in %s\n", args[0])
+		return nil
+	}
+	writeDuTable(os.Stdout, root, duDepth)
+	return nil
+}
+
+// writeDuTable prints the usage below root down to depth, largest first and
+// indented by level, with root's total last
+func writeDuTable(w io.Writer, root bigquery.StorageUsage, depth int) {
+	t := prettytable.NewWriter()
+	t.SetStyle(prettytable.StyleRounded)
+	t.AppendHeader(prettytable.Row{"Name", "Logical", "Active", "Long-term", "Physical", "Time travel", "Fail-safe", "Logical $/mo", "Physical $/mo"})
+
+	row := func(name string, u bigquery.StorageUsage) prettytable.Row {
+		if u.Deleted {
+			name += " (deleted)"
+		}
+		return prettytable.Row{name,
+			bigquery.FormatSize(u.LogicalBytes()),
+			bigquery.FormatSize(u.ActiveLogicalBytes),
+			bigquery.FormatSize(u.LongTermLogicalBytes),
+			bigquery.FormatSize(u.PhysicalBytes()),
+			bigquery.FormatSize(u.TimeTravelPhysicalBytes),
+			bigquery.FormatSize(u.FailSafePhysicalBytes),
+			fmt.Sprintf("$%.2f", u.LogicalCost()),
+			fmt.Sprintf("$%.2f", u.PhysicalCost()),
+		}
+	}
+
+	var walk func(usage []bigquery.StorageUsage, level int)
+	walk = func(usage []bigquery.StorageUsage, level int) {
+		if level > depth {
+			return
+		}
+		for _, u := range usage {
+			t.AppendRow(row(strings.Repeat("  ", level-1)+u.Name, u))
+			walk(u.Children, level+1)
+		}
+	}
+	walk(root.Children, 1)
+	if depth > 0 {
+		t.AppendSeparator()
+	}
+	t.AppendRow(row(root.Name, root))
+
+	fmt.Fprintln(w, t.Render())
+	fmt.Fprintln(w, "Costs are monthly estimates at US multi-region list prices, before the free tier.")
+	fmt.Fprintln(w, "Time travel and fail-safe bytes are only billed under the physical billing model.")
+}
+
+// writeDuJSON prints root and the usage below it down to depth as a JSON
+// array, parents before their children
+func writeDuJSON(w io.Writer, root bigquery.StorageUsage, depth int) error {
+	type usageJSON struct {
+		Name                    string  `json:"name"`
+		Deleted                 bool    `json:"deleted,omitempty"`
+		Rows                    int64   `json:"rows"`
+		LogicalBytes            int64   `json:"logical_bytes"`
+		ActiveLogicalBytes      int64   `json:"active_logical_bytes"`
+		LongTermLogicalBytes    int64   `json:"long_term_logical_bytes"`
+		PhysicalBytes           int64   `json:"physical_bytes"`
+		ActivePhysicalBytes     int64   `json:"active_physical_bytes"`
+		LongTermPhysicalBytes   int64   `json:"long_term_physical_bytes"`
+		TimeTravelPhysicalBytes int64   `json:"time_travel_physical_bytes"`
+		FailSafePhysicalBytes   int64   `json:"fail_safe_physical_bytes"`
+		LogicalCostUSD          float64 `json:"logical_cost_usd"`
+		PhysicalCostUSD         float64 `json:"physical_cost_usd"`
+	}
+	out := []usageJSON{}
+	var walk func(u bigquery.StorageUsage, name string, level int)
+	walk = func(u bigquery.StorageUsage, name string, level int) {
+		out = append(out, usageJSON{
+			Name:                    name,
+			Deleted:                 u.Deleted,
+			Rows:                    u.Rows,
+			LogicalBytes:            u.LogicalBytes(),
+			ActiveLogicalBytes:      u.ActiveLogicalBytes,
+			LongTermLogicalBytes:    u.LongTermLogicalBytes,
+			PhysicalBytes:           u.PhysicalBytes(),
+			ActivePhysicalBytes:     u.ActivePhysicalBytes,
+			LongTermPhysicalBytes:   u.LongTermPhysicalBytes,
+			TimeTravelPhysicalBytes: u.TimeTravelPhysicalBytes,
+			FailSafePhysicalBytes:   u.FailSafePhysicalBytes,
+			LogicalCostUSD:          math.Round(u.LogicalCost()*100) / 100,
+			PhysicalCostUSD:         math.Round(u.PhysicalCost()*100) / 100,
+		})
+		if level < depth {
+			for _, child := range u.Children {
+				walk(child, name+"."+child.Name, level+1)
+			}
+		}
+	}
+	walk(root, root.Name, 0)
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode storage usage: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"bqs/internal/bigquery"
+	"bqs/internal/config"
+	"bqs/internal/errors"
+	"bqs/internal/utils"
+	"bqs/internal/validation"
+)
+
+var (
+	headRows   int
+	headFormat string
+)
+
+var headCmd = &cobra.Command{
+	Use:   "head [flags] <project.dataset.table>",
+	Short: "Print the first rows of a table",
+	Long: `Print the first rows of a table without running a query.
+
+Rows are read with tabledata.list (bq head), which is free: no bytes are
+billed. Views and materialized views can't be read this way.
+
+Common usage:
+  bqs head project.dataset.table              # First 20 rows as a table
+  bqs head -n 5 -f json project.dataset.table # First 5 rows as JSON
+  bqs head -f csv project.dataset.table       # CSV, nested values as JSON`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHead,
+}
+
+func init() {
+	rootCmd.AddCommand(headCmd)
+
+	headCmd.Flags().IntVarP(&headRows, "rows", "n", config.HeadDefaultRows, "Number of rows to print")
+	headCmd.Flags().StringVarP(&headFormat, "format", "f", "table", "Output format: table, json, csv")
+}
+
+func runHead(cmd *cobra.Command, args []string) error {
+	if err := validation.ValidateProjectDatasetTable(args[0]); err != nil {
+		return fmt.Errorf("invalid input: %w", err)
+	}
+	parts := strings.Split(args[0], ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("head requires project.dataset.table format, got %s", args[0])
+	}
+	if headRows <= 0 {
+		return fmt.Errorf("--rows must be positive, got %d", headRows)
+	}
+	switch headFormat {
+	case "table", "json", "csv":
+	default:
+		return fmt.Errorf("unsupported format %q: use table, json or csv", headFormat)
+	}
+
+	c, err := utils.NewCache()
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache: %w", err)
+	}
+	defer c.Close()
+
+	ctx, cancel := withOperationTimeout(cmd.Context())
+	defer cancel()
+
+	preview, err := bigquery.NewClient(c).PreviewTable(ctx, parts[0], parts[1], parts[2], headRows)
+	if err != nil {
+		if bqsErr, ok := err.(*errors.BQSError); ok {
+			return fmt.Errorf("%s", bqsErr.UserFriendlyMessage())
+		}
+		return err
+	}
+
+	switch headFormat {
+	case "json":
+		return writeRowsJSON(os.Stdout, preview)
+	case "csv":
+		return writeRowsCSV(os.Stdout, preview)
+	default:
+		writeRowsTable(os.Stdout, preview)
+		return nil
+	}
+}
@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"bqs/internal/bigquery"
+	"bqs/internal/config"
+	"bqs/internal/utils"
+	"bqs/internal/validation"
+)
+
+var (
+	lineageUpstream   bool
+	lineageDownstream bool
+	lineageDepth      int
+	lineageFormat     string
+	lineageScan       []string
+)
+
+var lineageCmd = &cobra.Command{
+	Use:   "lineage [flags] <project.dataset.table>",
+	Short: "Show which tables a table reads from and which views read it",
+	Long: `Show table-level lineage parsed from the SQL of views and materialized views.
+
+Upstream lineage follows each view's definition to the tables it reads.
+Downstream lineage finds the views reading a table among the datasets scanned:
+the table's own dataset, the dataset of every view found on the way, and any
+dataset passed with --scan. Definitions come from cached metadata where
+possible, so repeated runs are cheap.
+
+Common usage:
+  bqs lineage project.dataset.view                  # Both directions as a tree
+  bqs lineage --upstream --depth 1 project.ds.view  # Direct sources only
+  bqs lineage --downstream --scan project.mart project.raw.events
+  bqs lineage -f dot project.dataset.view | dot -Tsvg > lineage.svg
+  bqs lineage -f mermaid project.dataset.view       # Paste into Markdown`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLineage,
+}
+
+func init() {
+	rootCmd.AddCommand(lineageCmd)
+
+	lineageCmd.Flags().BoolVar(&lineageUpstream, "upstream", false, "Show the tables this table reads from")
+	lineageCmd.Flags().BoolVar(&lineageDownstream, "downstream", false, "Show the views reading this table")
+	lineageCmd.Flags().IntVar(&lineageDepth, "depth", config.LineageDefaultDepth, "Maximum number of levels to walk")
+	lineageCmd.Flags().StringVarP(&lineageFormat, "format", "f", "text", "Output format: text, dot, mermaid")
+	lineageCmd.Flags().StringSliceVar(&lineageScan, "scan", nil, "Extra project.dataset to search for downstream views (repeatable)")
+}
+
+func runLineage(cmd *cobra.Command, args []string) error {
+	if err := validation.ValidateProjectDatasetTable(args[0]); err != nil {
+		return fmt.Errorf("invalid input: %w", err)
+	}
+	parts := strings.Split(args[0], ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("lineage requires project.dataset.table format, got %s", args[0])
+	}
+	if lineageDepth <= 0 {
+		return fmt.Errorf("--depth must be positive, got %d", lineageDepth)
+	}
+	switch lineageFormat {
+	case "text", "dot", "mermaid":
+	default:
+		return fmt.Errorf("unsupported format %q: use text, dot or mermaid", lineageFormat)
+	}
+	for _, dataset := range lineageScan {
+		if err := validation.ValidateProjectDatasetTable(dataset); err != nil || strings.Count(dataset, ".") != 1 {
+			return fmt.Errorf("invalid --scan %q: expected project.dataset", dataset)
+		}
+	}
+
+	// Neither flag means both directions
+	var directions []bigquery.LineageDirection
+	if lineageUpstream || !lineageDownstream {
+		directions = append(directions, bigquery.LineageUpstream)
+	}
+	if lineageDownstream || !lineageUpstream {
+		directions = append(directions, bigquery.LineageDownstream)
+	}
+
+	c, err := utils.NewCache()
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache: %w", err)
+	}
+	defer c.Close()
+	client := bigquery.NewClient(c)
+
+	ctx, cancel := withOperationTimeout(cmd.Context())
+	defer cancel()
+
+	root := bigquery.TableReference{ProjectID: parts[0], DatasetID: parts[1], TableID: parts[2]}
+	graph := bigquery.NewLineageGraph()
+	for _, dataset := range lineageScan {
+		scan := strings.Split(dataset, ".")
+		if err := client.ScanDatasetLineage(ctx, graph, scan[0], scan[1]); err != nil {
+			return queryError(err)
+		}
+	}
+
+	trees := make(map[bigquery.LineageDirection]*bigquery.LineageNode)
+	for _, direction := range directions {
+		if err := client.TraceLineage(ctx, graph, root, direction, lineageDepth); err != nil {
+			return queryError(err)
+		}
+	}
+	// Build the trees once every walk has filled in the graph
+	for _, direction := range directions {
+		trees[direction] = graph.Tree(root, direction, lineageDepth)
+	}
+
+	switch lineageFormat {
+	case "dot":
+		writeLineageDOT(os.Stdout, root, trees)
+	case "mermaid":
+		writeLineageMermaid(os.Stdout, root, trees)
+	default:
+		writeLineageText(os.Stdout, directions, trees)
+	}
+	return nil
+}
+
+// lineageLabel returns a table name with its type, when known
+func lineageLabel(node *bigquery.LineageNode) string {
+	if node.Type == "" {
+		return node.Ref.String()
+	}
+	return fmt.Sprintf("%s [%s]", node.Ref.String(), node.Type)
+}
+
+// writeLineageText prints each direction as an indented tree
+func writeLineageText(w io.Writer, directions []bigquery.LineageDirection, trees map[bigquery.LineageDirection]*bigquery.LineageNode) {
+	for i, direction := range directions {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		tree := trees[direction]
+		if direction == bigquery.LineageUpstream {
+			fmt.Fprintln(w, "Upstream (reads from):")
+		} else {
+			fmt.Fprintln(w, "Downstream (read by):")
+		}
+		fmt.Fprintln(w, lineageLabel(tree))
+		if len(tree.Children) == 0 {
+			fmt.Fprintln(w, "  (none found)")
+			continue
+		}
+		writeLineageChildren(w, tree.Children, "")
+	}
+}
+
+func writeLineageChildren(w io.Writer, children []*bigquery.LineageNode, prefix string) {
+	for i, child := range children {
+		connector, indent := "├── ", "│   "
+		if i == len(children)-1 {
+			connector, indent = "└── ", "    "
+		}
+		label := lineageLabel(child)
+		if child.Repeated {
+			label += " (see above)"
+		}
+		fmt.Fprintln(w, prefix+connector+label)
+		writeLineageChildren(w, child.Children, prefix+indent)
+	}
+}
+
+// lineageEdge is a data flow from a source table to a view reading it
+type lineageEdge struct {
+	from, to string
+}
+
+// collectLineage flattens the trees into their tables and data-flow edges,
+// both sorted so the output is stable
+func collectLineage(root bigquery.TableReference, trees map[bigquery.LineageDirection]*bigquery.LineageNode) (map[string]*bigquery.LineageNode, []string, []lineageEdge) {
+	nodes := map[string]*bigquery.LineageNode{}
+	edges := map[lineageEdge]bool{}
+
+	var walk func(node *bigquery.LineageNode, direction bigquery.LineageDirection)
+	walk = func(node *bigquery.LineageNode, direction bigquery.LineageDirection) {
+		if existing, ok := nodes[node.Ref.String()]; !ok || existing.Type == "" {
+			nodes[node.Ref.String()] = node
+		}
+		for _, child := range node.Children {
+			if direction == bigquery.LineageUpstream {
+				edges[lineageEdge{child.Ref.String(), node.Ref.String()}] = true
+			} else {
+				edges[lineageEdge{node.Ref.String(), child.Ref.String()}] = true
+			}
+			walk(child, direction)
+		}
+	}
+	for direction, tree := range trees {
+		walk(tree, direction)
+	}
+
+	ids := make([]string, 0, len(nodes))
+	for id := range nodes {
+		if id != root.String() {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	ids = append([]string{root.String()}, ids...)
+
+	sortedEdges := make([]lineageEdge, 0, len(edges))
+	for edge := range edges {
+		sortedEdges = append(sortedEdges, edge)
+	}
+	sort.Slice(sortedEdges, func(i, j int) bool {
+		if sortedEdges[i].from != sortedEdges[j].from {
+			return sortedEdges[i].from < sortedEdges[j].from
+		}
+		return sortedEdges[i].to < sortedEdges[j].to
+	})
+
+	return nodes, ids, sortedEdges
+}
+
+// writeLineageDOT prints the lineage as a Graphviz digraph, data flowing left to right
+func writeLineageDOT(w io.Writer, root bigquery.TableReference, trees map[bigquery.LineageDirection]*bigquery.LineageNode) {
+	nodes, ids, edges := collectLineage(root, trees)
+
+	fmt.Fprintln(w, "digraph lineage {")
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintln(w, "  node [shape=box, fontname=\"Helvetica\"];")
+	for _, id := range ids {
+		attrs := []string{fmt.Sprintf("label=%q", strings.ReplaceAll(lineageLabel(nodes[id]), " [", "\n["))}
+		switch nodes[id].Type {
+		case "VIEW", "MATERIALIZED_VIEW":
+			attrs = append(attrs, "style=rounded")
+		}
+		if id == root.String() {
+			attrs = append(attrs, "penwidth=2")
+		}
+		fmt.Fprintf(w, "  %q [%s];\n", id, strings.Join(attrs, ", "))
+	}
+	for _, edge := range edges {
+		fmt.Fprintf(w, "  %q -> %q;\n", edge.from, edge.to)
+	}
+	fmt.Fprintln(w, "}")
+}
+
+// writeLineageMermaid prints the lineage as a Mermaid flowchart. Mermaid node
+// IDs can't contain dots or dashes, so tables are numbered and labelled.
+func writeLineageMermaid(w io.Writer, root bigquery.TableReference, trees map[bigquery.LineageDirection]*bigquery.LineageNode) {
+	nodes, ids, edges := collectLineage(root, trees)
+
+	fmt.Fprintln(w, "flowchart LR")
+	index := make(map[string]string, len(ids))
+	for i, id := range ids {
+		index[id] = fmt.Sprintf("t%d", i)
+		label := strings.ReplaceAll(lineageLabel(nodes[id]), `"`, "#quot;")
+		switch nodes[id].Type {
+		case "VIEW", "MATERIALIZED_VIEW":
+			fmt.Fprintf(w, "  %s(\"%s\")\n", index[id], label)
+		default:
+			fmt.Fprintf(w, "  %s[\"%s\"]\n", index[id], label)
+		}
+	}
+	for _, edge := range edges {
+		fmt.Fprintf(w, "  %s --> %s\n", index[edge.from], index[edge.to])
+	}
+	fmt.Fprintf(w, "  style %s stroke-width:3px\n", index[root.String()])
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	prettytable "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"bqs/internal/bigquery"
+	"bqs/internal/utils"
+	"bqs/internal/validation"
+)
+
+var (
+	partitionsFormat    string
+	partitionsSince     string
+	partitionsEmpty     bool
+	partitionsTopBySize int
+)
+
+var partitionsCmd = &cobra.Command{
+	Use:   "partitions [flags] <project.dataset.table>",
+	Short: "List the partitions of a table",
+	Long: `List the partitions of a table with their row counts, logical and billable
+bytes, last modified time and storage tier, from INFORMATION_SCHEMA.PARTITIONS.
+
+Partition lists are cached per table for a few minutes. Unpartitioned tables
+have a single partition. Missing days simply have no partition; the browser's
+partition histogram makes them easier to spot.
+
+Common usage:
+  bqs partitions project.dataset.table                    # Every partition
+  bqs partitions --since 2026-10-01 project.dataset.table # Recent time partitions
+  bqs partitions --empty project.dataset.table            # Partitions without rows
+  bqs partitions --top-by-size 10 project.dataset.table   # Largest partitions
+  bqs partitions -f json project.dataset.table`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPartitions,
+}
+
+func init() {
+	rootCmd.AddCommand(partitionsCmd)
+
+	partitionsCmd.Flags().StringVarP(&partitionsFormat, "format", "f", "table", "Output format: table, json")
+	partitionsCmd.Flags().StringVar(&partitionsSince, "since", "", "Only show time partitions starting at or after a date (2006-01-02) or RFC 3339 time")
+	partitionsCmd.Flags().BoolVar(&partitionsEmpty, "empty", false, "Only show partitions without rows")
+	partitionsCmd.Flags().IntVar(&partitionsTopBySize, "top-by-size", 0, "Only show the N largest partitions by logical bytes, largest first")
+}
+
+func runPartitions(cmd *cobra.Command, args []string) error {
+	if err := validation.ValidateProjectDatasetTable(args[0]); err != nil {
+		return fmt.Errorf("invalid input: %w", err)
+	}
+	parts := strings.Split(args[0], ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("partitions requires project.dataset.table format, got %s", args[0])
+	}
+	switch partitionsFormat {
+	case "table", "json":
+	default:
+		return fmt.Errorf("unsupported format %q: use table or json", partitionsFormat)
+	}
+	if partitionsTopBySize < 0 {
+		return fmt.Errorf("invalid --top-by-size %d: must be positive", partitionsTopBySize)
+	}
+	filter := bigquery.PartitionFilter{EmptyOnly: partitionsEmpty, TopBySize: partitionsTopBySize}
+	if partitionsSince != "" {
+		since, err := parseSince(partitionsSince)
+		if err != nil {
+			return err
+		}
+		filter.Since = since
+	}
+
+	c, err := utils.NewCache()
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache: %w", err)
+	}
+	defer c.Close()
+
+	ctx, cancel := withOperationTimeout(cmd.Context())
+	defer cancel()
+
+	client := bigquery.NewClient(c)
+	if !filter.Since.IsZero() {
+		// Integer range partition IDs would parse as years
+		metadata, err := client.GetTableMetadata(ctx, parts[0], parts[1], parts[2])
+		if err != nil {
+			return queryError(err)
+		}
+		if metadata.TimePartitioning == nil {
+			return fmt.Errorf("--since needs a time-partitioned table, %s is not", args[0])
+		}
+	}
+
+	partitions, err := client.ListPartitions(ctx, parts[0], parts[1], parts[2])
+	if err != nil {
+		return queryError(err)
+	}
+	shown := bigquery.FilterPartitions(partitions, filter)
+
+	if partitionsFormat == "json" {
+		return writePartitionsJSON(os.Stdout, shown)
+	}
+	if len(shown) == 0 {
+		fmt.Printf("No partitions of %s match\n", args[0])
+		return nil
+	}
+	writePartitionsTable(os.Stdout, shown, len(partitions))
+	return nil
+}
+
+// parseSince accepts a date or an RFC 3339 time
+func parseSince(value string) (time.Time, error) {
+	if since, err := time.Parse("2006-01-02", value); err == nil {
+		return since, nil
+	}
+	since, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q: expected a date such as 2026-10-01 or an RFC 3339 time", value)
+	}
+	return since, nil
+}
+
+// writePartitionsTable prints one row per partition and a total line
+func writePartitionsTable(w io.Writer, partitions []bigquery.PartitionInfo, total int) {
+	t := prettytable.NewWriter()
+	t.SetStyle(prettytable.StyleRounded)
+	t.AppendHeader(prettytable.Row{"Partition", "Rows", "Logical", "Billable", "Modified", "Tier"})
+
+	var rows, logical, billable int64
+	for _, p := range partitions {
+		id := p.PartitionID
+		if id == "" {
+			id = "(unpartitioned)"
+		}
+		t.AppendRow(prettytable.Row{id, p.TotalRows, bigquery.FormatSize(p.TotalLogicalBytes),
+			bigquery.FormatSize(p.TotalBillableBytes), bigquery.FormatTime(p.LastModifiedTime), p.StorageTier})
+		rows += p.TotalRows
+		logical += p.TotalLogicalBytes
+		billable += p.TotalBillableBytes
+	}
+
+	fmt.Fprintln(w, t.Render())
+	count := fmt.Sprintf("%d partitions", len(partitions))
+	if len(partitions) != total {
+		count = fmt.Sprintf("%d of %d partitions", len(partitions), total)
+	}
+	fmt.Fprintf(w, "%s, %d rows, %s logical, %s billable\n", count, rows, bigquery.FormatSize(logical), bigquery.FormatSize(billable))
+}
+
+// writePartitionsJSON prints the partitions as a JSON array with numeric
+// counts, unlike the strings INFORMATION_SCHEMA returns
+func writePartitionsJSON(w io.Writer, partitions []bigquery.PartitionInfo) error {
+	type partitionJSON struct {
+		PartitionID        string `json:"partition_id"`
+		TotalRows          int64  `json:"total_rows"`
+		TotalLogicalBytes  int64  `json:"total_logical_bytes"`
+		TotalBillableBytes int64  `json:"total_billable_bytes"`
+		LastModifiedTime   string `json:"last_modified_time"`
+		StorageTier        string `json:"storage_tier"`
+	}
+	out := make([]partitionJSON, 0, len(partitions))
+	for _, p := range partitions {
+		out = append(out, partitionJSON{
+			PartitionID:        p.PartitionID,
+			TotalRows:          p.TotalRows,
+			TotalLogicalBytes:  p.TotalLogicalBytes,
+			TotalBillableBytes: p.TotalBillableBytes,
+			LastModifiedTime:   time.UnixMilli(p.LastModifiedTime).UTC().Format(time.RFC3339),
+			StorageTier:        p.StorageTier,
+		})
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode partitions: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
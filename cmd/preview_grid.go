@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"bqs/internal/bigquery"
+	"bqs/internal/config"
+)
+
+// maxExpandedLines caps the expanded JSON of a nested cell below the grid
+const maxExpandedLines = 12
+
+// previewRowHeight returns the number of grid rows visible at once
+func (m *browserModel) previewRowHeight() int {
+	height := m.height - config.PreviewPadding
+	if m.previewExpanded {
+		height -= maxExpandedLines + 2
+	}
+	if height < config.MinTableHeight {
+		height = config.MinTableHeight
+	}
+	return height
+}
+
+// previewVisibleColumns returns how many columns fit next to the row gutter
+func (m *browserModel) previewVisibleColumns() int {
+	columns := (m.width - 8) / (config.PreviewColumnWidth + 1)
+	if columns < 1 {
+		columns = 1
+	}
+	return columns
+}
+
+// selectedPreviewValue returns the value of the selected cell and its column
+func (m *browserModel) selectedPreviewValue() (interface{}, string, bool) {
+	if m.preview == nil || m.previewRow >= len(m.preview.Rows) {
+		return nil, "", false
+	}
+	columns := m.preview.Columns()
+	if m.previewCol >= len(columns) {
+		return nil, "", false
+	}
+	column := columns[m.previewCol]
+	return m.preview.Rows[m.previewRow][column], column, true
+}
+
+// collapsedCell summarises a nested value for the grid
+func collapsedCell(value interface{}) string {
+	switch v := value.(type) {
+	case []interface{}:
+		if len(v) == 0 {
+			return "[]"
+		}
+		return fmt.Sprintf("▸ [%d items]", len(v))
+	case map[string]interface{}:
+		return fmt.Sprintf("▸ {%d fields}", len(v))
+	default:
+		return bigquery.FormatCellValue(value)
+	}
+}
+
+// fitCell pads or truncates a cell value to exactly width columns
+func fitCell(value string, width int) string {
+	value = strings.ReplaceAll(value, "\n", "⏎")
+	if lipgloss.Width(value) > width {
+		runes := []rune(value)
+		for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+			runes = runes[:len(runes)-1]
+		}
+		value = string(runes) + "…"
+	}
+	return value + strings.Repeat(" ", width-lipgloss.Width(value))
+}
+
+// renderPreviewGrid renders the visible window of the preview rows. The
+// window follows the selected cell both vertically and horizontally.
+func (m *browserModel) renderPreviewGrid() string {
+	var content strings.Builder
+
+	columns := m.preview.Columns()
+	visible := m.previewVisibleColumns()
+	colOffset := 0
+	if m.previewCol >= visible {
+		colOffset = m.previewCol - visible + 1
+	}
+	colEnd := colOffset + visible
+	if colEnd > len(columns) {
+		colEnd = len(columns)
+	}
+
+	height := m.previewRowHeight()
+	rowOffset := 0
+	if m.previewRow >= height {
+		rowOffset = m.previewRow - height + 1
+	}
+	rowEnd := rowOffset + height
+	if rowEnd > len(m.preview.Rows) {
+		rowEnd = len(m.preview.Rows)
+	}
+
+	width := config.PreviewColumnWidth
+	gutterWidth := len(fmt.Sprintf("%d", len(m.preview.Rows)))
+	gutterStyle := lipgloss.NewStyle().Foreground(darkGray)
+	headerStyle := lipgloss.NewStyle().Foreground(primaryBlue).Bold(true)
+	selectedHeaderStyle := headerStyle.Underline(true)
+	nullStyle := lipgloss.NewStyle().Foreground(secondaryGray).Italic(true)
+	nestedStyle := lipgloss.NewStyle().Foreground(accentPurple)
+	rowStyle := lipgloss.NewStyle().Background(selectedBg).Foreground(selectedFg)
+	cellStyle := lipgloss.NewStyle().Background(primaryYellow).Foreground(lipgloss.Color("0")).Bold(true)
+
+	// Header with scroll hints when columns are hidden on either side
+	left, right := " ", " "
+	if colOffset > 0 {
+		left = "‹"
+	}
+	if colEnd < len(columns) {
+		right = "›"
+	}
+	content.WriteString(fmt.Sprintf("  %s%s", strings.Repeat(" ", gutterWidth), left))
+	for i := colOffset; i < colEnd; i++ {
+		style := headerStyle
+		if i == m.previewCol {
+			style = selectedHeaderStyle
+		}
+		content.WriteString(" " + style.Render(fitCell(columns[i], width)))
+	}
+	content.WriteString(right + "\n")
+
+	for r := rowOffset; r < rowEnd; r++ {
+		row := m.preview.Rows[r]
+		content.WriteString("  " + gutterStyle.Render(fmt.Sprintf("%*d", gutterWidth, r+1)) + " ")
+		for i := colOffset; i < colEnd; i++ {
+			value := row[columns[i]]
+			cell := fitCell(collapsedCell(value), width)
+			switch {
+			case r == m.previewRow && i == m.previewCol:
+				cell = cellStyle.Render(cell)
+			case r == m.previewRow:
+				cell = rowStyle.Render(cell)
+			case value == nil:
+				cell = nullStyle.Render(cell)
+			case bigquery.IsNestedValue(value):
+				cell = nestedStyle.Render(cell)
+			}
+			content.WriteString(" " + cell)
+		}
+		content.WriteString("\n")
+	}
+
+	if m.previewExpanded {
+		content.WriteString(m.renderExpandedCell())
+	}
+
+	return content.String()
+}
+
+// renderExpandedCell renders the selected cell as indented JSON
+func (m *browserModel) renderExpandedCell() string {
+	value, column, ok := m.selectedPreviewValue()
+	if !ok {
+		return ""
+	}
+
+	var content strings.Builder
+	titleStyle := lipgloss.NewStyle().Foreground(accentPurple).Bold(true).Padding(0, 1).MarginTop(1)
+	content.WriteString(titleStyle.Render(fmt.Sprintf("▾ %s (row %d)", column, m.previewRow+1)))
+	content.WriteString("\n")
+
+	text := bigquery.FormatCellValue(value)
+	if bigquery.IsNestedValue(value) {
+		if data, err := json.MarshalIndent(value, "", "  "); err == nil {
+			text = string(data)
+		}
+	}
+
+	valueStyle := lipgloss.NewStyle().Foreground(lightGray)
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if i == maxExpandedLines {
+			content.WriteString(valueStyle.Italic(true).Render(fmt.Sprintf("    … %d more lines (yy copies the full value)", len(lines)-i)))
+			content.WriteString("\n")
+			break
+		}
+		content.WriteString("    " + valueStyle.Render(line) + "\n")
+	}
+
+	return content.String()
+}
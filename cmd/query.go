@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+
+	"bqs/internal/bigquery"
+	"bqs/internal/config"
+	"bqs/internal/errors"
+	"bqs/internal/utils"
+	"bqs/internal/validation"
+)
+
+var (
+	queryProject        string
+	queryMaxBytesBilled string
+	queryRows           int
+	queryFormat         string
+	queryDryRun         bool
+	queryInteractive    bool
+	queryQuiet          bool
+)
+
+var queryCmd = &cobra.Command{
+	Use:   "query [flags] <sql>",
+	Short: "Run a GoogleSQL query after a free dry run",
+	Long: `Run an ad-hoc GoogleSQL query.
+
+Every query is dry-run first to print the bytes it will process and the
+estimated on-demand cost. Queries above --max-bytes-billed are refused without
+running, and the limit is passed on to BigQuery as well. Pass - or no SQL to
+read the query from stdin.
+
+Common usage:
+  bqs query -p my-project "SELECT ..."           # Results as a table
+  bqs query -p my-project -f csv "SELECT ..."    # CSV, nested values as JSON
+  bqs query -p my-project -i "SELECT ..."        # Interactive result grid
+  bqs query -p my-project --dry-run "SELECT ..." # Estimate only
+  bqs query --max-bytes-billed 100GB -p my-project < report.sql`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runQuery,
+}
+
+func init() {
+	rootCmd.AddCommand(queryCmd)
+
+	queryCmd.Flags().StringVarP(&queryProject, "project", "p", os.Getenv("GOOGLE_CLOUD_PROJECT"), "Project to run and bill the query in (default $GOOGLE_CLOUD_PROJECT)")
+	queryCmd.Flags().StringVar(&queryMaxBytesBilled, "max-bytes-billed", utils.FormatBytes(config.DefaultMaxBytesBilled), "Refuse queries processing more than this, e.g. 500MB, 1TB")
+	queryCmd.Flags().IntVarP(&queryRows, "rows", "n", config.QueryDefaultRows, "Maximum number of rows to fetch")
+	queryCmd.Flags().StringVarP(&queryFormat, "format", "f", "table", "Output format: table, csv, ndjson")
+	queryCmd.Flags().BoolVar(&queryDryRun, "dry-run", false, "Only print the estimate")
+	queryCmd.Flags().BoolVarP(&queryInteractive, "interactive", "i", false, "Show results in an interactive grid")
+	queryCmd.Flags().BoolVarP(&queryQuiet, "quiet", "q", false, "Don't print the estimate")
+}
+
+func runQuery(cmd *cobra.Command, args []string) error {
+	if queryProject == "" {
+		return fmt.Errorf("no project to run the query in: pass --project or set GOOGLE_CLOUD_PROJECT")
+	}
+	if err := validation.ValidateProject(queryProject); err != nil {
+		return fmt.Errorf("invalid project: %w", err)
+	}
+	maxBytesBilled, err := utils.ParseBytes(queryMaxBytesBilled)
+	if err != nil {
+		return fmt.Errorf("invalid --max-bytes-billed: %w", err)
+	}
+	if queryRows <= 0 {
+		return fmt.Errorf("--rows must be positive, got %d", queryRows)
+	}
+	switch queryFormat {
+	case "table", "csv", "ndjson":
+	default:
+		return fmt.Errorf("unsupported format %q: use table, csv or ndjson", queryFormat)
+	}
+
+	sql, err := readQuery(args)
+	if err != nil {
+		return err
+	}
+
+	c, err := utils.NewCache()
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache: %w", err)
+	}
+	defer c.Close()
+	client := bigquery.NewClient(c)
+
+	ctx, cancel := withOperationTimeout(cmd.Context())
+	defer cancel()
+
+	// Always dry-run first: it's free and catches errors and runaway scans
+	estimate, err := client.DryRunQuery(ctx, queryProject, sql)
+	if err != nil {
+		return queryError(err)
+	}
+	if !queryQuiet || queryDryRun {
+		fmt.Fprintf(os.Stderr, "Query will process %s (%s on-demand)\n",
+			utils.FormatBytes(estimate.TotalBytesProcessed), formatCost(estimate.EstimatedCost()))
+	}
+	if maxBytesBilled > 0 && estimate.TotalBytesProcessed > maxBytesBilled {
+		return fmt.Errorf("query would process %s, above --max-bytes-billed %s; raise the limit to run it",
+			utils.FormatBytes(estimate.TotalBytesProcessed), utils.FormatBytes(maxBytesBilled))
+	}
+	if queryDryRun {
+		return nil
+	}
+
+	result, err := client.RunQuery(ctx, queryProject, sql, bigquery.QueryOptions{
+		MaxRows:        queryRows,
+		MaxBytesBilled: maxBytesBilled,
+	})
+	if err != nil {
+		return queryError(err)
+	}
+
+	if queryInteractive {
+		p := tea.NewProgram(newQueryGridModel(sql, result), tea.WithAltScreen())
+		_, err := p.Run()
+		return err
+	}
+
+	switch queryFormat {
+	case "csv":
+		return writeRowsCSV(os.Stdout, &result.TablePreview)
+	case "ndjson":
+		return writeRowsNDJSON(os.Stdout, &result.TablePreview)
+	default:
+		writeRowsTable(os.Stdout, &result.TablePreview)
+		return nil
+	}
+}
+
+// readQuery takes the SQL from the argument, or from stdin when it is - or missing
+func readQuery(args []string) (string, error) {
+	sql := ""
+	if len(args) > 0 {
+		sql = args[0]
+	}
+	if sql == "" || sql == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read query from stdin: %w", err)
+		}
+		sql = string(data)
+	}
+
+	sql = strings.TrimSpace(sql)
+	if sql == "" {
+		return "", fmt.Errorf("empty query")
+	}
+	return sql, nil
+}
+
+// formatCost formats a USD estimate, keeping tiny amounts from reading as free
+func formatCost(usd float64) string {
+	if usd > 0 && usd < 0.01 {
+		return "< $0.01"
+	}
+	return fmt.Sprintf("≈ $%.2f", usd)
+}
+
+// queryError turns classified BigQuery errors into their user-facing message
+func queryError(err error) error {
+	if bqsErr, ok := err.(*errors.BQSError); ok {
+		return fmt.Errorf("%s", bqsErr.UserFriendlyMessage())
+	}
+	return err
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"bqs/internal/bigquery"
+	"bqs/internal/config"
+	"bqs/internal/utils"
+)
+
+// queryGridModel shows a query result in the browser's table component. The
+// component has no horizontal scrolling, so it is handed a window of columns
+// that h/l move across.
+type queryGridModel struct {
+	sql       string
+	result    *bigquery.QueryResult
+	table     table.Model
+	colOffset int
+	expanded  bool // Show the selected row as indented JSON below the grid
+	width     int
+	height    int
+}
+
+func newQueryGridModel(sql string, result *bigquery.QueryResult) *queryGridModel {
+	m := &queryGridModel{
+		sql:    sql,
+		result: result,
+		table:  newStyledTable(nil),
+		width:  80,
+		height: config.DefaultTableHeight + config.HeaderFooterPadding,
+	}
+	m.refreshColumns()
+	return m
+}
+
+// Init implements tea.Model
+func (m *queryGridModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m *queryGridModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.refreshColumns()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "h", "left":
+			if m.colOffset > 0 {
+				m.colOffset--
+				m.refreshColumns()
+			}
+			return m, nil
+		case "l", "right":
+			if m.colOffset+m.visibleColumns() < len(m.result.Columns()) {
+				m.colOffset++
+				m.refreshColumns()
+			}
+			return m, nil
+		case "enter", " ":
+			m.expanded = !m.expanded
+			m.refreshColumns()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+// visibleColumns returns how many result columns fit the terminal width
+func (m *queryGridModel) visibleColumns() int {
+	columns := (m.width - 4) / (config.PreviewColumnWidth + 2)
+	if columns < 1 {
+		columns = 1
+	}
+	return columns
+}
+
+// refreshColumns hands the table the current column window and resizes it
+func (m *queryGridModel) refreshColumns() {
+	names := m.result.Columns()
+	end := m.colOffset + m.visibleColumns()
+	if end > len(names) {
+		end = len(names)
+	}
+
+	columns := make([]table.Column, 0, end-m.colOffset)
+	for _, name := range names[m.colOffset:end] {
+		columns = append(columns, table.Column{Title: name, Width: config.PreviewColumnWidth})
+	}
+
+	rows := make([]table.Row, len(m.result.Rows))
+	for i, row := range m.result.Rows {
+		cells := make(table.Row, len(columns))
+		for j, name := range names[m.colOffset:end] {
+			cells[j] = strings.ReplaceAll(collapsedCell(row[name]), "\n", "⏎")
+		}
+		rows[i] = cells
+	}
+
+	// Clear the rows first: the table renders them against the new columns
+	m.table.SetRows(nil)
+	m.table.SetColumns(columns)
+	m.table.SetRows(rows)
+
+	height := m.height - config.HeaderFooterPadding
+	if m.expanded {
+		height -= maxExpandedLines + 2
+	}
+	if height < config.MinTableHeight {
+		height = config.MinTableHeight
+	}
+	m.table.SetHeight(height)
+}
+
+// View implements tea.Model
+func (m *queryGridModel) View() string {
+	var content strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryBlue).
+		Padding(0, 1)
+	summary := fmt.Sprintf("🔎 %d rows • %s processed", len(m.result.Rows), utils.FormatBytes(m.result.TotalBytesProcessed))
+	if m.result.CacheHit {
+		summary += " • cached"
+	}
+	if columns := len(m.result.Columns()); columns > m.visibleColumns() {
+		summary += fmt.Sprintf(" • columns %d-%d of %d", m.colOffset+1,
+			min(m.colOffset+m.visibleColumns(), columns), columns)
+	}
+	content.WriteString(headerStyle.Render(summary))
+	content.WriteString("\n")
+
+	sqlStyle := lipgloss.NewStyle().Foreground(secondaryGray).Padding(0, 1)
+	content.WriteString(sqlStyle.Render(fitCell(strings.Join(strings.Fields(m.sql), " "), max(m.width-2, 1))))
+	content.WriteString("\n\n")
+
+	if len(m.result.Rows) == 0 {
+		content.WriteString(headerStyle.Render("No rows"))
+		content.WriteString("\n")
+	} else {
+		content.WriteString(m.table.View())
+		content.WriteString("\n")
+		if m.expanded {
+			content.WriteString(m.renderSelectedRow())
+		}
+	}
+
+	footerStyle := lipgloss.NewStyle().
+		Foreground(footerGray).
+		Padding(0, 1).
+		MarginTop(1)
+	shortcuts := []string{
+		navKeyStyle.Render("[hjkl/↑↓←→]") + " Move",
+		actionKeyStyle.Render("[Space]") + " Expand row",
+		quitKeyStyle.Render("[q]") + " Quit",
+	}
+	content.WriteString(renderShortcutFooter(shortcuts, footerStyle))
+
+	return content.String()
+}
+
+// renderSelectedRow renders the selected row as indented JSON
+func (m *queryGridModel) renderSelectedRow() string {
+	cursor := m.table.Cursor()
+	if cursor < 0 || cursor >= len(m.result.Rows) {
+		return ""
+	}
+
+	var content strings.Builder
+	titleStyle := lipgloss.NewStyle().Foreground(accentPurple).Bold(true).Padding(0, 1).MarginTop(1)
+	content.WriteString(titleStyle.Render(fmt.Sprintf("▾ Row %d", cursor+1)))
+	content.WriteString("\n")
+
+	data, err := json.MarshalIndent(m.result.Rows[cursor], "", "  ")
+	if err != nil {
+		return content.String()
+	}
+
+	valueStyle := lipgloss.NewStyle().Foreground(lightGray)
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		if i == maxExpandedLines {
+			content.WriteString(valueStyle.Italic(true).Render(fmt.Sprintf("    … %d more lines", len(lines)-i)))
+			content.WriteString("\n")
+			break
+		}
+		content.WriteString("    " + valueStyle.Render(line) + "\n")
+	}
+
+	return content.String()
+}
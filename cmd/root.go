@@ -1,12 +1,19 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"bqs/internal/bigquery"
+	"bqs/internal/config"
 )
 
+var operationTimeout time.Duration
+
 var rootCmd = &cobra.Command{
 	Use:   "bqs",
 	Short: "BigQuery Schema Tool",
@@ -24,10 +31,33 @@ Common usage:
 
 For more information, visit: https://github.com/debitCredit/bqs`,
 	Version: "1.0.0",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// A misconfigured environment isn't a usage mistake
+		if err := bigquery.CheckEnvironment(); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().DurationVar(&operationTimeout, "timeout", config.DefaultOperationTimeout, "Maximum duration of each BigQuery operation (0 disables the limit)")
+}
+
+// withOperationTimeout bounds ctx by the --timeout flag
+func withOperationTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if operationTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, operationTimeout)
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	// os.Exit skips deferred calls, so the bq worker is stopped here
+	_ = bigquery.Shutdown()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
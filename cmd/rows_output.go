@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	prettytable "github.com/jedib0t/go-pretty/v6/table"
+
+	"bqs/internal/bigquery"
+	"bqs/internal/config"
+)
+
+// Row writers shared by bqs head and bqs query. Rows are in the form
+// described on bigquery.TablePreview.
+
+// writeRowsJSON prints the rows as a JSON array, keeping each row's columns
+// in schema order rather than Go's sorted map order
+func writeRowsJSON(w io.Writer, preview *bigquery.TablePreview) error {
+	columns := preview.Columns()
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	for i, row := range preview.Rows {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n  ")
+		if err := encodeRowJSON(&buf, columns, row); err != nil {
+			return err
+		}
+	}
+	if len(preview.Rows) > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("]\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeRowsNDJSON prints one JSON object per line, columns in schema order
+func writeRowsNDJSON(w io.Writer, preview *bigquery.TablePreview) error {
+	columns := preview.Columns()
+	var buf bytes.Buffer
+	for _, row := range preview.Rows {
+		if err := encodeRowJSON(&buf, columns, row); err != nil {
+			return err
+		}
+		buf.WriteString("\n")
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// encodeRowJSON writes a row as a single-line JSON object with its keys in
+// the given column order
+func encodeRowJSON(buf *bytes.Buffer, columns []string, row map[string]interface{}) error {
+	buf.WriteString("{")
+	for i, column := range columns {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		key, _ := json.Marshal(column)
+		value, err := json.Marshal(row[column])
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", column, err)
+		}
+		buf.Write(key)
+		buf.WriteString(": ")
+		buf.Write(value)
+	}
+	buf.WriteString("}")
+	return nil
+}
+
+// writeRowsCSV prints the rows as CSV with a header line. NULLs become empty
+// fields and nested values compact JSON.
+func writeRowsCSV(w io.Writer, preview *bigquery.TablePreview) error {
+	columns := preview.Columns()
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+	for _, row := range preview.Rows {
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			if value := row[column]; value != nil {
+				record[i] = bigquery.FormatCellValue(value)
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeRowsTable prints the rows as a bordered table with long cells truncated
+func writeRowsTable(w io.Writer, preview *bigquery.TablePreview) {
+	columns := preview.Columns()
+	if len(preview.Rows) == 0 {
+		fmt.Fprintln(w, "No rows")
+		return
+	}
+
+	t := prettytable.NewWriter()
+	t.SetStyle(prettytable.StyleRounded)
+
+	header := prettytable.Row{}
+	for _, column := range columns {
+		header = append(header, column)
+	}
+	t.AppendHeader(header)
+
+	for _, row := range preview.Rows {
+		cells := prettytable.Row{}
+		for _, column := range columns {
+			cell := strings.ReplaceAll(bigquery.FormatCellValue(row[column]), "\n", "⏎")
+			if runes := []rune(cell); len(runes) > config.PreviewColumnWidth {
+				cell = string(runes[:config.PreviewColumnWidth-1]) + "…"
+			}
+			cells = append(cells, cell)
+		}
+		t.AppendRow(cells)
+	}
+
+	fmt.Fprintln(w, t.Render())
+	fmt.Fprintf(w, "\n%d rows\n", len(preview.Rows))
+}
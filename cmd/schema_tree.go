@@ -7,6 +7,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"bqs/internal/bigquery"
+	"bqs/internal/config"
 )
 
 // buildSchemaTree constructs the flattened schema tree for display
@@ -45,6 +46,9 @@ func (m *browserModel) buildSchemaNodesRecursive(fields []bigquery.SchemaField,
 			Level:       level,
 			HasChildren: hasChildren,
 		}
+		if level == 0 {
+			node.Sources = m.columnLineage[field.Name]
+		}
 		m.schemaNodes = append(m.schemaNodes, node)
 
 		// If this node is expanded and has children, add them recursively
@@ -123,9 +127,26 @@ func (m *browserModel) renderSchemaTree() string {
 		typeStyle := lipgloss.NewStyle().Foreground(typeColor).Bold(true).Render(node.Field.Type)
 
 		line := fmt.Sprintf("%s%s%s%s %s%s", indent, connector, expandIcon, node.Field.Name, typeStyle, mode)
+		if len(node.Sources) > 0 {
+			line += lipgloss.NewStyle().Foreground(lightGray).Render(" ← " + m.sourceSummary(node.Sources))
+		}
 		content.WriteString(style.Render(line))
 		content.WriteString("\n")
 	}
 
 	return content.String()
-}
\ No newline at end of file
+}
+
+// sourceSummary lists the first few source columns of a view column, leaving
+// out the project when it is the view's
+func (m *browserModel) sourceSummary(sources []bigquery.ColumnSource) string {
+	names := make([]string, 0, config.SchemaSourcesShown+1)
+	for i, source := range sources {
+		if i == config.SchemaSourcesShown {
+			names = append(names, fmt.Sprintf("+%d more", len(sources)-i))
+			break
+		}
+		names = append(names, source.ShortName(m.project))
+	}
+	return strings.Join(names, ", ")
+}
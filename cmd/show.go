@@ -1,13 +1,21 @@
 package cmd
 
 import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+	"time"
 
+	prettytable "github.com/jedib0t/go-pretty/v6/table"
 	"github.com/spf13/cobra"
 	
+	"bqs/internal/bigquery"
+	"bqs/internal/utils"
 	"bqs/internal/validation"
 )
 
@@ -19,21 +27,24 @@ var (
 	projectOverride  string
 	quietMode        bool
 	noCache          bool
+	showAt           string
 )
 
 var showCmd = &cobra.Command{
-	Use:   "show [flags] <project.dataset.table>",
-	Short: "Show BigQuery table or view metadata",
-	Long: `Display metadata for BigQuery tables, views, and materialized views.
+	Use:   "show [flags] <project.dataset[.table]>",
+	Short: "Show BigQuery dataset, table or view metadata",
+	Long: `Display metadata for BigQuery datasets, tables, views, and materialized views.
 
 Supports all major bq show functionality with enhanced usability.
 
 Common usage:
   bqs show project.dataset.table              # Complete metadata (prettyjson)
+  bqs show project.dataset                    # Dataset metadata and access entries
   bqs show -s project.dataset.table           # Schema only
   bqs show -v project.dataset.view            # View with SQL definition
   bqs show -f json project.dataset.table      # Compact JSON format
   bqs show -s -f pretty project.dataset.table # Schema in table format
+  bqs show -s --at 2026-10-01T00:00:00Z project.dataset.table # Schema as of a point in time
   bqs show -p other-project dataset.table     # Cross-project access`,
 	Args: cobra.ExactArgs(1),
 	RunE: runShow,
@@ -54,6 +65,7 @@ func init() {
 	showCmd.Flags().StringVarP(&projectOverride, "project", "p", "", "Override project ID for cross-project access")
 	showCmd.Flags().BoolVarP(&quietMode, "quiet", "q", false, "Suppress status updates")
 	showCmd.Flags().BoolVar(&noCache, "no-cache", false, "Bypass cache and fetch fresh data")
+	showCmd.Flags().StringVar(&showAt, "at", "", "With --schema, show the schema as of an RFC 3339 time within the time travel window")
 }
 
 func runShow(cmd *cobra.Command, args []string) error {
@@ -65,8 +77,11 @@ func runShow(cmd *cobra.Command, args []string) error {
 	}
 	
 	parts := strings.Split(fullTableID, ".")
-	if len(parts) < 3 {
-		return fmt.Errorf("show command requires project.dataset.table format, got %s", fullTableID)
+	var table string
+	if len(parts) > 2 {
+		table = parts[2]
+	} else if schemaOnly {
+		return fmt.Errorf("--schema requires project.dataset.table format, got %s", fullTableID)
 	}
 	
 	projectID := parts[0]
@@ -76,10 +91,221 @@ func runShow(cmd *cobra.Command, args []string) error {
 	
 	datasetTableID := strings.Join(parts[1:], ".")
 	
-	return showBQTable(projectID, datasetTableID)
+	ctx, cancel := withOperationTimeout(cmd.Context())
+	defer cancel()
+	
+	// bq show has no time travel, so past schemas always go through the client
+	if showAt != "" {
+		if !schemaOnly || table == "" {
+			return fmt.Errorf("--at requires --schema and project.dataset.table format")
+		}
+		at, err := time.Parse(time.RFC3339, showAt)
+		if err != nil {
+			return fmt.Errorf("invalid --at %q: expected an RFC 3339 time such as 2026-10-01T00:00:00Z", showAt)
+		}
+		return showSchemaAt(ctx, projectID, parts[1], table, at)
+	}
+	
+	// Non-CLI backends (e.g. fixtures) and recorded or replayed ones can't pass
+	// through to bq, render their data through the client instead
+	backend := bigquery.NewDefaultBackend()
+	if cli, ok := backend.(*bigquery.CLIBackend); !ok || !cli.Passthrough() {
+		return showFromClient(ctx, backend, projectID, parts[1], table)
+	}
+	
+	return showBQTable(ctx, projectID, datasetTableID)
+}
+
+// showFromClient prints dataset metadata, table metadata or schema fetched
+// through a client on the backend, so they are cached, retried and
+// classified like everything else. An empty table selects the dataset.
+func showFromClient(ctx context.Context, backend bigquery.Backend, projectID, dataset, table string) error {
+	switch formatFlag {
+	case "json", "prettyjson", "pretty", "sparse", "csv":
+	default:
+		return fmt.Errorf("unsupported format %q: use json, prettyjson, pretty, sparse or csv", formatFlag)
+	}
+	
+	c, err := utils.NewCache()
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache: %w", err)
+	}
+	defer c.Close()
+	
+	client := bigquery.NewClientWithBackend(c, backend)
+	if noCache {
+		if err := client.InvalidateCache(projectID, dataset, table); err != nil {
+			return err
+		}
+	}
+	
+	var resource interface{}
+	var header []string
+	var rows [][]string
+	if table == "" {
+		metadata, err := client.GetDatasetMetadata(ctx, projectID, dataset)
+		if err != nil {
+			return queryError(err)
+		}
+		resource = metadata
+		header, rows = datasetShowRows(metadata)
+	} else if schemaOnly {
+		schema, err := client.GetSchema(ctx, projectID, dataset, table)
+		if err != nil {
+			return queryError(err)
+		}
+		// bq show --schema prints the bare field list
+		resource = schema.Fields
+		header, rows = schemaShowRows(schema.Fields)
+	} else {
+		metadata, err := client.GetFullTableMetadata(ctx, projectID, dataset, table)
+		if err != nil {
+			return queryError(err)
+		}
+		resource = metadata
+		header, rows = tableShowRows(metadata)
+	}
+	
+	switch formatFlag {
+	case "json", "prettyjson":
+		var output []byte
+		if formatFlag == "json" {
+			output, err = json.Marshal(resource)
+		} else {
+			output, err = json.MarshalIndent(resource, "", "  ")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to format output: %w", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	case "csv":
+		writer := csv.NewWriter(os.Stdout)
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+		if err := writer.WriteAll(rows); err != nil {
+			return err
+		}
+		return writer.Error()
+	default:
+		t := prettytable.NewWriter()
+		if formatFlag == "sparse" {
+			t.SetStyle(prettytable.StyleLight)
+			t.Style().Options = prettytable.Options{SeparateHeader: true}
+		}
+		headerRow := prettytable.Row{}
+		for _, column := range header {
+			headerRow = append(headerRow, column)
+		}
+		t.AppendHeader(headerRow)
+		for _, row := range rows {
+			cells := prettytable.Row{}
+			for _, cell := range row {
+				cells = append(cells, cell)
+			}
+			t.AppendRow(cells)
+		}
+		fmt.Println(t.Render())
+		return nil
+	}
+}
+
+// datasetShowRows summarizes a dataset the way bq show's table formats do
+func datasetShowRows(metadata *bigquery.DatasetMetadata) ([]string, [][]string) {
+	header := []string{"Last modified", "Location", "Default Table Expiration", "Labels"}
+	row := []string{
+		bigquery.FormatTime(metadata.LastModifiedTime),
+		metadata.Location,
+		bigquery.FormatExpiration(metadata.DefaultTableExpirationMs),
+		bigquery.FormatLabels(metadata.Labels),
+	}
+	return header, [][]string{row}
+}
+
+// tableShowRows summarizes a table the way bq show's table formats do
+func tableShowRows(metadata *bigquery.TableMetadata) ([]string, [][]string) {
+	partitioning := ""
+	if tp := metadata.TimePartitioning; tp != nil {
+		partitioning = tp.Type
+		if tp.Field != "" {
+			partitioning += " (field: " + tp.Field + ")"
+		}
+	} else if metadata.RangePartitioning != nil {
+		partitioning = "RANGE (field: " + metadata.RangePartitioning.Field + ")"
+	}
+	clustering := ""
+	if metadata.Clustering != nil {
+		clustering = strings.Join(metadata.Clustering.Fields, ", ")
+	}
+	expiration := ""
+	if metadata.ExpirationTime > 0 {
+		expiration = bigquery.FormatTime(metadata.ExpirationTime)
+	}
+	header := []string{"Last modified", "Type", "Total Rows", "Total Bytes", "Expiration", "Time Partitioning", "Clustered Fields", "Labels"}
+	row := []string{
+		bigquery.FormatTime(metadata.LastModifiedTime),
+		metadata.Type,
+		strconv.FormatInt(metadata.NumRows, 10),
+		strconv.FormatInt(metadata.NumBytes, 10),
+		expiration,
+		partitioning,
+		clustering,
+		bigquery.FormatLabels(metadata.Labels),
+	}
+	return header, [][]string{row}
+}
+
+// schemaShowRows lists the fields, nested ones by their dotted path
+func schemaShowRows(fields []bigquery.SchemaField) ([]string, [][]string) {
+	var rows [][]string
+	var walk func(prefix string, fields []bigquery.SchemaField)
+	walk = func(prefix string, fields []bigquery.SchemaField) {
+		for _, field := range fields {
+			mode := field.Mode
+			if mode == "" {
+				mode = "NULLABLE"
+			}
+			rows = append(rows, []string{prefix + field.Name, field.Type, mode, field.Description})
+			walk(prefix+field.Name+".", field.Fields)
+		}
+	}
+	walk("", fields)
+	return []string{"Name", "Type", "Mode", "Description"}, rows
+}
+
+// showSchemaAt prints a table's schema as of a point in time, in the bare
+// field list form of bq show --schema
+func showSchemaAt(ctx context.Context, projectID, dataset, table string, at time.Time) error {
+	c, err := utils.NewCache()
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache: %w", err)
+	}
+	defer c.Close()
+	
+	schema, err := bigquery.NewClient(c).GetSchemaAt(ctx, projectID, dataset, table, at)
+	if err != nil {
+		return queryError(err)
+	}
+	
+	var output []byte
+	switch formatFlag {
+	case "json":
+		output, err = json.Marshal(schema.Fields)
+	case "prettyjson":
+		output, err = json.MarshalIndent(schema.Fields, "", "  ")
+	default:
+		return fmt.Errorf("format %s is not supported with --at (use json or prettyjson)", formatFlag)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+	
+	fmt.Println(string(output))
+	return nil
 }
 
-func showBQTable(projectID, datasetTableID string) error {
+func showBQTable(ctx context.Context, projectID, datasetTableID string) error {
 	args := []string{"show"}
 	
 	// Add project ID
@@ -107,7 +333,7 @@ func showBQTable(projectID, datasetTableID string) error {
 	// Add the table identifier
 	args = append(args, datasetTableID)
 	
-	cmd := exec.Command("bq", args...)
+	cmd := exec.CommandContext(ctx, "bq", args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
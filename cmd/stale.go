@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	prettytable "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"bqs/internal/bigquery"
+	"bqs/internal/utils"
+	"bqs/internal/validation"
+)
+
+var (
+	staleUnmodified string
+	staleUnqueried  string
+	staleMinSize    string
+	staleFormat     string
+)
+
+var staleCmd = &cobra.Command{
+	Use:   "stale [flags] <project.dataset>",
+	Short: "Find tables that look unused",
+	Long: `List the tables of a dataset that look unused and are candidates for
+cleanup, largest first, with their size and estimated monthly storage cost.
+
+A table is stale when it hasn't been modified for --unmodified, nobody has read
+it for --unqueried, and it holds at least --min-size. Reads come from
+INFORMATION_SCHEMA.JOBS_BY_PROJECT, which only covers jobs run in the dataset's
+project and reaches back 180 days; a longer --unqueried is judged by those
+180 days, with a warning. Without the bigquery.jobs.listAll
+permission the read criterion is skipped with a warning. Pass 0 to skip a
+criterion. Views and external tables have no storage and are never listed.
+
+Costs are estimated at US multi-region logical storage list prices; tables
+unmodified for 90 days are billed at the long-term rate.
+
+Common usage:
+  bqs stale project.dataset                                 # 180d unmodified, 90d unread
+  bqs stale --unmodified 90d --min-size 1GB project.dataset
+  bqs stale --unqueried 0 project.dataset                   # Modification time only
+  bqs stale -f csv project.dataset > cleanup.csv`,
+	Args: cobra.ExactArgs(1),
+	RunE: runStale,
+}
+
+func init() {
+	rootCmd.AddCommand(staleCmd)
+
+	defaults := bigquery.DefaultStaleCriteria()
+	staleCmd.Flags().StringVar(&staleUnmodified, "unmodified", formatAge(defaults.Unmodified), "Minimum time since the last modification, e.g. 180d, 26w (0 to skip)")
+	staleCmd.Flags().StringVar(&staleUnqueried, "unqueried", formatAge(defaults.Unqueried), "Minimum time since the last read, e.g. 90d (0 to skip)")
+	staleCmd.Flags().StringVar(&staleMinSize, "min-size", "0", "Minimum logical size, e.g. 1GB")
+	staleCmd.Flags().StringVarP(&staleFormat, "format", "f", "table", "Output format: table, csv, json")
+}
+
+func runStale(cmd *cobra.Command, args []string) error {
+	if err := validation.ValidateResourcePath(args[0]); err != nil {
+		return fmt.Errorf("invalid input: %w", err)
+	}
+	parts := strings.Split(args[0], ".")
+	if len(parts) != 2 {
+		return fmt.Errorf("stale requires project.dataset format, got %s", args[0])
+	}
+	switch staleFormat {
+	case "table", "csv", "json":
+	default:
+		return fmt.Errorf("unsupported format %q: use table, csv or json", staleFormat)
+	}
+
+	var criteria bigquery.StaleCriteria
+	var err error
+	if criteria.Unmodified, err = utils.ParseAge(staleUnmodified); err != nil {
+		return fmt.Errorf("invalid --unmodified: %w", err)
+	}
+	if criteria.Unqueried, err = utils.ParseAge(staleUnqueried); err != nil {
+		return fmt.Errorf("invalid --unqueried: %w", err)
+	}
+	if criteria.MinSize, err = utils.ParseBytes(staleMinSize); err != nil {
+		return fmt.Errorf("invalid --min-size: %w", err)
+	}
+
+	c, err := utils.NewCache()
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache: %w", err)
+	}
+	defer c.Close()
+
+	ctx, cancel := withOperationTimeout(cmd.Context())
+	defer cancel()
+
+	now := time.Now()
+	report, err := bigquery.NewClient(c).FindStaleTables(ctx, parts[0], parts[1], criteria, now)
+	if err != nil {
+		return queryError(err)
+	}
+	if report.HistoryErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %s; judging by modification time and size only\n", queryError(report.HistoryErr))
+	} else if report.HistoryCap > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: job history only reaches back %s, so tables not read in that time count as unqueried\n",
+			formatAge(report.HistoryCap))
+	}
+
+	switch staleFormat {
+	case "json":
+		return writeStaleJSON(os.Stdout, report.Tables, now)
+	case "csv":
+		return writeStaleCSV(os.Stdout, report.Tables, now)
+	}
+	if len(report.Tables) == 0 {
+		fmt.Printf("None of the %d tables in %s look stale\n", report.Checked, args[0])
+		return nil
+	}
+	writeStaleTable(os.Stdout, report, now)
+	return nil
+}
+
+// formatAge prints a whole number of days as a flag value, e.g. 180d
+func formatAge(age time.Duration) string {
+	return fmt.Sprintf("%dd", int(age.Hours()/24))
+}
+
+// formatLastRead describes when a stale table was last read
+func formatLastRead(t bigquery.StaleTable) string {
+	switch {
+	case !t.ReadsKnown:
+		return "unknown"
+	case t.LastRead == 0:
+		return "not in job history"
+	default:
+		return bigquery.FormatTime(t.LastRead)
+	}
+}
+
+// writeStaleTable prints one row per stale table and a total line
+func writeStaleTable(w io.Writer, report *bigquery.StaleReport, now time.Time) {
+	t := prettytable.NewWriter()
+	t.SetStyle(prettytable.StyleRounded)
+	t.AppendHeader(prettytable.Row{"Table", "Type", "Size", "Modified", "Last read", "$/mo"})
+
+	var bytes int64
+	var cost float64
+	for _, table := range report.Tables {
+		t.AppendRow(prettytable.Row{table.TableID, table.Type, bigquery.FormatSize(table.NumBytes),
+			bigquery.FormatTime(table.LastModifiedTime), formatLastRead(table), fmt.Sprintf("$%.2f", table.StorageCost(now))})
+		bytes += table.NumBytes
+		cost += table.StorageCost(now)
+	}
+
+	fmt.Fprintln(w, t.Render())
+	fmt.Fprintf(w, "%d of %d tables look stale, %s, about $%.2f a month\n",
+		len(report.Tables), report.Checked, bigquery.FormatSize(bytes), cost)
+}
+
+// writeStaleCSV prints the stale tables as CSV with a header line and RFC
+// 3339 times; last_read is empty when unknown or not in the job history
+func writeStaleCSV(w io.Writer, tables []bigquery.StaleTable, now time.Time) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"table", "type", "size_bytes", "last_modified", "last_read", "monthly_cost_usd"}); err != nil {
+		return err
+	}
+	for _, t := range tables {
+		record := []string{
+			t.TableReference.String(),
+			t.Type,
+			strconv.FormatInt(t.NumBytes, 10),
+			formatRFC3339(t.LastModifiedTime),
+			formatRFC3339(t.LastRead),
+			strconv.FormatFloat(t.StorageCost(now), 'f', 2, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeStaleJSON prints the stale tables as a JSON array
+func writeStaleJSON(w io.Writer, tables []bigquery.StaleTable, now time.Time) error {
+	type staleJSON struct {
+		Table          string  `json:"table"`
+		Type           string  `json:"type"`
+		SizeBytes      int64   `json:"size_bytes"`
+		LastModified   string  `json:"last_modified"`
+		LastRead       string  `json:"last_read,omitempty"`
+		ReadsKnown     bool    `json:"reads_known"`
+		MonthlyCostUSD float64 `json:"monthly_cost_usd"`
+	}
+	out := make([]staleJSON, 0, len(tables))
+	for _, t := range tables {
+		out = append(out, staleJSON{
+			Table:          t.TableReference.String(),
+			Type:           t.Type,
+			SizeBytes:      t.NumBytes,
+			LastModified:   formatRFC3339(t.LastModifiedTime),
+			LastRead:       formatRFC3339(t.LastRead),
+			ReadsKnown:     t.ReadsKnown,
+			MonthlyCostUSD: math.Round(t.StorageCost(now)*100) / 100,
+		})
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode stale tables: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// formatRFC3339 formats Unix milliseconds in UTC, or nothing when unset
+func formatRFC3339(unixMillis int64) string {
+	if unixMillis == 0 {
+		return ""
+	}
+	return time.UnixMilli(unixMillis).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	prettytable "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"bqs/internal/bigquery"
+	"bqs/internal/config"
+	"bqs/internal/utils"
+	"bqs/internal/validation"
+)
+
+var (
+	usageDays   int
+	usageFormat string
+)
+
+var usageCmd = &cobra.Command{
+	Use:   "usage [flags] <project.dataset.table>",
+	Short: "Summarize who reads and writes a table",
+	Long: `Summarize the jobs of the last days that read or wrote a table, from
+INFORMATION_SCHEMA.JOBS_BY_PROJECT: read and write counts, bytes processed,
+the last read and write, and the same counts per user.
+
+A job whose destination is the table counts as a write, whether a query, load
+or copy; every other job referencing it counts as a read. Only jobs run in the
+table's own project are included. Listing other users' jobs needs the
+bigquery.jobs.listAll permission on the project.
+
+Job history is cached for a couple of minutes. The jobs view bills the bytes
+it scans, which grows with --days.
+
+Common usage:
+  bqs usage project.dataset.table           # Last 30 days
+  bqs usage --days 7 project.dataset.table
+  bqs usage -f json project.dataset.table`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUsage,
+}
+
+func init() {
+	rootCmd.AddCommand(usageCmd)
+
+	usageCmd.Flags().IntVar(&usageDays, "days", config.UsageDefaultDays, "Number of days of job history to summarize")
+	usageCmd.Flags().StringVarP(&usageFormat, "format", "f", "table", "Output format: table, json")
+}
+
+func runUsage(cmd *cobra.Command, args []string) error {
+	if err := validation.ValidateProjectDatasetTable(args[0]); err != nil {
+		return fmt.Errorf("invalid input: %w", err)
+	}
+	parts := strings.Split(args[0], ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("usage requires project.dataset.table format, got %s", args[0])
+	}
+	if usageDays <= 0 {
+		return fmt.Errorf("invalid --days %d: must be positive", usageDays)
+	}
+	switch usageFormat {
+	case "table", "json":
+	default:
+		return fmt.Errorf("unsupported format %q: use table or json", usageFormat)
+	}
+
+	c, err := utils.NewCache()
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache: %w", err)
+	}
+	defer c.Close()
+
+	ctx, cancel := withOperationTimeout(cmd.Context())
+	defer cancel()
+
+	jobs, err := bigquery.NewClient(c).TableJobs(ctx, parts[0], parts[1], parts[2], usageDays)
+	if err != nil {
+		return queryError(err)
+	}
+	usage := bigquery.SummarizeTableJobs(jobs)
+
+	if usageFormat == "json" {
+		return writeUsageJSON(os.Stdout, usage, usageDays)
+	}
+	if len(jobs) == 0 {
+		fmt.Printf("No jobs read or wrote %s in the last %d days\n", args[0], usageDays)
+		return nil
+	}
+	writeUsageTable(os.Stdout, usage, usageDays)
+	return nil
+}
+
+// writeUsageTable prints the overall counts followed by one row per user
+func writeUsageTable(w io.Writer, usage bigquery.TableUsage, days int) {
+	fmt.Fprintf(w, "Last %d days: %d reads, %d writes, %d failed, %s processed\n",
+		days, usage.Reads, usage.Writes, usage.Failed, bigquery.FormatSize(usage.BytesProcessed))
+	fmt.Fprintf(w, "Last read: %s, last write: %s\n", bigquery.FormatTime(usage.LastRead), bigquery.FormatTime(usage.LastWrite))
+
+	t := prettytable.NewWriter()
+	t.SetStyle(prettytable.StyleRounded)
+	t.AppendHeader(prettytable.Row{"User", "Reads", "Writes", "Last job"})
+	for _, u := range usage.Users {
+		t.AppendRow(prettytable.Row{u.User, u.Reads, u.Writes, bigquery.FormatTime(u.LastJob)})
+	}
+	fmt.Fprintln(w, t.Render())
+
+	if usage.Truncated {
+		fmt.Fprintf(w, "Only the most recent %d jobs were counted.\n", config.JobHistoryLimit)
+	}
+	fmt.Fprintln(w, "Only jobs run in the table's own project are included.")
+}
+
+// writeUsageJSON prints the summary as a JSON object with RFC 3339 times,
+// empty when the table wasn't read or written
+func writeUsageJSON(w io.Writer, usage bigquery.TableUsage, days int) error {
+	type userJSON struct {
+		User    string `json:"user"`
+		Reads   int    `json:"reads"`
+		Writes  int    `json:"writes"`
+		LastJob string `json:"last_job"`
+	}
+	type usageJSON struct {
+		Days           int        `json:"days"`
+		Reads          int        `json:"reads"`
+		Writes         int        `json:"writes"`
+		Failed         int        `json:"failed"`
+		BytesProcessed int64      `json:"bytes_processed"`
+		LastRead       string     `json:"last_read"`
+		LastWrite      string     `json:"last_write"`
+		Truncated      bool       `json:"truncated"`
+		Users          []userJSON `json:"users"`
+	}
+	out := usageJSON{
+		Days:           days,
+		Reads:          usage.Reads,
+		Writes:         usage.Writes,
+		Failed:         usage.Failed,
+		BytesProcessed: usage.BytesProcessed,
+		LastRead:       formatRFC3339(usage.LastRead),
+		LastWrite:      formatRFC3339(usage.LastWrite),
+		Truncated:      usage.Truncated,
+		Users:          []userJSON{},
+	}
+	for _, u := range usage.Users {
+		out.Users = append(out.Users, userJSON{User: u.User, Reads: u.Reads, Writes: u.Writes, LastJob: formatRFC3339(u.LastJob)})
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode usage: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
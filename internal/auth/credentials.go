@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+
+	"bqs/internal/config"
+)
+
+// BigQueryScope is the OAuth scope requested for BigQuery API access
+const BigQueryScope = "https://www.googleapis.com/auth/bigquery"
+
+// TokenSource supplies OAuth2 access tokens for API requests. Token gives
+// up waiting when ctx is done.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticTokenSource always returns the same access token
+type StaticTokenSource string
+
+// Token implements TokenSource
+func (s StaticTokenSource) Token(ctx context.Context) (string, error) {
+	return string(s), nil
+}
+
+// NewDefaultTokenSource returns a TokenSource that discovers Application Default
+// Credentials on first use, so a missing setup surfaces as an auth error on the
+// first API call rather than at startup.
+//
+// BQS_ACCESS_TOKEN takes precedence; otherwise credentials are found as
+// google.FindDefaultCredentials does: GOOGLE_APPLICATION_CREDENTIALS, the
+// gcloud ADC file, then the GCE metadata server. Every ADC credential type
+// is supported, including impersonated service accounts and workload
+// identity federation.
+func NewDefaultTokenSource() TokenSource {
+	return &lazyTokenSource{done: make(chan struct{})}
+}
+
+// lazyTokenSource resolves the real token source on first call
+type lazyTokenSource struct {
+	once   sync.Once
+	done   chan struct{} // Closed once source or err is set
+	source TokenSource
+	err    error
+}
+
+// Token implements TokenSource
+func (l *lazyTokenSource) Token(ctx context.Context) (string, error) {
+	// Discovery may probe the metadata server; callers needn't wait it out
+	l.once.Do(func() {
+		go func() {
+			defer close(l.done)
+			l.source, l.err = findDefaultTokenSource()
+		}()
+	})
+	select {
+	case <-l.done:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	if l.err != nil {
+		return "", l.err
+	}
+	return l.source.Token(ctx)
+}
+
+// findDefaultTokenSource walks the ADC lookup chain
+func findDefaultTokenSource() (TokenSource, error) {
+	if token := os.Getenv("BQS_ACCESS_TOKEN"); token != "" {
+		return StaticTokenSource(token), nil
+	}
+
+	creds, err := google.FindDefaultCredentials(tokenContext(), BigQueryScope)
+	if err != nil {
+		return nil, fmt.Errorf("no application default credentials found - run 'gcloud auth application-default login' or set GOOGLE_APPLICATION_CREDENTIALS: %w", err)
+	}
+	return &oauthTokenSource{source: creds.TokenSource}, nil
+}
+
+// NewTokenSourceFromJSON builds a TokenSource from any ADC credentials file:
+// service_account, authorized_user, impersonated_service_account or
+// external_account
+func NewTokenSourceFromJSON(data []byte) (TokenSource, error) {
+	creds, err := google.CredentialsFromJSON(tokenContext(), data, BigQueryScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file: %w", err)
+	}
+	return &oauthTokenSource{source: creds.TokenSource}, nil
+}
+
+// tokenContext is the context token sources make their requests in. It
+// outlives any one API call, since the source refreshes tokens for as long
+// as bqs runs; callers bound their wait with the ctx passed to Token.
+func tokenContext() context.Context {
+	return context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Timeout: config.TokenRequestTimeout})
+}
+
+// oauthTokenSource adapts an oauth2.TokenSource, which caches tokens until
+// shortly before they expire but can't be cancelled, to TokenSource
+type oauthTokenSource struct {
+	source oauth2.TokenSource
+}
+
+// Token implements TokenSource. A refresh the caller stops waiting for
+// still completes, and its token is reused by the next call.
+func (s *oauthTokenSource) Token(ctx context.Context) (string, error) {
+	type result struct {
+		token *oauth2.Token
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		token, err := s.source.Token()
+		done <- result{token, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return "", fmt.Errorf("failed to obtain credentials: %w", r.err)
+		}
+		if r.token.AccessToken == "" {
+			return "", fmt.Errorf("failed to obtain credentials: token endpoint returned no access token")
+		}
+		return r.token.AccessToken, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
@@ -0,0 +1,252 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServiceAccountTokenSource(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+			t.Errorf("Unexpected grant_type %q", r.Form.Get("grant_type"))
+		}
+
+		// Verify the assertion is signed by the service account key
+		parts := strings.Split(r.Form.Get("assertion"), ".")
+		if len(parts) != 3 {
+			t.Fatalf("Expected 3 JWT parts, got %d", len(parts))
+		}
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+			t.Errorf("Invalid JWT signature: %v", err)
+		}
+
+		claimsJSON, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		var claims map[string]interface{}
+		json.Unmarshal(claimsJSON, &claims)
+		if claims["iss"] != "bqs@demo-project.iam.gserviceaccount.com" || claims["scope"] != BigQueryScope {
+			t.Errorf("Unexpected claims: %v", claims)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token": "sa-token", "expires_in": 3600, "token_type": "Bearer"}`))
+	}))
+	defer server.Close()
+
+	creds, _ := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "bqs@demo-project.iam.gserviceaccount.com",
+		"private_key":  string(pemKey),
+		"token_uri":    server.URL,
+	})
+
+	source, err := NewTokenSourceFromJSON(creds)
+	if err != nil {
+		t.Fatalf("NewTokenSourceFromJSON returned error: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		token, err := source.Token(context.Background())
+		if err != nil {
+			t.Fatalf("Token returned error: %v", err)
+		}
+		if token != "sa-token" {
+			t.Errorf("Expected sa-token, got %s", token)
+		}
+	}
+
+	// The second call must reuse the cached token
+	if requests != 1 {
+		t.Errorf("Expected 1 token request, got %d", requests)
+	}
+}
+
+func TestAuthorizedUserTokenSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "refresh-me" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token": "user-token", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+
+	creds, _ := json.Marshal(map[string]string{
+		"type":          "authorized_user",
+		"client_id":     "client",
+		"client_secret": "secret",
+		"refresh_token": "refresh-me",
+		"token_uri":     server.URL,
+	})
+
+	source, err := NewTokenSourceFromJSON(creds)
+	if err != nil {
+		t.Fatalf("NewTokenSourceFromJSON returned error: %v", err)
+	}
+
+	token, err := source.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token returned error: %v", err)
+	}
+	if token != "user-token" {
+		t.Errorf("Expected user-token, got %s", token)
+	}
+}
+
+func TestDefaultTokenSourceAccessTokenOverride(t *testing.T) {
+	t.Setenv("BQS_ACCESS_TOKEN", "override")
+
+	token, err := NewDefaultTokenSource().Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token returned error: %v", err)
+	}
+	if token != "override" {
+		t.Errorf("Expected override token, got %s", token)
+	}
+}
+
+func TestImpersonatedServiceAccountTokenSource(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token": "user-token", "expires_in": 3600}`))
+		case "/v1/projects/-/serviceAccounts/bqs@demo-project.iam.gserviceaccount.com:generateAccessToken":
+			if r.Header.Get("Authorization") != "Bearer user-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			expiry := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+			w.Write([]byte(`{"accessToken": "impersonated-token", "expireTime": "` + expiry + `"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	creds, _ := json.Marshal(map[string]interface{}{
+		"type":                              "impersonated_service_account",
+		"service_account_impersonation_url": server.URL + "/v1/projects/-/serviceAccounts/bqs@demo-project.iam.gserviceaccount.com:generateAccessToken",
+		"source_credentials": map[string]string{
+			"type":          "authorized_user",
+			"client_id":     "client",
+			"client_secret": "secret",
+			"refresh_token": "refresh-me",
+			"token_uri":     server.URL + "/token",
+		},
+	})
+
+	source, err := NewTokenSourceFromJSON(creds)
+	if err != nil {
+		t.Fatalf("NewTokenSourceFromJSON returned error: %v", err)
+	}
+	token, err := source.Token(context.Background())
+	if err != nil || token != "impersonated-token" {
+		t.Errorf("Token returned %q, %v; want impersonated-token", token, err)
+	}
+}
+
+func TestExternalAccountTokenSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("subject_token") != "workload-identity" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token": "federated-token", "issued_token_type": "urn:ietf:params:oauth:token-type:access_token", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+
+	subjectToken := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(subjectToken, []byte("workload-identity"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	creds, _ := json.Marshal(map[string]interface{}{
+		"type":               "external_account",
+		"audience":           "//iam.googleapis.com/projects/123/locations/global/workloadIdentityPools/pool/providers/provider",
+		"subject_token_type": "urn:ietf:params:oauth:token-type:jwt",
+		"token_url":          server.URL,
+		"credential_source":  map[string]string{"file": subjectToken},
+	})
+
+	source, err := NewTokenSourceFromJSON(creds)
+	if err != nil {
+		t.Fatalf("NewTokenSourceFromJSON returned error: %v", err)
+	}
+	token, err := source.Token(context.Background())
+	if err != nil || token != "federated-token" {
+		t.Errorf("Token returned %q, %v; want federated-token", token, err)
+	}
+}
+
+func TestTokenCancelled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token": "late-token", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	creds, _ := json.Marshal(map[string]string{
+		"type":          "authorized_user",
+		"client_id":     "client",
+		"client_secret": "secret",
+		"refresh_token": "refresh-me",
+		"token_uri":     server.URL,
+	})
+	source, err := NewTokenSourceFromJSON(creds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := source.Token(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Token returned %v, want the caller's deadline", err)
+	}
+}
+
+func TestUnsupportedCredentialsType(t *testing.T) {
+	if _, err := NewTokenSourceFromJSON([]byte(`{"type": "carrier_pigeon"}`)); err == nil {
+		t.Error("Expected error for unsupported credentials type")
+	}
+}
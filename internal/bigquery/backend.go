@@ -0,0 +1,140 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"bqs/internal/auth"
+)
+
+// Backend fetches raw metadata from BigQuery or a stand-in for it.
+// Client layers caching, retry and error classification on top, so
+// implementations only need to return plain errors. Implementations must
+// abandon work when ctx is cancelled.
+type Backend interface {
+	ListProjects(ctx context.Context) ([]ProjectInfo, error)
+	ListDatasets(ctx context.Context, project string) ([]DatasetInfo, error)
+	GetDatasetMetadata(ctx context.Context, project, dataset string) (*DatasetMetadata, error)
+	ListTablesPage(ctx context.Context, project, dataset, pageToken string) (*TablePage, error)
+	GetSchema(ctx context.Context, project, dataset, table string) (*Schema, error)
+	GetTableMetadata(ctx context.Context, project, dataset, table string) (*TableMetadata, error)
+	// TableChanged reports whether a table's metadata changed since it had
+	// the given etag, with a cheaper call than fetching it. Backends without
+	// one, the bq CLI among them, report a change, so the metadata is
+	// fetched again.
+	TableChanged(ctx context.Context, project, dataset, table, etag string) (bool, error)
+	// ListTableMetadata returns metadata, including schemas, for every table
+	// in the dataset in a single round trip
+	ListTableMetadata(ctx context.Context, project, dataset string) ([]TableMetadata, error)
+	// ListRows reads up to maxResults stored rows of a table without running
+	// a query, in the row form described on TablePreview
+	ListRows(ctx context.Context, project, dataset, table string, maxResults int) ([]map[string]interface{}, error)
+	// ListPartitions lists a table's partitions from INFORMATION_SCHEMA.PARTITIONS
+	ListPartitions(ctx context.Context, project, dataset, table string) ([]PartitionInfo, error)
+	// ListTableStorage lists the storage of a project's tables in one region
+	// from INFORMATION_SCHEMA.TABLE_STORAGE
+	ListTableStorage(ctx context.Context, project, location string) ([]TableStorage, error)
+	// ListTableJobs lists the jobs of the last days that read or wrote a table
+	// from the regional INFORMATION_SCHEMA.JOBS_BY_PROJECT
+	ListTableJobs(ctx context.Context, project, location, dataset, table string, days int) ([]TableJob, error)
+	// ListTableReads counts the jobs of the last days that read each table of
+	// a dataset, from the regional INFORMATION_SCHEMA.JOBS_BY_PROJECT
+	ListTableReads(ctx context.Context, project, location, dataset string, days int) ([]TableReads, error)
+	// DryRunQuery validates a GoogleSQL query and reports what it would
+	// process without running it
+	DryRunQuery(ctx context.Context, project, sql string) (*QueryEstimate, error)
+	// RunQuery runs a GoogleSQL query and returns its first rows. Backends
+	// that can't report the result schema leave it nil.
+	RunQuery(ctx context.Context, project, sql string, opts QueryOptions) (*QueryResult, error)
+}
+
+// IdempotentQuerier is implemented by backends whose RunQuery honors
+// opts.RequestID, so a retried request reuses the first attempt's job instead
+// of starting another. Client only retries queries on backends reporting true.
+type IdempotentQuerier interface {
+	IdempotentQueries() bool
+}
+
+// TablePage is one page of a table listing. An empty NextPageToken marks the
+// last page; TotalItems is the dataset's table count when the backend knows it.
+type TablePage struct {
+	Tables        []TableInfo
+	NextPageToken string
+	TotalItems    int
+}
+
+// NewDefaultBackend selects a backend from the environment.
+// BQS_FIXTURE_DIR switches to the fixture backend, BQS_REPLAY to replaying a
+// cassette, BQS_BACKEND=rest to the REST API (with BQS_BIGQUERY_ENDPOINT
+// overriding the API root), otherwise the bq CLI is used, through the
+// persistent worker BQS_BQ_WORKER names if set and recording its commands
+// into the cassette BQS_RECORD names if set. CheckEnvironment reports
+// settings this would ignore.
+func NewDefaultBackend() Backend {
+	if dir := os.Getenv("BQS_FIXTURE_DIR"); dir != "" {
+		return NewFixtureBackend(dir)
+	}
+	if path := os.Getenv("BQS_REPLAY"); path != "" {
+		return NewReplayBackend(path)
+	}
+	if os.Getenv("BQS_BACKEND") == "rest" {
+		return NewRESTBackend(os.Getenv("BQS_BIGQUERY_ENDPOINT"), auth.NewDefaultTokenSource())
+	}
+	backend := NewCLIBackend()
+	if worker := strings.Fields(os.Getenv("BQS_BQ_WORKER")); len(worker) > 0 {
+		backend = NewCLIBackendWithSession(defaultBQSession(worker))
+	}
+	if path := os.Getenv("BQS_RECORD"); path != "" {
+		backend.cassette = RecordCassette(path)
+	}
+	return backend
+}
+
+var (
+	defaultSessionMu sync.Mutex
+	defaultSession   *BQSession
+)
+
+// defaultBQSession returns the session every backend NewDefaultBackend
+// creates shares, so a process runs one worker however many clients it makes
+func defaultBQSession(command []string) *BQSession {
+	defaultSessionMu.Lock()
+	defer defaultSessionMu.Unlock()
+	if defaultSession == nil {
+		defaultSession = NewBQSession(command)
+	}
+	return defaultSession
+}
+
+// Shutdown stops the persistent bq worker NewDefaultBackend started, if any.
+// Call it once the program is done with BigQuery.
+func Shutdown() error {
+	defaultSessionMu.Lock()
+	session := defaultSession
+	defaultSessionMu.Unlock()
+	if session == nil {
+		return nil
+	}
+	return session.Close()
+}
+
+// CheckEnvironment reports backend settings NewDefaultBackend would ignore.
+// Only the bq backend records, so BQS_RECORD can't be combined with a
+// fixture, replay or REST backend.
+func CheckEnvironment() error {
+	if os.Getenv("BQS_RECORD") == "" {
+		return nil
+	}
+	for _, name := range []string{"BQS_FIXTURE_DIR", "BQS_REPLAY"} {
+		if os.Getenv(name) != "" {
+			return fmt.Errorf("BQS_RECORD only records the bq backend and can't be combined with %s", name)
+		}
+	}
+	if os.Getenv("BQS_BACKEND") == "rest" {
+		return fmt.Errorf("BQS_RECORD only records the bq backend and can't be combined with BQS_BACKEND=rest")
+	}
+	return nil
+}
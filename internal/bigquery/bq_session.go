@@ -0,0 +1,298 @@
+package bigquery
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+
+	"bqs/internal/config"
+	"bqs/internal/errors"
+)
+
+// BQSession runs bq commands through a long-lived worker process instead of
+// starting bq, and with it a Python interpreter, for every call. Requests are
+// multiplexed over the worker's stdin and stdout with a framing of one JSON
+// line per request:
+//
+//	{"id":7,"args":["show","--format=json","my-project:analytics"]}
+//
+// answered, in any order, by a JSON header line followed by the command's
+// stdout and stderr, exactly as many bytes as the header announces:
+//
+//	{"id":7,"exit":0,"stdout":1234,"stderr":0}
+//
+// scripts/bq_worker.py is such a worker, running bq's Python code in forks
+// of one process that loaded it.
+//
+// A worker that crashes is restarted on the next request. A request the
+// worker doesn't answer within config.BQWorkerRequestTimeout runs one-shot
+// instead, leaving the worker be: a slow command isn't a dead worker.
+// Requests run one-shot with bq while no worker can be started, and for good
+// after config.BQWorkerMaxRestarts failures in a row.
+type BQSession struct {
+	command []string
+	timeout time.Duration
+
+	// fallback runs a command when the worker can't; one-shot bq by default
+	fallback func(ctx context.Context, args ...string) ([]byte, error)
+
+	mu       sync.Mutex
+	worker   *bqWorker // nil until started and after it dies
+	nextID   int
+	failures int  // Worker failures since the last answered request
+	disabled bool // Too many failures; everything runs one-shot
+}
+
+// bqWorker is one worker process and the requests waiting for its answers
+type bqWorker struct {
+	cmd     *exec.Cmd
+	pending map[int]chan bqResponse // Guarded by BQSession.mu
+	done    chan struct{}           // Closed when the worker has exited
+
+	// Writes to stdin take their own lock, so a worker slow to read them
+	// doesn't hold up the delivery of its answers
+	writeMu sync.Mutex
+	stdin   io.WriteCloser
+}
+
+type bqRequest struct {
+	ID   int      `json:"id"`
+	Args []string `json:"args"`
+}
+
+type bqResponseHeader struct {
+	ID     int `json:"id"`
+	Exit   int `json:"exit"`
+	Stdout int `json:"stdout"`
+	Stderr int `json:"stderr"`
+}
+
+type bqResponse struct {
+	exit   int
+	stdout []byte
+	stderr []byte
+	err    error // The worker died before answering
+}
+
+// errWorkerExited is the answer to requests in flight when the worker died
+var errWorkerExited = stderrors.New("bq worker exited")
+
+// NewBQSession creates a session running the given worker command. The
+// worker is started with the first request.
+func NewBQSession(command []string) *BQSession {
+	return &BQSession{
+		command:  command,
+		timeout:  config.BQWorkerRequestTimeout,
+		fallback: runBQ,
+	}
+}
+
+// runBQ runs bq once and returns its standard output. A non-zero exit
+// returns an exec.ExitError carrying stderr.
+func runBQ(ctx context.Context, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, "bq", args...).Output()
+}
+
+// Run runs a bq command, given without the leading bq, and returns its
+// standard output. A non-zero exit returns an errors.CommandError carrying
+// stderr. Requests in flight when the worker dies, and those it takes too
+// long to answer, are run again one-shot, so only commands that are safe to
+// repeat should be run through a session.
+func (s *BQSession) Run(ctx context.Context, args ...string) ([]byte, error) {
+	worker, id, answer, err := s.send(args)
+	if err != nil {
+		return s.fallback(ctx, args...)
+	}
+
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
+	select {
+	case response := <-answer:
+		if response.err != nil {
+			return s.fallback(ctx, args...)
+		}
+		if response.exit != 0 {
+			return response.stdout, &errors.CommandError{ExitCode: response.exit, Stderr: response.stderr}
+		}
+		return response.stdout, nil
+	case <-ctx.Done():
+		// The worker still answers eventually; the answer is dropped
+		s.forget(worker, id)
+		return nil, ctx.Err()
+	case <-timer.C:
+		// Only a worker that exits counts as failing; its reader sees to that
+		s.forget(worker, id)
+		return s.fallback(ctx, args...)
+	}
+}
+
+// Close stops the worker, if running, and waits for it to exit. Later
+// requests start a new one.
+func (s *BQSession) Close() error {
+	s.mu.Lock()
+	worker := s.worker
+	s.worker = nil // Not a failure
+	s.mu.Unlock()
+	if worker == nil {
+		return nil
+	}
+	worker.kill()
+	<-worker.done
+	return nil
+}
+
+// send writes a request to the worker, starting it if needed, and returns
+// the channel its answer arrives on. It fails when requests have to run
+// one-shot instead.
+func (s *BQSession) send(args []string) (*bqWorker, int, chan bqResponse, error) {
+	s.mu.Lock()
+	if s.disabled {
+		s.mu.Unlock()
+		return nil, 0, nil, stderrors.New("bq worker disabled")
+	}
+	if s.worker == nil {
+		worker, err := s.start()
+		if err != nil {
+			s.failed()
+			s.mu.Unlock()
+			return nil, 0, nil, err
+		}
+		s.worker = worker
+	}
+	worker := s.worker
+	s.nextID++
+	id := s.nextID
+	answer := make(chan bqResponse, 1)
+	worker.pending[id] = answer
+	s.mu.Unlock()
+
+	line, err := json.Marshal(bqRequest{ID: id, Args: args})
+	if err == nil {
+		worker.writeMu.Lock()
+		_, err = worker.stdin.Write(append(line, '\n'))
+		worker.writeMu.Unlock()
+	}
+	if err != nil {
+		// A worker that can't be written to has exited; its reader cleans up
+		s.forget(worker, id)
+		return nil, 0, nil, err
+	}
+	return worker, id, answer, nil
+}
+
+// start launches a worker process and its reader. Callers hold s.mu.
+func (s *BQSession) start() (*bqWorker, error) {
+	if len(s.command) == 0 {
+		return nil, stderrors.New("no bq worker command")
+	}
+	cmd := exec.Command(s.command[0], s.command[1:]...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start bq worker: %w", err)
+	}
+
+	worker := &bqWorker{
+		cmd:     cmd,
+		stdin:   stdin,
+		pending: make(map[int]chan bqResponse),
+		done:    make(chan struct{}),
+	}
+	go s.read(worker, bufio.NewReader(stdout))
+	return worker, nil
+}
+
+// read delivers the worker's answers until it exits, then fails the
+// requests still waiting
+func (s *BQSession) read(worker *bqWorker, stdout *bufio.Reader) {
+	for {
+		response, id, err := readBQResponse(stdout)
+		if err != nil {
+			break
+		}
+		s.mu.Lock()
+		answer, ok := worker.pending[id]
+		delete(worker.pending, id)
+		s.failures = 0
+		s.mu.Unlock()
+		if ok {
+			answer <- response
+		}
+	}
+
+	// Broken framing or a crash; either way this worker is done
+	worker.kill()
+	_ = worker.cmd.Wait()
+
+	s.mu.Lock()
+	if s.worker == worker {
+		s.worker = nil
+		s.failed()
+	}
+	for id, answer := range worker.pending {
+		answer <- bqResponse{err: errWorkerExited}
+		delete(worker.pending, id)
+	}
+	s.mu.Unlock()
+	close(worker.done)
+}
+
+// readBQResponse reads one framed answer
+func readBQResponse(r *bufio.Reader) (bqResponse, int, error) {
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		return bqResponse{}, 0, err
+	}
+	var header bqResponseHeader
+	if err := json.Unmarshal(line, &header); err != nil {
+		return bqResponse{}, 0, fmt.Errorf("malformed bq worker header %q: %w", line, err)
+	}
+	if header.Stdout < 0 || header.Stderr < 0 {
+		return bqResponse{}, 0, fmt.Errorf("malformed bq worker header %q", line)
+	}
+	response := bqResponse{
+		exit:   header.Exit,
+		stdout: make([]byte, header.Stdout),
+		stderr: make([]byte, header.Stderr),
+	}
+	if _, err := io.ReadFull(r, response.stdout); err != nil {
+		return bqResponse{}, 0, err
+	}
+	if _, err := io.ReadFull(r, response.stderr); err != nil {
+		return bqResponse{}, 0, err
+	}
+	return response, header.ID, nil
+}
+
+// failed counts a worker failure, giving up on the worker after too many in
+// a row. Callers hold s.mu.
+func (s *BQSession) failed() {
+	s.failures++
+	if s.failures >= config.BQWorkerMaxRestarts {
+		s.disabled = true
+	}
+}
+
+// forget drops a request whose caller stopped waiting
+func (s *BQSession) forget(worker *bqWorker, id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(worker.pending, id)
+}
+
+// kill stops the worker; its reader fails the requests still waiting
+func (w *bqWorker) kill() {
+	_ = w.cmd.Process.Kill()
+}
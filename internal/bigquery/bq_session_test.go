@@ -0,0 +1,318 @@
+package bigquery
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"bqs/internal/config"
+	"bqs/internal/errors"
+)
+
+// fakeBQWorker is a stand-in for a persistent bq worker speaking the
+// session's framing. It logs every start to the file given as $1, answers
+// show with table metadata and anything else with the request itself, fails
+// requests for "missing", crashes on "crash" and never answers "slow", like a
+// command that is still running.
+const fakeBQWorker = `#!/bin/sh
+echo started >> "$1"
+while IFS= read -r line; do
+  id=$(printf '%s\n' "$line" | sed 's/^{"id":\([0-9]*\),.*/\1/')
+  case "$line" in
+    *'"crash"'*) exit 1 ;;
+    *'"slow"'*) ;;
+    *missing*)
+      err='BigQuery error in show operation: Not found: Table p:d.missing'
+      printf '{"id":%s,"exit":2,"stdout":0,"stderr":%d}\n%s' "$id" ${#err} "$err" ;;
+    *'"show"'*)
+      out='{"type":"TABLE","tableReference":{"projectId":"p","datasetId":"d","tableId":"t"}}'
+      printf '{"id":%s,"exit":0,"stdout":%d,"stderr":0}\n%s' "$id" ${#out} "$out" ;;
+    *)
+      printf '{"id":%s,"exit":0,"stdout":%d,"stderr":0}\n%s' "$id" ${#line} "$line" ;;
+  esac
+done
+`
+
+// newFakeSession starts a session on the fake worker, with one-shot
+// fallbacks answered by "fallback" and counted. starts reports how often
+// the worker was started.
+func newFakeSession(t *testing.T) (session *BQSession, starts func() int, fallbacks func() int) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake bq worker is a shell script")
+	}
+	dir := t.TempDir()
+	script := filepath.Join(dir, "bq-worker")
+	if err := os.WriteFile(script, []byte(fakeBQWorker), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	log := filepath.Join(dir, "starts.log")
+
+	session = NewBQSession([]string{script, log})
+	var mu sync.Mutex
+	count := 0
+	session.fallback = func(ctx context.Context, args ...string) ([]byte, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		count++
+		return []byte("fallback"), nil
+	}
+	t.Cleanup(func() { session.Close() })
+
+	starts = func() int {
+		data, _ := os.ReadFile(log)
+		return strings.Count(string(data), "started")
+	}
+	fallbacks = func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return count
+	}
+	return session, starts, fallbacks
+}
+
+func TestBQSessionRunsCommands(t *testing.T) {
+	session, starts, fallbacks := newFakeSession(t)
+	ctx := context.Background()
+
+	// Arguments survive the framing, newlines and quotes included
+	sql := "SELECT \"a\"\nFROM t"
+	output, err := session.Run(ctx, "query", "--format=json", sql)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if want := `"args":["query","--format=json","SELECT \"a\"\nFROM t"]`; !strings.Contains(string(output), want) {
+		t.Errorf("Output %s doesn't echo %s", output, want)
+	}
+
+	// Concurrent requests share the worker and each get their own answer
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			arg := fmt.Sprintf("table_%d", i)
+			output, err := session.Run(ctx, "ls", arg)
+			if err != nil {
+				t.Errorf("Run %d failed: %v", i, err)
+			} else if !strings.Contains(string(output), `"`+arg+`"`) {
+				t.Errorf("Run %d got %s", i, output)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if starts() != 1 || fallbacks() != 0 {
+		t.Errorf("Worker started %d times with %d fallbacks, want one start and none", starts(), fallbacks())
+	}
+}
+
+func TestBQSessionCommandError(t *testing.T) {
+	session, _, _ := newFakeSession(t)
+
+	_, err := session.Run(context.Background(), "show", "d.missing")
+	var commandErr *errors.CommandError
+	if !stderrors.As(err, &commandErr) || commandErr.ExitCode != 2 {
+		t.Fatalf("Expected a CommandError with exit 2, got %v", err)
+	}
+	if bqsErr := errors.WrapBigQueryError(err, "get_metadata", "p", "d", "missing"); bqsErr.Type != errors.ErrorTypeNotFound {
+		t.Errorf("Expected a not found error from stderr, got %v: %s", bqsErr.Type, bqsErr.Message)
+	}
+}
+
+func TestBQSessionRestartsAfterCrash(t *testing.T) {
+	session, starts, fallbacks := newFakeSession(t)
+	ctx := context.Background()
+
+	// The request in flight runs one-shot instead
+	output, err := session.Run(ctx, "crash")
+	if err != nil || string(output) != "fallback" {
+		t.Fatalf("Crashed request returned %q, %v; want the one-shot fallback", output, err)
+	}
+
+	if _, err := session.Run(ctx, "ls"); err != nil {
+		t.Fatalf("Run after crash failed: %v", err)
+	}
+	if starts() != 2 || fallbacks() != 1 {
+		t.Errorf("Worker started %d times with %d fallbacks, want a restart and one fallback", starts(), fallbacks())
+	}
+}
+
+func TestBQSessionTimeout(t *testing.T) {
+	session, starts, fallbacks := newFakeSession(t)
+	session.timeout = 100 * time.Millisecond
+	ctx := context.Background()
+
+	// A request the worker is slow to answer runs one-shot instead
+	output, err := session.Run(ctx, "slow")
+	if err != nil || string(output) != "fallback" {
+		t.Fatalf("Slow request returned %q, %v; want the one-shot fallback", output, err)
+	}
+
+	// The worker is still alive, so it keeps serving and isn't counted as failing
+	for i := 0; i < config.BQWorkerMaxRestarts; i++ {
+		if _, err := session.Run(ctx, "slow"); err != nil {
+			t.Fatalf("Slow request %d failed: %v", i, err)
+		}
+	}
+	if output, err := session.Run(ctx, "ls"); err != nil || string(output) == "fallback" {
+		t.Fatalf("Run after timeouts returned %q, %v; want the worker's answer", output, err)
+	}
+	if starts() != 1 || fallbacks() != config.BQWorkerMaxRestarts+1 || session.disabled {
+		t.Errorf("Worker started %d times with %d fallbacks (disabled=%v), want one start and only the slow requests one-shot",
+			starts(), fallbacks(), session.disabled)
+	}
+}
+
+func TestBQSessionFallsBackWithoutWorker(t *testing.T) {
+	session, _, fallbacks := newFakeSession(t)
+	session.command = []string{filepath.Join(t.TempDir(), "no-such-worker")}
+
+	for i := 0; i < config.BQWorkerMaxRestarts+2; i++ {
+		output, err := session.Run(context.Background(), "ls")
+		if err != nil || string(output) != "fallback" {
+			t.Fatalf("Run %d returned %q, %v; want the one-shot fallback", i, output, err)
+		}
+	}
+	if !session.disabled || fallbacks() != config.BQWorkerMaxRestarts+2 {
+		t.Errorf("disabled = %v after %d fallbacks, want the session given up", session.disabled, fallbacks())
+	}
+}
+
+func TestCLIBackendWithSession(t *testing.T) {
+	session, _, _ := newFakeSession(t)
+	backend := NewCLIBackendWithSession(session)
+
+	metadata, err := backend.GetTableMetadata(context.Background(), "p", "d", "t")
+	if err != nil {
+		t.Fatalf("GetTableMetadata failed: %v", err)
+	}
+	if metadata.Type != "TABLE" || metadata.TableReference.TableID != "t" {
+		t.Errorf("Unexpected metadata: %+v", metadata)
+	}
+}
+
+// fakeBQScript stands in for bq's Python entry point. It logs each time it
+// is loaded next to itself, echoes its arguments as JSON and fails like bq
+// for "missing".
+const fakeBQScript = `import json, sys
+
+with open(__file__ + ".log", "a") as log:
+    log.write("loaded\n")
+
+def main():
+    args = sys.argv[1:]
+    if any("missing" in arg for arg in args):
+        sys.stderr.write("BigQuery error in show operation: Not found: Table p:d.missing")
+        sys.exit(2)
+    print(json.dumps(args))
+
+if __name__ == "__main__":
+    main()
+`
+
+func TestBQWorkerScript(t *testing.T) {
+	python, err := exec.LookPath("python3")
+	if err != nil || runtime.GOOS == "windows" {
+		t.Skip("the bq worker needs python3 and fork")
+	}
+	bq := filepath.Join(t.TempDir(), "bq.py")
+	if err := os.WriteFile(bq, []byte(fakeBQScript), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	worker, err := filepath.Abs(filepath.Join("..", "..", "scripts", "bq_worker.py"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session := NewBQSession([]string{python, worker, bq})
+	session.fallback = func(ctx context.Context, args ...string) ([]byte, error) {
+		t.Errorf("bq %v ran one-shot instead of through the worker", args)
+		return nil, nil
+	}
+	defer session.Close()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			arg := fmt.Sprintf("d.table_%d", i)
+			output, err := session.Run(ctx, "show", "--format=json", arg)
+			if err != nil {
+				t.Errorf("Run %d failed: %v", i, err)
+			} else if want := fmt.Sprintf("[\"show\", \"--format=json\", %q]\n", arg); string(output) != want {
+				t.Errorf("Run %d got %q, want %q", i, output, want)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	_, err = session.Run(ctx, "show", "d.missing")
+	var commandErr *errors.CommandError
+	if !stderrors.As(err, &commandErr) || commandErr.ExitCode != 2 {
+		t.Fatalf("Expected a CommandError with exit 2, got %v", err)
+	}
+	if bqsErr := errors.WrapBigQueryError(err, "get_metadata", "p", "d", "missing"); bqsErr.Type != errors.ErrorTypeNotFound {
+		t.Errorf("Expected a not found error from stderr, got %v: %s", bqsErr.Type, bqsErr.Message)
+	}
+
+	// bq was loaded once by the worker, not once per command
+	data, err := os.ReadFile(bq + ".log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loads := strings.Count(string(data), "loaded"); loads != 1 {
+		t.Errorf("bq was loaded %d times, want once", loads)
+	}
+}
+
+func TestBQWorkerScriptRefusesCloudSDK(t *testing.T) {
+	python, err := exec.LookPath("python3")
+	if err != nil || runtime.GOOS == "windows" {
+		t.Skip("the bq worker needs python3 and fork")
+	}
+	// A Cloud SDK layout, whose bq.py would run without gcloud's credentials
+	sdk := t.TempDir()
+	for _, dir := range []string{filepath.Join(sdk, "platform", "bq"), filepath.Join(sdk, "bin", "bootstrapping")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "bq.py"), []byte(fakeBQScript), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	worker, err := filepath.Abs(filepath.Join("..", "..", "scripts", "bq_worker.py"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session := NewBQSession([]string{python, worker, filepath.Join(sdk, "platform", "bq", "bq.py")})
+	fallbacks := 0
+	session.fallback = func(ctx context.Context, args ...string) ([]byte, error) {
+		fallbacks++
+		return []byte("fallback"), nil
+	}
+	defer session.Close()
+
+	for i := 0; i < config.BQWorkerMaxRestarts; i++ {
+		if output, err := session.Run(context.Background(), "ls"); err != nil || string(output) != "fallback" {
+			t.Fatalf("Run %d returned %q, %v; want the one-shot fallback", i, output, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(sdk, "platform", "bq", "bq.py.log")); err == nil {
+		t.Error("The worker loaded the SDK's bq.py")
+	}
+	if !session.disabled {
+		t.Error("Expected the session to give up on a worker that refuses to start")
+	}
+}
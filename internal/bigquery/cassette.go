@@ -0,0 +1,197 @@
+package bigquery
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"bqs/internal/errors"
+)
+
+// Cassette records the bq commands a CLIBackend runs, with their raw
+// responses, or replays such a recording without running bq. A cassette file
+// holds one JSON line per command, in the order they ran:
+//
+//	{"args":["show","--format=json","p:d"],"exit":0,"stdout":"{...}","stderr":""}
+//
+// Replaying answers each command with the recorded responses to the same
+// arguments in turn, repeating the last once they run out, so a command that
+// failed and then succeeded on retry fails and succeeds again. Commands that
+// never got a response, because bq couldn't be started or the caller gave
+// up, aren't recorded. Replaying a command that wasn't recorded fails with
+// errors.ErrNotRecorded.
+//
+// A nil Cassette is valid and runs commands as they are.
+type Cassette struct {
+	path   string
+	replay bool
+
+	mu        sync.Mutex
+	loaded    bool
+	err       error                         // Loading or creating the file failed
+	file      *os.File                      // Recording
+	responses map[string][]cassetteResponse // Replaying, by cassetteKey
+}
+
+// cassetteEntry is one line of a cassette file
+type cassetteEntry struct {
+	Args []string `json:"args"`
+	cassetteResponse
+}
+
+type cassetteResponse struct {
+	Exit   int    `json:"exit"`
+	Stdout string `json:"stdout"`
+	Stderr string `json:"stderr"`
+}
+
+// recorders shares one recording cassette per file between the backends of
+// a process, so they don't truncate each other's recordings
+var (
+	recordersMu sync.Mutex
+	recorders   = map[string]*Cassette{}
+)
+
+// RecordCassette returns a cassette recording into the file at path, which
+// is truncated with the first command recorded
+func RecordCassette(path string) *Cassette {
+	recordersMu.Lock()
+	defer recordersMu.Unlock()
+	if c, ok := recorders[path]; ok {
+		return c
+	}
+	c := &Cassette{path: path}
+	recorders[path] = c
+	return c
+}
+
+// ReplayCassette returns a cassette replaying the file at path, which is
+// read with the first command replayed
+func ReplayCassette(path string) *Cassette {
+	return &Cassette{path: path, replay: true}
+}
+
+// Run runs a bq command with run, recording its response, or answers it
+// from the recording
+func (c *Cassette) Run(ctx context.Context, run func(ctx context.Context, args ...string) ([]byte, error), args ...string) ([]byte, error) {
+	if c == nil {
+		return run(ctx, args...)
+	}
+	if c.replay {
+		return c.play(ctx, args)
+	}
+
+	output, err := run(ctx, args...)
+	if err != nil && ctx.Err() != nil {
+		return output, err
+	}
+	response := cassetteResponse{Stdout: string(output)}
+	if err != nil {
+		var commandErr *errors.CommandError
+		var exitErr *exec.ExitError
+		switch {
+		case stderrors.As(err, &commandErr):
+			response.Exit, response.Stderr = commandErr.ExitCode, string(commandErr.Stderr)
+		case stderrors.As(err, &exitErr):
+			response.Exit, response.Stderr = exitErr.ExitCode(), string(exitErr.Stderr)
+		default:
+			return output, err
+		}
+	}
+	if recordErr := c.record(cassetteEntry{Args: args, cassetteResponse: response}); recordErr != nil {
+		return nil, recordErr
+	}
+	return output, err
+}
+
+// record appends an entry to the cassette file
+func (c *Cassette) record(entry cassetteEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.loaded {
+		c.loaded = true
+		c.file, c.err = os.Create(c.path)
+	}
+	if c.err != nil {
+		return fmt.Errorf("failed to record to cassette %s: %w", c.path, c.err)
+	}
+	if _, err := c.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to record to cassette %s: %w", c.path, err)
+	}
+	return nil
+}
+
+// play answers a command with its next recorded response
+func (c *Cassette) play(ctx context.Context, args []string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.loaded {
+		c.loaded = true
+		c.responses, c.err = loadCassette(c.path)
+	}
+	if c.err != nil {
+		return nil, fmt.Errorf("%w from cassette %s: %v", errors.ErrNotRecorded, c.path, c.err)
+	}
+
+	key := cassetteKey(args)
+	responses := c.responses[key]
+	if len(responses) == 0 {
+		return nil, fmt.Errorf("%w in cassette %s to bq %s", errors.ErrNotRecorded, c.path, strings.Join(args, " "))
+	}
+	response := responses[0]
+	if len(responses) > 1 {
+		c.responses[key] = responses[1:]
+	}
+
+	if response.Exit != 0 {
+		return []byte(response.Stdout), &errors.CommandError{ExitCode: response.Exit, Stderr: []byte(response.Stderr)}
+	}
+	return []byte(response.Stdout), nil
+}
+
+// loadCassette reads a cassette file's responses by command
+func loadCassette(path string) (map[string][]cassetteResponse, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make(map[string][]cassetteResponse)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for n := 1; scanner.Scan(); n++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var entry cassetteEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		key := cassetteKey(entry.Args)
+		responses[key] = append(responses[key], entry.cassetteResponse)
+	}
+	return responses, scanner.Err()
+}
+
+// cassetteKey identifies a command by its arguments
+func cassetteKey(args []string) string {
+	key, _ := json.Marshal(args)
+	return string(key)
+}
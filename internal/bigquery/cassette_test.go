@@ -0,0 +1,127 @@
+package bigquery
+
+import (
+	"context"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"bqs/internal/errors"
+	"bqs/internal/retry"
+)
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	ctx := context.Background()
+
+	// A stand-in for bq: one success, one failure and one bq that can't start
+	run := func(ctx context.Context, args ...string) ([]byte, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		switch args[0] {
+		case "ls":
+			return []byte("[{\"id\":\"p:d\"}]\n"), nil
+		case "show":
+			return nil, &errors.CommandError{ExitCode: 2, Stderr: []byte("Not found: Table p:d.t")}
+		}
+		return nil, stderrors.New("executable file not found")
+	}
+
+	recorder := RecordCassette(path)
+	recorder.Run(ctx, run, "ls", "--format=json")
+	recorder.Run(ctx, run, "show", "d.t")
+	recorder.Run(ctx, run, "version")
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	recorder.Run(cancelled, run, "ls", "--format=json")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Fatalf("Recorded %d commands, want the two that got a response:\n%s", lines, data)
+	}
+
+	player := ReplayCassette(path)
+	unused := func(ctx context.Context, args ...string) ([]byte, error) {
+		t.Fatalf("Replay ran bq %v", args)
+		return nil, nil
+	}
+	output, err := player.Run(ctx, unused, "ls", "--format=json")
+	if err != nil || string(output) != "[{\"id\":\"p:d\"}]\n" {
+		t.Errorf("Replayed ls returned %q, %v", output, err)
+	}
+	_, err = player.Run(ctx, unused, "show", "d.t")
+	var commandErr *errors.CommandError
+	if !stderrors.As(err, &commandErr) || commandErr.ExitCode != 2 || string(commandErr.Stderr) != "Not found: Table p:d.t" {
+		t.Errorf("Replayed show returned %v, want the recorded failure", err)
+	}
+	_, err = player.Run(ctx, unused, "version")
+	if !stderrors.Is(err, errors.ErrNotRecorded) {
+		t.Errorf("Replaying an unrecorded command returned %v, want ErrNotRecorded", err)
+	}
+	if bqsErr := errors.WrapBigQueryError(err, "version", "", "", ""); bqsErr.Retryable {
+		t.Error("A command missing from the cassette shouldn't be retried")
+	}
+}
+
+func TestReplayBackendErrorHandling(t *testing.T) {
+	backend := NewReplayBackend(filepath.Join("testdata", "cassettes", "backend_error.jsonl"))
+	ctx := context.Background()
+
+	// The recorded failure classifies as it did against BigQuery
+	_, err := backend.GetTableMetadata(ctx, "demo-project", "analytics", "missing")
+	if bqsErr := errors.WrapBigQueryError(err, "get_metadata", "demo-project", "analytics", "missing"); bqsErr.Type != errors.ErrorTypeNotFound || bqsErr.Retryable {
+		t.Errorf("Expected a final not found error, got %v: %s", bqsErr.Type, bqsErr.Message)
+	}
+
+	// The first attempt fails as recorded and the retry succeeds
+	attempts := 0
+	config := &retry.Config{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1}
+	var metadata *TableMetadata
+	err = retry.WithRetry(ctx, config, "get_metadata", func() error {
+		attempts++
+		var fetchErr error
+		metadata, fetchErr = backend.GetTableMetadata(ctx, "demo-project", "analytics", "events")
+		return fetchErr
+	})
+	if err != nil || attempts != 2 {
+		t.Fatalf("Retry returned %v after %d attempts, want success on the second", err, attempts)
+	}
+	if metadata.NumRows != 42 {
+		t.Errorf("Expected the recorded 42 rows, got %d", metadata.NumRows)
+	}
+
+	// Once the recording runs out, the last response repeats
+	if _, err := backend.GetTableMetadata(ctx, "demo-project", "analytics", "events"); err != nil {
+		t.Errorf("Replaying past the recording failed: %v", err)
+	}
+}
+
+func TestCheckEnvironment(t *testing.T) {
+	for _, name := range []string{"BQS_RECORD", "BQS_FIXTURE_DIR", "BQS_REPLAY", "BQS_BACKEND", "BQS_BQ_WORKER"} {
+		t.Setenv(name, "")
+	}
+	if err := CheckEnvironment(); err != nil {
+		t.Fatalf("CheckEnvironment() = %v for an empty environment", err)
+	}
+
+	t.Setenv("BQS_RECORD", filepath.Join(t.TempDir(), "session.jsonl"))
+	t.Setenv("BQS_BQ_WORKER", "bq-worker")
+	if err := CheckEnvironment(); err != nil {
+		t.Errorf("CheckEnvironment() = %v when recording the bq backend", err)
+	}
+	for name, value := range map[string]string{"BQS_BACKEND": "rest", "BQS_FIXTURE_DIR": "testdata/fixtures", "BQS_REPLAY": "session.jsonl"} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+			if err := CheckEnvironment(); err == nil || !strings.Contains(err.Error(), name) {
+				t.Errorf("CheckEnvironment() = %v, want BQS_RECORD rejected with %s", err, name)
+			}
+		})
+	}
+}
@@ -0,0 +1,346 @@
+package bigquery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"bqs/internal/config"
+)
+
+// CLIBackend fetches metadata by running the bq command-line tool
+type CLIBackend struct {
+	session  *BQSession // Persistent bq worker; nil runs bq once per call
+	cassette *Cassette  // Records or replays the commands; nil runs them as they are
+}
+
+// NewCLIBackend creates a backend that shells out to bq
+func NewCLIBackend() *CLIBackend {
+	return &CLIBackend{}
+}
+
+// NewCLIBackendWithSession creates a backend that runs bq commands through
+// a persistent worker, falling back to running bq once per call
+func NewCLIBackendWithSession(session *BQSession) *CLIBackend {
+	return &CLIBackend{session: session}
+}
+
+// NewReplayBackend creates a backend that answers bq commands from a
+// cassette recorded with BQS_RECORD, without running bq
+func NewReplayBackend(path string) *CLIBackend {
+	return &CLIBackend{cassette: ReplayCassette(path)}
+}
+
+// Passthrough reports whether bq can be run directly in place of the
+// backend, which it can't while its commands are recorded or replayed
+func (b *CLIBackend) Passthrough() bool {
+	return b.cassette == nil
+}
+
+// bq runs a bq command, through the session if there is one, and returns
+// its standard output
+func (b *CLIBackend) bq(ctx context.Context, args ...string) ([]byte, error) {
+	run := runBQ
+	if b.session != nil {
+		run = b.session.Run
+	}
+	return b.cassette.Run(ctx, run, args...)
+}
+
+// ListProjects calls bq ls --projects
+func (b *CLIBackend) ListProjects(ctx context.Context) ([]ProjectInfo, error) {
+	maxResults := fmt.Sprintf("--max_results=%d", config.CLIMaxTableListResults)
+	output, err := b.bq(ctx, "ls", "--projects", "--format=json", maxResults)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	if len(bytes.TrimSpace(output)) == 0 {
+		return []ProjectInfo{}, nil
+	}
+
+	var projects []ProjectInfo
+	if err := json.Unmarshal(output, &projects); err != nil {
+		return nil, fmt.Errorf("failed to parse project list: %w", err)
+	}
+
+	return projects, nil
+}
+
+// ListDatasets calls bq ls --datasets. bq only returns list-level fields,
+// so creation time, expiration and table counts are left for Client to fill
+// in from its cache.
+func (b *CLIBackend) ListDatasets(ctx context.Context, project string) ([]DatasetInfo, error) {
+	maxResults := fmt.Sprintf("--max_results=%d", config.CLIMaxTableListResults)
+	output, err := b.bq(ctx, "ls", "--project_id="+project, "--datasets", "--format=json", maxResults)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list datasets: %w", err)
+	}
+
+	// bq prints nothing at all for a project without datasets
+	if len(bytes.TrimSpace(output)) == 0 {
+		return []DatasetInfo{}, nil
+	}
+
+	var datasets []DatasetInfo
+	if err := json.Unmarshal(output, &datasets); err != nil {
+		return nil, fmt.Errorf("failed to parse dataset list: %w", err)
+	}
+
+	return datasets, nil
+}
+
+// GetDatasetMetadata calls bq show for the dataset
+func (b *CLIBackend) GetDatasetMetadata(ctx context.Context, project, dataset string) (*DatasetMetadata, error) {
+	output, err := b.bq(ctx, "show", "--format=json", fmt.Sprintf("%s:%s", project, dataset))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dataset metadata: %w", err)
+	}
+
+	var metadata DatasetMetadata
+	if err := json.Unmarshal(output, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse dataset metadata: %w", err)
+	}
+
+	return &metadata, nil
+}
+
+// ListTablesPage calls bq ls to get the table list. bq pages through
+// tables.list itself without exposing page tokens, so the whole dataset comes
+// back as a single page and pageToken is ignored.
+func (b *CLIBackend) ListTablesPage(ctx context.Context, project, dataset, pageToken string) (*TablePage, error) {
+	maxResults := fmt.Sprintf("--max_results=%d", config.CLIMaxTableListResults)
+	output, err := b.bq(ctx, "ls", "--project_id="+project, "--format=json", maxResults, dataset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	var tables []TableInfo
+	if err := json.Unmarshal(output, &tables); err != nil {
+		return nil, fmt.Errorf("failed to parse table list: %w", err)
+	}
+
+	return &TablePage{Tables: tables, TotalItems: len(tables)}, nil
+}
+
+// GetSchema calls bq show --schema to get table schema
+func (b *CLIBackend) GetSchema(ctx context.Context, project, dataset, table string) (*Schema, error) {
+	tableID := dataset + "." + table
+	output, err := b.bq(ctx, "show", "--project_id="+project, "--schema", "--format=json", tableID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema: %w", err)
+	}
+
+	var fields []SchemaField
+	if err := json.Unmarshal(output, &fields); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+
+	return &Schema{Fields: fields}, nil
+}
+
+// GetTableMetadata calls bq show to get complete table metadata
+func (b *CLIBackend) GetTableMetadata(ctx context.Context, project, dataset, table string) (*TableMetadata, error) {
+	tableID := dataset + "." + table
+	output, err := b.bq(ctx, "show", "--project_id="+project, "--format=json", tableID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table metadata: %w", err)
+	}
+
+	var metadata TableMetadata
+	if err := json.Unmarshal(output, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse table metadata: %w", err)
+	}
+
+	return &metadata, nil
+}
+
+// TableChanged always reports a change, so expired entries are fetched
+// again: bq has no call cheaper than the bq show that fetches the metadata,
+// and revalidation only saves work on the REST backend
+func (b *CLIBackend) TableChanged(ctx context.Context, project, dataset, table, etag string) (bool, error) {
+	return true, nil
+}
+
+// ListTableMetadata runs the bulk INFORMATION_SCHEMA query through bq query
+func (b *CLIBackend) ListTableMetadata(ctx context.Context, project, dataset string) ([]TableMetadata, error) {
+	maxRows := fmt.Sprintf("--max_rows=%d", config.CLIMaxTableListResults)
+	output, err := b.bq(ctx, "query", "--project_id="+project, "--nouse_legacy_sql", "--format=json", maxRows,
+		datasetSchemaQuery(project, dataset))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table metadata: %w", err)
+	}
+
+	// bq prints nothing at all for an empty result
+	if len(bytes.TrimSpace(output)) == 0 {
+		return []TableMetadata{}, nil
+	}
+
+	var rows []informationSchemaRow
+	if err := json.Unmarshal(output, &rows); err != nil {
+		return nil, fmt.Errorf("failed to parse table metadata: %w", err)
+	}
+
+	return tableMetadataFromRows(project, dataset, rows)
+}
+
+// ListPartitions queries INFORMATION_SCHEMA.PARTITIONS through bq query
+func (b *CLIBackend) ListPartitions(ctx context.Context, project, dataset, table string) ([]PartitionInfo, error) {
+	maxRows := fmt.Sprintf("--max_rows=%d", config.CLIMaxTableListResults)
+	output, err := b.bq(ctx, "query", "--project_id="+project, "--nouse_legacy_sql", "--format=json", maxRows,
+		partitionsQuery(project, dataset, table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query partitions: %w", err)
+	}
+
+	// bq prints nothing at all for an empty result
+	partitions := []PartitionInfo{}
+	if len(bytes.TrimSpace(output)) == 0 {
+		return partitions, nil
+	}
+	if err := json.Unmarshal(output, &partitions); err != nil {
+		return nil, fmt.Errorf("failed to parse partitions: %w", err)
+	}
+	return partitions, nil
+}
+
+// ListTableStorage queries INFORMATION_SCHEMA.TABLE_STORAGE through bq query
+func (b *CLIBackend) ListTableStorage(ctx context.Context, project, location string) ([]TableStorage, error) {
+	maxRows := fmt.Sprintf("--max_rows=%d", config.CLIMaxTableListResults)
+	output, err := b.bq(ctx, "query", "--project_id="+project, "--nouse_legacy_sql", "--format=json", maxRows,
+		tableStorageQuery(project, location))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table storage: %w", err)
+	}
+
+	// bq prints nothing at all for an empty result
+	tables := []TableStorage{}
+	if len(bytes.TrimSpace(output)) == 0 {
+		return tables, nil
+	}
+	if err := json.Unmarshal(output, &tables); err != nil {
+		return nil, fmt.Errorf("failed to parse table storage: %w", err)
+	}
+	return tables, nil
+}
+
+// ListTableJobs queries INFORMATION_SCHEMA.JOBS_BY_PROJECT through bq query
+func (b *CLIBackend) ListTableJobs(ctx context.Context, project, location, dataset, table string, days int) ([]TableJob, error) {
+	maxRows := fmt.Sprintf("--max_rows=%d", config.JobHistoryLimit)
+	output, err := b.bq(ctx, "query", "--project_id="+project, "--nouse_legacy_sql", "--format=json", maxRows,
+		tableJobsQuery(project, location, dataset, table, days))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query jobs: %w", err)
+	}
+
+	// bq prints nothing at all for an empty result
+	jobs := []TableJob{}
+	if len(bytes.TrimSpace(output)) == 0 {
+		return jobs, nil
+	}
+	if err := json.Unmarshal(output, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to parse jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// ListTableReads queries INFORMATION_SCHEMA.JOBS_BY_PROJECT through bq query
+func (b *CLIBackend) ListTableReads(ctx context.Context, project, location, dataset string, days int) ([]TableReads, error) {
+	maxRows := fmt.Sprintf("--max_rows=%d", config.CLIMaxTableListResults)
+	output, err := b.bq(ctx, "query", "--project_id="+project, "--nouse_legacy_sql", "--format=json", maxRows,
+		tableReadsQuery(project, location, dataset, days))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query jobs: %w", err)
+	}
+
+	// bq prints nothing at all for an empty result
+	reads := []TableReads{}
+	if len(bytes.TrimSpace(output)) == 0 {
+		return reads, nil
+	}
+	if err := json.Unmarshal(output, &reads); err != nil {
+		return nil, fmt.Errorf("failed to parse table reads: %w", err)
+	}
+	return reads, nil
+}
+
+// ListRows calls bq head, which reads rows with tabledata.list
+func (b *CLIBackend) ListRows(ctx context.Context, project, dataset, table string, maxResults int) ([]map[string]interface{}, error) {
+	tableID := dataset + "." + table
+	maxRows := fmt.Sprintf("--max_rows=%d", maxResults)
+	output, err := b.bq(ctx, "head", "--project_id="+project, "--format=json", maxRows, tableID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	if len(bytes.TrimSpace(output)) == 0 {
+		return []map[string]interface{}{}, nil
+	}
+
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(output, &rows); err != nil {
+		return nil, fmt.Errorf("failed to parse rows: %w", err)
+	}
+
+	return rows, nil
+}
+
+// dryRunJob is the part of the job resource bq query --dry_run prints
+type dryRunJob struct {
+	Statistics struct {
+		Query struct {
+			TotalBytesProcessed int64   `json:"totalBytesProcessed,string"`
+			StatementType       string  `json:"statementType"`
+			Schema              *Schema `json:"schema"`
+		} `json:"query"`
+	} `json:"statistics"`
+}
+
+// DryRunQuery calls bq query --dry_run, which prints the job resource
+func (b *CLIBackend) DryRunQuery(ctx context.Context, project, sql string) (*QueryEstimate, error) {
+	output, err := b.bq(ctx, "query", "--project_id="+project, "--nouse_legacy_sql", "--dry_run", "--format=json", sql)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dry-run query: %w", err)
+	}
+
+	var job dryRunJob
+	if err := json.Unmarshal(output, &job); err != nil {
+		return nil, fmt.Errorf("failed to parse dry run: %w", err)
+	}
+
+	stats := job.Statistics.Query
+	return &QueryEstimate{
+		TotalBytesProcessed: stats.TotalBytesProcessed,
+		StatementType:       stats.StatementType,
+		Schema:              stats.Schema,
+	}, nil
+}
+
+// RunQuery calls bq query. bq prints rows in the bq head form but neither
+// the schema nor job statistics, so those are left unset. bq query takes no
+// request ID, so opts.RequestID is ignored and the client doesn't retry.
+func (b *CLIBackend) RunQuery(ctx context.Context, project, sql string, opts QueryOptions) (*QueryResult, error) {
+	args := []string{"query", "--project_id=" + project, "--nouse_legacy_sql", "--format=json"}
+	if opts.MaxRows > 0 {
+		args = append(args, fmt.Sprintf("--max_rows=%d", opts.MaxRows))
+	}
+	if opts.MaxBytesBilled > 0 {
+		args = append(args, fmt.Sprintf("--maximum_bytes_billed=%d", opts.MaxBytesBilled))
+	}
+	// Never through the session: a query in flight when the worker dies
+	// would run again one-shot
+	output, err := b.cassette.Run(ctx, runBQ, append(args, sql)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run query: %w", err)
+	}
+
+	rows := []map[string]interface{}{}
+	if len(bytes.TrimSpace(output)) > 0 {
+		if err := json.Unmarshal(output, &rows); err != nil {
+			return nil, fmt.Errorf("failed to parse query result: %w", err)
+		}
+	}
+
+	return &QueryResult{TablePreview: TablePreview{Rows: rows}}, nil
+}
@@ -3,11 +3,14 @@ package bigquery
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
-	"os/exec"
+	"sort"
 	"strings"
 	"time"
 
+	"golang.org/x/sync/singleflight"
+
 	"bqs/internal/cache"
 	"bqs/internal/config"
 	"bqs/internal/errors"
@@ -15,18 +18,32 @@ import (
 	"bqs/internal/utils"
 )
 
-// Client wraps BigQuery operations with caching
+// Client wraps BigQuery operations with caching. It is safe for concurrent
+// use; concurrent fetches of the same resource are coalesced into one.
 type Client struct {
-	cache cache.Service
+	cache   cache.Service
+	backend Backend
+	flights singleflight.Group // In-flight fetches, by cache key
 }
 
-// NewClient creates a new BigQuery client with caching
+// NewClient creates a new BigQuery client with caching and the default backend
 func NewClient(c cache.Service) *Client {
+	return NewClientWithBackend(c, NewDefaultBackend())
+}
+
+// NewClientWithBackend creates a new BigQuery client with caching on top of the given backend
+func NewClientWithBackend(c cache.Service, b Backend) *Client {
 	return &Client{
-		cache: c,
+		cache:   c,
+		backend: b,
 	}
 }
 
+// Backend returns the backend the client delegates to
+func (c *Client) Backend() Backend {
+	return c.backend
+}
+
 // IsTableMetadataCached checks if table metadata is available in cache
 func (c *Client) IsTableMetadataCached(project, dataset, table string) bool {
 	key := cache.MetadataKey(project, dataset, table)
@@ -49,6 +66,64 @@ type TableInfo struct {
 	Location         string         `json:"location,omitempty"`
 	FriendlyName     string         `json:"friendlyName,omitempty"`
 	Description      string         `json:"description,omitempty"`
+
+	// Storage layout and lifecycle; tables.list returns the first few, the rest
+	// only come with tables.get
+	TimePartitioning        *TimePartitioning        `json:"timePartitioning,omitempty"`
+	RangePartitioning       *RangePartitioning       `json:"rangePartitioning,omitempty"`
+	Clustering              *Clustering              `json:"clustering,omitempty"`
+	RequirePartitionFilter  bool                     `json:"requirePartitionFilter,omitempty"`
+	Labels                  map[string]string        `json:"labels,omitempty"`
+	ExpirationTime          int64                    `json:"expirationTime,string,omitempty"`
+	EncryptionConfiguration *EncryptionConfiguration `json:"encryptionConfiguration,omitempty"`
+	StreamingBuffer         *StreamingBuffer         `json:"streamingBuffer,omitempty"`
+	NumLongTermBytes        int64                    `json:"numLongTermBytes,string,omitempty"`
+}
+
+// TimePartitioning describes time-unit or ingestion-time partitioning
+type TimePartitioning struct {
+	Type                   string `json:"type"`            // HOUR, DAY, MONTH, YEAR
+	Field                  string `json:"field,omitempty"` // Empty for ingestion-time partitioning
+	ExpirationMs           int64  `json:"expirationMs,string,omitempty"`
+	RequirePartitionFilter bool   `json:"requirePartitionFilter,omitempty"` // Deprecated in favour of the table-level flag
+}
+
+// RangePartitioning describes integer-range partitioning
+type RangePartitioning struct {
+	Field string `json:"field"`
+	Range struct {
+		Start    int64 `json:"start,string"`
+		End      int64 `json:"end,string"`
+		Interval int64 `json:"interval,string"`
+	} `json:"range"`
+}
+
+// Clustering lists the clustering columns in order
+type Clustering struct {
+	Fields []string `json:"fields"`
+}
+
+// EncryptionConfiguration names the Cloud KMS key of a CMEK-protected table
+type EncryptionConfiguration struct {
+	KmsKeyName string `json:"kmsKeyName,omitempty"`
+}
+
+// StreamingBuffer estimates data still in the streaming buffer
+type StreamingBuffer struct {
+	EstimatedBytes  int64 `json:"estimatedBytes,string,omitempty"`
+	EstimatedRows   int64 `json:"estimatedRows,string,omitempty"`
+	OldestEntryTime int64 `json:"oldestEntryTime,string,omitempty"`
+}
+
+// PartitionFilterRequired reports whether queries must filter on the partition
+// column, honouring the deprecated timePartitioning flag too
+func (t TableInfo) PartitionFilterRequired() bool {
+	return t.RequirePartitionFilter || (t.TimePartitioning != nil && t.TimePartitioning.RequirePartitionFilter)
+}
+
+// Partitioned reports whether the table is time or integer range partitioned
+func (t TableInfo) Partitioned() bool {
+	return t.TimePartitioning != nil || t.RangePartitioning != nil
 }
 
 // TableReference represents BigQuery table reference
@@ -58,9 +133,107 @@ type TableReference struct {
 	TableID   string `json:"tableId"`
 }
 
+// ProjectInfo represents a BigQuery project list entry
+type ProjectInfo struct {
+	ProjectReference ProjectReference `json:"projectReference"`
+	FriendlyName     string           `json:"friendlyName,omitempty"`
+	NumericID        string           `json:"numericId,omitempty"`
+	Favorite         bool             `json:"favorite,omitempty"` // Not an API field; set for BQS_FAVORITE_PROJECTS entries
+}
+
+// ProjectReference identifies a BigQuery project
+type ProjectReference struct {
+	ProjectID string `json:"projectId"`
+}
+
+// DatasetInfo represents a BigQuery dataset list entry
+type DatasetInfo struct {
+	DatasetReference         DatasetReference  `json:"datasetReference"`
+	Location                 string            `json:"location,omitempty"`
+	FriendlyName             string            `json:"friendlyName,omitempty"`
+	Labels                   map[string]string `json:"labels,omitempty"`
+	CreationTime             int64             `json:"creationTime,string,omitempty"`
+	DefaultTableExpirationMs int64             `json:"defaultTableExpirationMs,string,omitempty"`
+	TableCount               *int              `json:"tableCount,omitempty"` // Not an API field; nil when the backend can't count cheaply
+}
+
+// DatasetReference represents BigQuery dataset reference
+type DatasetReference struct {
+	ProjectID string `json:"projectId"`
+	DatasetID string `json:"datasetId"`
+}
+
+// DatasetMetadata represents complete dataset metadata as returned by datasets.get
+type DatasetMetadata struct {
+	DatasetInfo
+	Description                  string        `json:"description,omitempty"`
+	LastModifiedTime             int64         `json:"lastModifiedTime,string,omitempty"`
+	DefaultPartitionExpirationMs int64         `json:"defaultPartitionExpirationMs,string,omitempty"`
+	DefaultCollation             string        `json:"defaultCollation,omitempty"`
+	StorageBillingModel          string        `json:"storageBillingModel,omitempty"`
+	Access                       []AccessEntry `json:"access,omitempty"`
+}
+
+// AccessEntry is a single dataset ACL entry. Exactly one grantee field is set.
+type AccessEntry struct {
+	Role         string            `json:"role,omitempty"`
+	UserByEmail  string            `json:"userByEmail,omitempty"`
+	GroupByEmail string            `json:"groupByEmail,omitempty"`
+	Domain       string            `json:"domain,omitempty"`
+	SpecialGroup string            `json:"specialGroup,omitempty"`
+	IAMMember    string            `json:"iamMember,omitempty"`
+	View         *TableReference   `json:"view,omitempty"`
+	Routine      *RoutineReference `json:"routine,omitempty"`
+	Dataset      *AccessDataset    `json:"dataset,omitempty"`
+}
+
+// RoutineReference identifies an authorized routine
+type RoutineReference struct {
+	ProjectID string `json:"projectId"`
+	DatasetID string `json:"datasetId"`
+	RoutineID string `json:"routineId"`
+}
+
+// AccessDataset is an authorized dataset grant
+type AccessDataset struct {
+	Dataset     DatasetReference `json:"dataset"`
+	TargetTypes []string         `json:"targetTypes,omitempty"`
+}
+
+// Grantee describes who an access entry applies to, e.g. "user:jane@example.com"
+func (a AccessEntry) Grantee() string {
+	switch {
+	case a.UserByEmail != "":
+		return "user:" + a.UserByEmail
+	case a.GroupByEmail != "":
+		return "group:" + a.GroupByEmail
+	case a.Domain != "":
+		return "domain:" + a.Domain
+	case a.SpecialGroup != "":
+		return "specialGroup:" + a.SpecialGroup
+	case a.IAMMember != "":
+		return a.IAMMember
+	case a.View != nil:
+		return fmt.Sprintf("view:%s.%s.%s", a.View.ProjectID, a.View.DatasetID, a.View.TableID)
+	case a.Routine != nil:
+		return fmt.Sprintf("routine:%s.%s.%s", a.Routine.ProjectID, a.Routine.DatasetID, a.Routine.RoutineID)
+	case a.Dataset != nil:
+		return fmt.Sprintf("dataset:%s.%s", a.Dataset.Dataset.ProjectID, a.Dataset.Dataset.DatasetID)
+	default:
+		return "unknown"
+	}
+}
+
 // Schema represents BigQuery table schema
 type Schema struct {
 	Fields []SchemaField `json:"fields"`
+
+	tableETag string // The table's etag, when the backend reports it; cached beside the schema
+}
+
+// etag is the version cached along with the schema, for revalidation
+func (s *Schema) etag() string {
+	return s.tableETag
 }
 
 // SchemaField represents a BigQuery schema field
@@ -75,107 +248,315 @@ type SchemaField struct {
 // TableMetadata represents complete table metadata
 type TableMetadata struct {
 	TableInfo
-	Schema *Schema `json:"schema,omitempty"`
+	ETag             string                      `json:"etag,omitempty"` // Changes with any change to the table; tables.get only
+	Schema           *Schema                     `json:"schema,omitempty"`
+	View             *ViewDefinition             `json:"view,omitempty"`
+	MaterializedView *MaterializedViewDefinition `json:"materializedView,omitempty"`
+	Snapshot         *SnapshotDefinition         `json:"snapshotDefinition,omitempty"`
+	Clone            *CloneDefinition            `json:"cloneDefinition,omitempty"`
+	External         *ExternalDataConfiguration  `json:"externalDataConfiguration,omitempty"`
+	BigLake          *BigLakeConfiguration       `json:"biglakeConfiguration,omitempty"`
+	Partial          bool                        `json:"bqsPartial,omitempty"` // Not an API field; set when rebuilt from INFORMATION_SCHEMA, see GetFullTableMetadata
 }
 
-// ListTables retrieves tables in a dataset with caching and retry logic
-func (c *Client) ListTables(project, dataset string) ([]TableInfo, error) {
-	cacheKey := cache.TableListKey(project, dataset)
+// etag is the version cached along with the metadata, for revalidation
+func (m *TableMetadata) etag() string {
+	return m.ETag
+}
+
+// ViewDefinition holds the defining query of a logical view
+type ViewDefinition struct {
+	Query        string `json:"query"`
+	UseLegacySQL bool   `json:"useLegacySql,omitempty"`
+}
+
+// MaterializedViewDefinition holds the query and refresh settings of a
+// materialized view. EnableRefresh is nil when the API omits it (refresh on).
+type MaterializedViewDefinition struct {
+	Query             string `json:"query"`
+	EnableRefresh     *bool  `json:"enableRefresh,omitempty"`
+	RefreshIntervalMs int64  `json:"refreshIntervalMs,string,omitempty"`
+	LastRefreshTime   int64  `json:"lastRefreshTime,string,omitempty"`
+}
+
+// RefreshEnabled reports whether automatic refresh is on, the API default
+func (mv *MaterializedViewDefinition) RefreshEnabled() bool {
+	return mv.EnableRefresh == nil || *mv.EnableRefresh
+}
+
+// DefinitionQuery returns the SQL of a view or materialized view, or "" for tables
+func (t *TableMetadata) DefinitionQuery() string {
+	if t.View != nil {
+		return t.View.Query
+	}
+	if t.MaterializedView != nil {
+		return t.MaterializedView.Query
+	}
+	return ""
+}
+
+// ListProjects retrieves the projects visible to the current credentials with
+// caching and retry logic. Favourite projects are listed first, whether or not
+// the credentials can enumerate them (e.g. public data projects).
+func (c *Client) ListProjects(ctx context.Context, favorites []string) ([]ProjectInfo, error) {
+	cacheKey := cache.ProjectListKey()
+
+	var projects []ProjectInfo
+	cached := false
 
 	// Try cache first
 	if entry, err := c.cache.Get(cacheKey); err == nil {
-		var tables []TableInfo
-		if err := json.Unmarshal([]byte(entry.Data), &tables); err == nil {
-			return tables, nil
+		if err := json.Unmarshal([]byte(entry.Data), &projects); err == nil {
+			cached = true
 		}
 	}
 
-	// Cache miss or invalid data, fetch from BigQuery with retry
-	var tables []TableInfo
-	ctx := context.Background()
-	err := retry.WithQuickRetry(ctx, "list tables", func() error {
-		var fetchErr error
-		tables, fetchErr = c.fetchTableList(project, dataset)
-		if fetchErr != nil {
-			return errors.WrapBigQueryError(fetchErr, "list_tables", project, dataset, "")
+	if !cached {
+		// Cache miss or invalid data, fetch from BigQuery with retry
+		var err error
+		projects, err = fetchShared(ctx, c, cacheKey, config.ProjectListTTL, "project list", func() ([]ProjectInfo, error) {
+			var projects []ProjectInfo
+			err := retry.WithQuickRetry(ctx, "list projects", func() error {
+				var fetchErr error
+				projects, fetchErr = c.backend.ListProjects(ctx)
+				if fetchErr != nil {
+					return errors.WrapBigQueryError(fetchErr, "list_projects", "", "", "")
+				}
+				return nil
+			})
+			sort.Slice(projects, func(i, j int) bool {
+				return projects[i].ProjectReference.ProjectID < projects[j].ProjectReference.ProjectID
+			})
+			return projects, err
+		})
+		if err != nil {
+			return nil, err
 		}
-		return nil
+	}
+
+	return mergeFavoriteProjects(projects, favorites), nil
+}
+
+// mergeFavoriteProjects puts favourites first, in the order given, followed
+// by the remaining listed projects
+func mergeFavoriteProjects(projects []ProjectInfo, favorites []string) []ProjectInfo {
+	listed := make(map[string]ProjectInfo, len(projects))
+	for _, p := range projects {
+		listed[p.ProjectReference.ProjectID] = p
+	}
+
+	merged := make([]ProjectInfo, 0, len(projects)+len(favorites))
+	seen := make(map[string]bool, len(favorites))
+	for _, id := range favorites {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		p, ok := listed[id]
+		if !ok {
+			p = ProjectInfo{ProjectReference: ProjectReference{ProjectID: id}}
+		}
+		p.Favorite = true
+		merged = append(merged, p)
+	}
+
+	for _, p := range projects {
+		if !seen[p.ProjectReference.ProjectID] {
+			merged = append(merged, p)
+		}
+	}
+
+	return merged
+}
+
+// ListDatasets retrieves datasets in a project with caching and retry logic
+func (c *Client) ListDatasets(ctx context.Context, project string) ([]DatasetInfo, error) {
+	cacheKey := cache.DatasetListKey(project)
+
+	// Try cache first
+	if entry, err := c.cache.Get(cacheKey); err == nil {
+		var datasets []DatasetInfo
+		if err := json.Unmarshal([]byte(entry.Data), &datasets); err == nil {
+			return c.WithCachedDatasetDetails(datasets), nil
+		}
+	}
+
+	// Cache miss or invalid data, fetch from BigQuery with retry
+	datasets, err := fetchShared(ctx, c, cacheKey, config.DatasetListTTL, "dataset list", func() ([]DatasetInfo, error) {
+		var datasets []DatasetInfo
+		err := retry.WithQuickRetry(ctx, "list datasets", func() error {
+			var fetchErr error
+			datasets, fetchErr = c.backend.ListDatasets(ctx, project)
+			if fetchErr != nil {
+				return errors.WrapBigQueryError(fetchErr, "list_datasets", project, "", "")
+			}
+			return nil
+		})
+		sort.Slice(datasets, func(i, j int) bool {
+			return datasets[i].DatasetReference.DatasetID < datasets[j].DatasetReference.DatasetID
+		})
+		return datasets, err
 	})
-	
 	if err != nil {
 		return nil, err
 	}
+	return c.WithCachedDatasetDetails(datasets), nil
+}
 
-	// Cache the result
-	data, err := json.Marshal(tables)
-	if err != nil {
-		// Log cache error but don't fail - continue without caching
-		if cacheErr := errors.WrapCacheError(err, "marshal table list"); cacheErr != nil {
-			fmt.Printf("Warning: %s\n", cacheErr.UserFriendlyMessage())
+// WithCachedDatasetDetails returns a copy of datasets with the columns
+// datasets.list leaves out filled in from what's already cached: creation
+// time and default expiration from GetDatasetMetadata, and the table count
+// from a cached table list. It never calls BigQuery, so details appear as
+// datasets are opened rather than costing two calls per dataset per listing.
+func (c *Client) WithCachedDatasetDetails(datasets []DatasetInfo) []DatasetInfo {
+	filled := make([]DatasetInfo, len(datasets))
+	copy(filled, datasets)
+	for i := range filled {
+		ds := &filled[i]
+		project, dataset := ds.DatasetReference.ProjectID, ds.DatasetReference.DatasetID
+
+		if entry, err := c.cache.Get(cache.DatasetMetadataKey(project, dataset)); err == nil {
+			var metadata DatasetMetadata
+			if err := json.Unmarshal([]byte(entry.Data), &metadata); err == nil {
+				ds.CreationTime = metadata.CreationTime
+				ds.DefaultTableExpirationMs = metadata.DefaultTableExpirationMs
+			}
+		}
+
+		if ds.TableCount == nil {
+			if entry, err := c.cache.Get(cache.TableListKey(project, dataset)); err == nil {
+				var tables []TableInfo
+				if err := json.Unmarshal([]byte(entry.Data), &tables); err == nil {
+					count := len(tables)
+					ds.TableCount = &count
+				}
+			}
 		}
-		return tables, nil
 	}
-	ttl := config.TableListTTL
-	if err := c.cache.Set(cacheKey, string(data), &ttl); err != nil {
-		// Log cache error but don't fail - continue without caching
-		if cacheErr := errors.WrapCacheError(err, "set table list cache"); cacheErr != nil {
-			fmt.Printf("Warning: %s\n", cacheErr.UserFriendlyMessage())
+	return filled
+}
+
+// GetDatasetMetadata retrieves dataset metadata with caching and retry logic
+func (c *Client) GetDatasetMetadata(ctx context.Context, project, dataset string) (*DatasetMetadata, error) {
+	cacheKey := cache.DatasetMetadataKey(project, dataset)
+
+	// Try cache first
+	if entry, err := c.cache.Get(cacheKey); err == nil {
+		var metadata DatasetMetadata
+		if err := json.Unmarshal([]byte(entry.Data), &metadata); err == nil {
+			return &metadata, nil
 		}
 	}
 
-	return tables, nil
+	// Cache miss, fetch from BigQuery with retry
+	return fetchShared(ctx, c, cacheKey, config.DatasetMetadataTTL, "dataset metadata", func() (*DatasetMetadata, error) {
+		var metadata *DatasetMetadata
+		err := retry.WithDefaultRetry(ctx, "get dataset metadata", func() error {
+			var fetchErr error
+			metadata, fetchErr = c.backend.GetDatasetMetadata(ctx, project, dataset)
+			if fetchErr != nil {
+				return errors.WrapBigQueryError(fetchErr, "get_dataset_metadata", project, dataset, "")
+			}
+			return nil
+		})
+		return metadata, err
+	})
 }
 
-// GetSchema retrieves table schema with caching and retry logic
-func (c *Client) GetSchema(project, dataset, table string) (*Schema, error) {
-	cacheKey := cache.SchemaKey(project, dataset, table)
+// ProgressFunc reports how many tables have been loaded so far.
+// total is 0 when the backend can't tell how many tables to expect.
+type ProgressFunc func(loaded, total int)
+
+// ListTables retrieves tables in a dataset with caching and retry logic
+func (c *Client) ListTables(ctx context.Context, project, dataset string) ([]TableInfo, error) {
+	return c.ListTablesWithProgress(ctx, project, dataset, nil)
+}
+
+// ListTablesWithProgress retrieves every page of tables in a dataset, calling
+// progress after each page. A listing that arrives in a single page, as bq ls
+// always does, reports no progress, so the caller can show an indeterminate
+// spinner instead. Only the complete listing is cached.
+func (c *Client) ListTablesWithProgress(ctx context.Context, project, dataset string, progress ProgressFunc) ([]TableInfo, error) {
+	cacheKey := cache.TableListKey(project, dataset)
 
 	// Try cache first
 	if entry, err := c.cache.Get(cacheKey); err == nil {
-		var schema Schema
-		if err := json.Unmarshal([]byte(entry.Data), &schema); err == nil {
-			return &schema, nil
+		var tables []TableInfo
+		if err := json.Unmarshal([]byte(entry.Data), &tables); err == nil {
+			return tables, nil
 		}
 	}
 
-	// Cache miss, fetch from BigQuery with retry
-	var schema *Schema
-	ctx := context.Background()
-	err := retry.WithDefaultRetry(ctx, "get schema", func() error {
-		var fetchErr error
-		schema, fetchErr = c.fetchSchema(project, dataset, table)
-		if fetchErr != nil {
-			return errors.WrapBigQueryError(fetchErr, "get_schema", project, dataset, table)
+	// Cache miss or invalid data, walk all pages from BigQuery with per-page
+	// retry. A caller sharing another's walk gets no progress reports.
+	return fetchShared(ctx, c, cacheKey, config.TableListTTL, "table list", func() ([]TableInfo, error) {
+		var tables []TableInfo
+		pageToken := ""
+		for {
+			var page *TablePage
+			err := retry.WithQuickRetry(ctx, "list tables", func() error {
+				var fetchErr error
+				page, fetchErr = c.fetchTablePage(ctx, project, dataset, pageToken)
+				if fetchErr != nil {
+					return errors.WrapBigQueryError(fetchErr, "list_tables", project, dataset, "")
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			tables = append(tables, page.Tables...)
+			if progress != nil && (pageToken != "" || page.NextPageToken != "") {
+				progress(len(tables), page.TotalItems)
+			}
+
+			if page.NextPageToken == "" {
+				return tables, nil
+			}
+			pageToken = page.NextPageToken
 		}
-		return nil
 	})
-	
-	if err != nil {
-		return nil, err
-	}
+}
 
-	// Cache the result
-	data, err := json.Marshal(schema)
-	if err != nil {
-		// Log cache error but don't fail - continue without caching
-		if cacheErr := errors.WrapCacheError(err, "marshal schema"); cacheErr != nil {
-			fmt.Printf("Warning: %s\n", cacheErr.UserFriendlyMessage())
+// GetSchema retrieves table schema with caching and retry logic
+func (c *Client) GetSchema(ctx context.Context, project, dataset, table string) (*Schema, error) {
+	cacheKey := cache.SchemaKey(project, dataset, table)
+
+	// Try cache first
+	if entry, err := c.cache.Get(cacheKey); err == nil {
+		var schema Schema
+		if err := json.Unmarshal([]byte(entry.Data), &schema); err == nil {
+			return &schema, nil
 		}
-		return schema, nil
 	}
-	ttl := config.SchemaTTL
-	if err := c.cache.Set(cacheKey, string(data), &ttl); err != nil {
-		// Log cache error but don't fail - continue without caching
-		if cacheErr := errors.WrapCacheError(err, "set schema cache"); cacheErr != nil {
-			fmt.Printf("Warning: %s\n", cacheErr.UserFriendlyMessage())
+
+	// An expired entry for a table that hasn't changed just lives on
+	if entry := c.revalidate(ctx, cacheKey, config.SchemaTTL, project, dataset, table); entry != nil {
+		var schema Schema
+		if err := json.Unmarshal([]byte(entry.Data), &schema); err == nil {
+			return &schema, nil
 		}
 	}
 
-	return schema, nil
+	// Cache miss, fetch from BigQuery with retry
+	return fetchShared(ctx, c, cacheKey, config.SchemaTTL, "schema", func() (*Schema, error) {
+		var schema *Schema
+		err := retry.WithDefaultRetry(ctx, "get schema", func() error {
+			var fetchErr error
+			schema, fetchErr = c.fetchSchema(ctx, project, dataset, table)
+			if fetchErr != nil {
+				return errors.WrapBigQueryError(fetchErr, "get_schema", project, dataset, table)
+			}
+			return nil
+		})
+		return schema, err
+	})
 }
 
 // GetTableMetadata retrieves complete table metadata with caching and retry logic
-func (c *Client) GetTableMetadata(project, dataset, table string) (*TableMetadata, error) {
+func (c *Client) GetTableMetadata(ctx context.Context, project, dataset, table string) (*TableMetadata, error) {
 	cacheKey := cache.MetadataKey(project, dataset, table)
 
 	// Try cache first
@@ -186,97 +567,307 @@ func (c *Client) GetTableMetadata(project, dataset, table string) (*TableMetadat
 		}
 	}
 
+	// An expired entry for a table that hasn't changed just lives on
+	if entry := c.revalidate(ctx, cacheKey, config.MetadataTTL, project, dataset, table); entry != nil {
+		var metadata TableMetadata
+		if err := json.Unmarshal([]byte(entry.Data), &metadata); err == nil {
+			return &metadata, nil
+		}
+	}
+
 	// Cache miss, fetch from BigQuery with retry
-	var metadata *TableMetadata
-	ctx := context.Background()
-	err := retry.WithDefaultRetry(ctx, "get table metadata", func() error {
+	return c.loadTableMetadata(ctx, project, dataset, table)
+}
+
+// GetFullTableMetadata is GetTableMetadata for views that show every field.
+// Metadata cached by WarmDatasetCache is rebuilt from INFORMATION_SCHEMA and
+// lacks the etag, location, streaming buffer and clone type, so a partial
+// entry is replaced with the full tables.get result first.
+func (c *Client) GetFullTableMetadata(ctx context.Context, project, dataset, table string) (*TableMetadata, error) {
+	metadata, err := c.GetTableMetadata(ctx, project, dataset, table)
+	if err != nil || !metadata.Partial {
+		return metadata, err
+	}
+	return c.loadTableMetadata(ctx, project, dataset, table)
+}
+
+// loadTableMetadata fetches table metadata from BigQuery with retry and
+// caches it
+func (c *Client) loadTableMetadata(ctx context.Context, project, dataset, table string) (*TableMetadata, error) {
+	return fetchShared(ctx, c, cache.MetadataKey(project, dataset, table), config.MetadataTTL, "metadata", func() (*TableMetadata, error) {
+		var metadata *TableMetadata
+		err := retry.WithDefaultRetry(ctx, "get table metadata", func() error {
+			var fetchErr error
+			metadata, fetchErr = c.fetchTableMetadata(ctx, project, dataset, table)
+			if fetchErr != nil {
+				return errors.WrapBigQueryError(fetchErr, "get_metadata", project, dataset, table)
+			}
+			return nil
+		})
+		return metadata, err
+	})
+}
+
+// WarmDatasetCache loads metadata and schemas for every table in the dataset
+// with a single bulk query and caches them. The metadata is cached marked
+// Partial; the schemas are complete. Tables already cached are left alone,
+// since tables.get entries carry details the bulk query can't see.
+// Returns the number of tables newly cached and the number in the dataset.
+func (c *Client) WarmDatasetCache(ctx context.Context, project, dataset string) (int, int, error) {
+	tables, cached, err := c.bulkLoadTableMetadata(ctx, project, dataset)
+	return cached, len(tables), err
+}
+
+// bulkLoadTableMetadata fetches metadata for every table in the dataset with
+// a single bulk query and caches the tables not cached yet. Returns the
+// tables fetched and the number newly cached.
+func (c *Client) bulkLoadTableMetadata(ctx context.Context, project, dataset string) ([]TableMetadata, int, error) {
+	var tables []TableMetadata
+	err := retry.WithDefaultRetry(ctx, "bulk load table metadata", func() error {
 		var fetchErr error
-		metadata, fetchErr = c.fetchTableMetadata(project, dataset, table)
+		tables, fetchErr = c.backend.ListTableMetadata(ctx, project, dataset)
 		if fetchErr != nil {
-			return errors.WrapBigQueryError(fetchErr, "get_metadata", project, dataset, table)
+			return errors.WrapBigQueryError(fetchErr, "bulk_load_metadata", project, dataset, "")
 		}
 		return nil
 	})
-	
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	// Cache the result
-	data, err := json.Marshal(metadata)
-	if err != nil {
-		// Log cache error but don't fail - continue without caching
-		if cacheErr := errors.WrapCacheError(err, "marshal metadata"); cacheErr != nil {
-			fmt.Printf("Warning: %s\n", cacheErr.UserFriendlyMessage())
+	cached := 0
+	metadataTTL, schemaTTL := config.MetadataTTL, config.SchemaTTL
+	for i := range tables {
+		table := tables[i].TableReference.TableID
+		if c.IsTableMetadataCached(project, dataset, table) {
+			continue
 		}
-		return metadata, nil
-	}
-	ttl := config.MetadataTTL
-	if err := c.cache.Set(cacheKey, string(data), &ttl); err != nil {
-		// Log cache error but don't fail - continue without caching
-		if cacheErr := errors.WrapCacheError(err, "set metadata cache"); cacheErr != nil {
-			fmt.Printf("Warning: %s\n", cacheErr.UserFriendlyMessage())
+
+		data, err := json.Marshal(&tables[i])
+		if err != nil {
+			return tables, cached, errors.WrapCacheError(err, "marshal metadata")
+		}
+		if err := c.cache.Set(cache.MetadataKey(project, dataset, table), string(data), &metadataTTL); err != nil {
+			return tables, cached, errors.WrapCacheError(err, "set metadata cache")
 		}
+
+		if tables[i].Schema != nil {
+			data, err := json.Marshal(tables[i].Schema)
+			if err != nil {
+				return tables, cached, errors.WrapCacheError(err, "marshal schema")
+			}
+			if err := c.cache.Set(cache.SchemaKey(project, dataset, table), string(data), &schemaTTL); err != nil {
+				return tables, cached, errors.WrapCacheError(err, "set schema cache")
+			}
+		}
+		cached++
 	}
 
-	return metadata, nil
+	return tables, cached, nil
 }
 
-// fetchTableList calls bq ls to get table list
-func (c *Client) fetchTableList(project, dataset string) ([]TableInfo, error) {
-	cmd := exec.Command("bq", "ls", "--project_id="+project, "--format=json", "--max_results=1000", dataset)
-	output, err := cmd.Output()
+// PreviewTable reads the first maxRows stored rows of a table. Rows are
+// never cached: they go stale faster than metadata and may be sensitive.
+// Views and other tables without stored rows return an error.
+func (c *Client) PreviewTable(ctx context.Context, project, dataset, table string, maxRows int) (*TablePreview, error) {
+	metadata, err := c.GetTableMetadata(ctx, project, dataset, table)
+	if err != nil {
+		return nil, err
+	}
+	if !Previewable(metadata.Type) {
+		return nil, fmt.Errorf("%s.%s.%s is a %s; only tables can be previewed without a query",
+			project, dataset, table, strings.ToLower(strings.ReplaceAll(metadata.Type, "_", " ")))
+	}
+
+	var rows []map[string]interface{}
+	err = retry.WithDefaultRetry(ctx, "preview table", func() error {
+		var fetchErr error
+		rows, fetchErr = c.backend.ListRows(ctx, project, dataset, table, maxRows)
+		if fetchErr != nil {
+			return errors.WrapBigQueryError(fetchErr, "preview_table", project, dataset, table)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list tables: %w", err)
+		return nil, err
 	}
 
-	var tables []TableInfo
-	if err := json.Unmarshal(output, &tables); err != nil {
-		return nil, fmt.Errorf("failed to parse table list: %w", err)
+	schema := metadata.Schema
+	if schema == nil {
+		schema = &Schema{}
 	}
+	return &TablePreview{Schema: schema, Rows: rows}, nil
+}
 
-	// Fix table IDs - use tableReference.tableId if tableId is empty
-	for i := range tables {
-		if tables[i].TableID == "" && tables[i].TableReference.TableID != "" {
-			tables[i].TableID = tables[i].TableReference.TableID
+// DryRunQuery validates a GoogleSQL query and estimates the bytes it would
+// process. Dry runs are free and never cached.
+func (c *Client) DryRunQuery(ctx context.Context, project, sql string) (*QueryEstimate, error) {
+	var estimate *QueryEstimate
+	err := retry.WithDefaultRetry(ctx, "dry-run query", func() error {
+		var fetchErr error
+		estimate, fetchErr = c.backend.DryRunQuery(ctx, project, sql)
+		if fetchErr != nil {
+			return errors.WrapBigQueryError(fetchErr, "dry_run_query", project, "", "")
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return tables, nil
+	if estimate.Schema == nil {
+		estimate.Schema = &Schema{}
+	}
+	return estimate, nil
 }
 
-// fetchSchema calls bq show --schema to get table schema
-func (c *Client) fetchSchema(project, dataset, table string) (*Schema, error) {
-	tableID := dataset + "." + table
-	cmd := exec.Command("bq", "show", "--project_id="+project, "--schema", "--format=json", tableID)
-	output, err := cmd.Output()
+// RunQuery runs a GoogleSQL query and returns its first opts.MaxRows rows.
+// Results are never cached. When the backend can't report the result schema
+// it is taken from a dry run, which is free.
+//
+// Queries are only retried on an IdempotentQuerier backend, which sends
+// opts.RequestID so BigQuery doesn't start a second job for a retry. Other
+// backends run the query once, since retrying DML or DDL could apply and
+// bill it again.
+func (c *Client) RunQuery(ctx context.Context, project, sql string, opts QueryOptions) (*QueryResult, error) {
+	if opts.RequestID == "" {
+		opts.RequestID = newRequestID()
+	}
+
+	var result *QueryResult
+	run := func() error {
+		var fetchErr error
+		result, fetchErr = c.backend.RunQuery(ctx, project, sql, opts)
+		if fetchErr != nil {
+			return errors.WrapBigQueryError(fetchErr, "run_query", project, "", "")
+		}
+		return nil
+	}
+	var err error
+	if querier, ok := c.backend.(IdempotentQuerier); ok && querier.IdempotentQueries() {
+		err = retry.WithDefaultRetry(ctx, "run query", run)
+	} else {
+		err = run()
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get schema: %w", err)
+		return nil, err
 	}
 
-	var fields []SchemaField
-	if err := json.Unmarshal(output, &fields); err != nil {
-		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	if result.Schema == nil {
+		estimate, err := c.DryRunQuery(ctx, project, sql)
+		if err != nil {
+			return nil, err
+		}
+		result.Schema = estimate.Schema
+		if result.TotalBytesProcessed == 0 {
+			result.TotalBytesProcessed = estimate.TotalBytesProcessed
+		}
 	}
+	return result, nil
+}
 
-	return &Schema{Fields: fields}, nil
+// revalidate asks the backend whether the table of an expired cache entry
+// changed since its etag was cached, and if not, extends the entry's life
+// by ttl and returns it. Entries cached without an etag, and any failure,
+// return nil, leaving the caller to fetch the table again. Concurrent
+// callers share one check.
+func (c *Client) revalidate(ctx context.Context, key string, ttl time.Duration, project, dataset, table string) *cache.CacheEntry {
+	entry, err := c.cache.GetStale(key)
+	if err != nil || entry.ETag == "" {
+		return nil
+	}
+	unchanged, _, _ := c.flights.Do("revalidate "+key, func() (interface{}, error) {
+		changed, err := c.backend.TableChanged(ctx, project, dataset, table, entry.ETag)
+		if err != nil || changed {
+			return false, err
+		}
+		return c.cache.Touch(key, &ttl) == nil, nil
+	})
+	if fresh, _ := unchanged.(bool); !fresh {
+		return nil
+	}
+	return entry
+}
+
+// fetchShared calls fetch on a cache miss and caches the result for ttl
+// under key, along with its etag if it has one. Concurrent callers asking
+// for the same key share one fetch and one cache write, and each decode
+// their own copy of the result from the JSON cached, so they are free to
+// modify it. name describes the result in cache errors, e.g. "schema".
+func fetchShared[T any](ctx context.Context, c *Client, key string, ttl time.Duration, name string, fetch func() (T, error)) (T, error) {
+	var result T
+	for {
+		flight := c.flights.DoChan(key, func() (interface{}, error) {
+			fetched, err := fetch()
+			if err != nil {
+				return nil, err
+			}
+			data, err := json.Marshal(fetched)
+			if err != nil {
+				return nil, errors.WrapCacheError(err, "marshal "+name)
+			}
+			var etag string
+			if tagged, ok := any(fetched).(interface{ etag() string }); ok {
+				etag = tagged.etag()
+			}
+			if err := c.cache.Set(key, string(data), &ttl, etag); err != nil {
+				// Log cache error but don't fail - continue without caching
+				if cacheErr := errors.WrapCacheError(err, "set "+name+" cache"); cacheErr != nil {
+					fmt.Printf("Warning: %s\n", cacheErr.UserFriendlyMessage())
+				}
+			}
+			return data, nil
+		})
+
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case shared := <-flight:
+			if shared.Err != nil {
+				// The caller whose fetch this was gave up; start over with ours
+				if shared.Shared && ctx.Err() == nil && isContextError(shared.Err) {
+					continue
+				}
+				return result, shared.Err
+			}
+			if err := json.Unmarshal(shared.Val.([]byte), &result); err != nil {
+				return result, errors.WrapCacheError(err, "unmarshal "+name)
+			}
+			return result, nil
+		}
+	}
+}
+
+// isContextError reports whether err comes from a cancelled or expired context
+func isContextError(err error) bool {
+	return stderrors.Is(err, context.Canceled) || stderrors.Is(err, context.DeadlineExceeded)
 }
 
-// fetchTableMetadata calls bq show to get complete table metadata
-func (c *Client) fetchTableMetadata(project, dataset, table string) (*TableMetadata, error) {
-	tableID := dataset + "." + table
-	cmd := exec.Command("bq", "show", "--project_id="+project, "--format=json", tableID)
-	output, err := cmd.Output()
+// fetchTablePage asks the backend for one page of the table list
+func (c *Client) fetchTablePage(ctx context.Context, project, dataset, pageToken string) (*TablePage, error) {
+	page, err := c.backend.ListTablesPage(ctx, project, dataset, pageToken)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get table metadata: %w", err)
+		return nil, err
 	}
 
-	var metadata TableMetadata
-	if err := json.Unmarshal(output, &metadata); err != nil {
-		return nil, fmt.Errorf("failed to parse table metadata: %w", err)
+	// Fix table IDs - use tableReference.tableId if tableId is empty
+	for i := range page.Tables {
+		if page.Tables[i].TableID == "" && page.Tables[i].TableReference.TableID != "" {
+			page.Tables[i].TableID = page.Tables[i].TableReference.TableID
+		}
 	}
 
-	return &metadata, nil
+	return page, nil
+}
+
+// fetchSchema asks the backend for the table schema
+func (c *Client) fetchSchema(ctx context.Context, project, dataset, table string) (*Schema, error) {
+	return c.backend.GetSchema(ctx, project, dataset, table)
+}
+
+// fetchTableMetadata asks the backend for complete table metadata
+func (c *Client) fetchTableMetadata(ctx context.Context, project, dataset, table string) (*TableMetadata, error) {
+	return c.backend.GetTableMetadata(ctx, project, dataset, table)
 }
 
 // InvalidateCache removes cached data for a specific table or dataset
@@ -289,11 +880,19 @@ func (c *Client) InvalidateCache(project, dataset, table string) error {
 			cache.SchemaKey(project, dataset, table),
 			cache.MetadataKey(project, dataset, table),
 		)
+		// and its schemas at past points in time, which a change to the
+		// table's history (e.g. recreating it) makes stale
+		if err := c.cache.DeletePrefix(cache.SchemaAtPrefix(project, dataset, table)); err != nil {
+			return fmt.Errorf("failed to invalidate past schemas of %s.%s.%s: %w", project, dataset, table, err)
+		}
 	}
 
 	if dataset != "" {
-		// Invalidate dataset table list
-		keys = append(keys, cache.TableListKey(project, dataset))
+		// Invalidate dataset table list and dataset metadata
+		keys = append(keys, cache.TableListKey(project, dataset), cache.DatasetMetadataKey(project, dataset))
+	} else {
+		// Invalidate project dataset list
+		keys = append(keys, cache.DatasetListKey(project))
 	}
 
 	for _, key := range keys {
@@ -319,7 +918,34 @@ func FormatTime(unixMillis int64) string {
 	return t.Format("Jan 2 15:04")
 }
 
-// GetTableTypeIcon returns an icon for the table type
+// FormatExpiration formats a default table expiration in milliseconds
+func FormatExpiration(ms int64) string {
+	if ms <= 0 {
+		return "Never"
+	}
+	d := time.Duration(ms) * time.Millisecond
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	default:
+		return d.String()
+	}
+}
+
+// FormatLabels formats labels as sorted key=value pairs
+func FormatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
+// GetTableTypeIcon returns an icon for the table type, including the CLONE
+// and BIGLAKE display types of TableMetadata.DisplayType
 func GetTableTypeIcon(tableType string) string {
 	switch strings.ToUpper(tableType) {
 	case "TABLE":
@@ -328,7 +954,15 @@ func GetTableTypeIcon(tableType string) string {
 		return "👁️"
 	case "MATERIALIZED_VIEW":
 		return "💎"
+	case "EXTERNAL":
+		return "🔗"
+	case "SNAPSHOT":
+		return "📸"
+	case "CLONE":
+		return "🧬"
+	case "BIGLAKE":
+		return "🌊"
 	default:
 		return "❓"
 	}
-}
\ No newline at end of file
+}
@@ -59,6 +59,28 @@ func TestFormatFunctions(t *testing.T) {
 	}
 }
 
+func TestFormatDatasetFields(t *testing.T) {
+	expirations := map[int64]string{
+		0:          "Never",
+		86400000:   "1d",
+		7776000000: "90d",
+		7200000:    "2h",
+		90000:      "1m30s",
+	}
+	for ms, expected := range expirations {
+		if result := FormatExpiration(ms); result != expected {
+			t.Errorf("FormatExpiration(%d) = %s, expected %s", ms, result, expected)
+		}
+	}
+
+	if result := FormatLabels(map[string]string{"team": "growth", "env": "prod"}); result != "env=prod, team=growth" {
+		t.Errorf("FormatLabels returned %q", result)
+	}
+	if result := FormatLabels(nil); result != "" {
+		t.Errorf("FormatLabels(nil) = %q, expected empty", result)
+	}
+}
+
 func TestGetTableTypeIcon(t *testing.T) {
 	tests := []struct {
 		tableType string
@@ -67,6 +89,10 @@ func TestGetTableTypeIcon(t *testing.T) {
 		{"TABLE", "📋"},
 		{"VIEW", "👁️"},
 		{"MATERIALIZED_VIEW", "💎"},
+		{"EXTERNAL", "🔗"},
+		{"SNAPSHOT", "📸"},
+		{"CLONE", "🧬"},
+		{"BIGLAKE", "🌊"},
 		{"UNKNOWN", "❓"},
 		{"", "❓"},
 	}
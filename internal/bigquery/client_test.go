@@ -1,7 +1,11 @@
 package bigquery
 
 import (
+	"context"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"bqs/internal/cache"
 	"bqs/internal/utils"
@@ -54,4 +58,253 @@ func TestClient(t *testing.T) {
 	}
 
 	t.Logf("Client tests passed successfully")
-}
\ No newline at end of file
+}
+
+// countingCache counts cache writes
+type countingCache struct {
+	cache.Service
+	sets atomic.Int32
+}
+
+func (c *countingCache) Set(key, data string, ttl *time.Duration, etag ...string) error {
+	c.sets.Add(1)
+	return c.Service.Set(key, data, ttl, etag...)
+}
+
+// waitForFetches waits until the backend has started n fetches
+func waitForFetches(t *testing.T, backend *prefetchBackend, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		backend.mu.Lock()
+		started := len(backend.fetched)
+		backend.mu.Unlock()
+		if started >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Backend didn't start %d fetches", n)
+}
+
+func TestConcurrentFetchesAreShared(t *testing.T) {
+	ctx := context.Background()
+	backend := &prefetchBackend{gate: make(chan struct{})}
+	counting := &countingCache{Service: cache.NewMockService()}
+	client := NewClientWithBackend(counting, backend)
+
+	const callers = 8
+	results := make([]*TableMetadata, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			metadata, err := client.GetTableMetadata(ctx, "p", "d", "t")
+			if err != nil {
+				t.Errorf("GetTableMetadata failed: %v", err)
+			}
+			results[i] = metadata
+		}(i)
+	}
+	waitForFetches(t, backend, 1)
+	time.Sleep(20 * time.Millisecond) // Let the other callers join the fetch
+	close(backend.gate)
+	wg.Wait()
+
+	if len(backend.fetched) != 1 {
+		t.Errorf("Backend fetched %d times, want once", len(backend.fetched))
+	}
+	if sets := counting.sets.Load(); sets != 1 {
+		t.Errorf("Cache written %d times, want once", sets)
+	}
+	// Every caller gets its own copy to modify
+	seen := make(map[*TableMetadata]bool)
+	for _, metadata := range results {
+		if metadata == nil || seen[metadata] {
+			t.Fatalf("Callers share results: %v", results)
+		}
+		seen[metadata] = true
+		metadata.TableReference.TableID = "modified"
+	}
+}
+
+func TestSharedFetchOfCancelledCaller(t *testing.T) {
+	backend := &prefetchBackend{gate: make(chan struct{})}
+	client := NewClientWithBackend(cache.NewMockService(), backend)
+
+	// The first caller gives up while a second one waits on its fetch
+	first, cancel := context.WithCancel(context.Background())
+	firstDone := make(chan error, 1)
+	go func() {
+		_, err := client.GetTableMetadata(first, "p", "d", "t")
+		firstDone <- err
+	}()
+	waitForFetches(t, backend, 1)
+
+	secondDone := make(chan error, 1)
+	go func() {
+		_, err := client.GetTableMetadata(context.Background(), "p", "d", "t")
+		secondDone <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if err := <-firstDone; err == nil {
+		t.Error("Cancelled caller didn't fail")
+	}
+
+	// The second caller starts its own fetch rather than failing too
+	waitForFetches(t, backend, 2)
+	close(backend.gate)
+	if err := <-secondDone; err != nil {
+		t.Errorf("Waiting caller failed with its fetch: %v", err)
+	}
+	if !client.IsTableMetadataCached("p", "d", "t") {
+		t.Error("Metadata wasn't cached")
+	}
+}
+
+// versionedBackend serves a table at its current etag, counting full fetches
+// and revalidations
+type versionedBackend struct {
+	Backend
+	etag    string
+	fetches int
+	checks  int
+}
+
+func (b *versionedBackend) GetTableMetadata(ctx context.Context, project, dataset, table string) (*TableMetadata, error) {
+	b.fetches++
+	return &TableMetadata{ETag: b.etag}, nil
+}
+
+func (b *versionedBackend) GetSchema(ctx context.Context, project, dataset, table string) (*Schema, error) {
+	b.fetches++
+	return &Schema{Fields: []SchemaField{{Name: "id", Type: "STRING"}}, tableETag: b.etag}, nil
+}
+
+func (b *versionedBackend) TableChanged(ctx context.Context, project, dataset, table, etag string) (bool, error) {
+	b.checks++
+	return etag != b.etag, nil
+}
+
+func TestExpiredMetadataRevalidated(t *testing.T) {
+	ctx := context.Background()
+	mockCache := cache.NewMockService()
+	backend := &versionedBackend{etag: "v1"}
+	client := NewClientWithBackend(mockCache, backend)
+	key := cache.MetadataKey("p", "d", "t")
+
+	if _, err := client.GetTableMetadata(ctx, "p", "d", "t"); err != nil {
+		t.Fatal(err)
+	}
+	entry, err := mockCache.Get(key)
+	if err != nil || entry.ETag != "v1" {
+		t.Fatalf("Expected the metadata cached with its etag, got %+v, %v", entry, err)
+	}
+
+	// Expire the entry
+	expire := func() {
+		expired := -time.Minute
+		entry, _ := mockCache.GetStale(key)
+		if err := mockCache.Set(key, entry.Data, &expired, entry.ETag); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Unchanged: the entry lives on without a fetch
+	expire()
+	metadata, err := client.GetTableMetadata(ctx, "p", "d", "t")
+	if err != nil || metadata.ETag != "v1" {
+		t.Fatalf("GetTableMetadata returned %+v, %v", metadata, err)
+	}
+	if backend.checks != 1 || backend.fetches != 1 {
+		t.Errorf("Revalidation made %d checks and %d fetches, want one check and no new fetch", backend.checks, backend.fetches-1)
+	}
+	if !client.IsTableMetadataCached("p", "d", "t") {
+		t.Error("Revalidated entry still expired")
+	}
+
+	// Changed: fetched again and cached with the new etag
+	expire()
+	backend.etag = "v2"
+	metadata, err = client.GetTableMetadata(ctx, "p", "d", "t")
+	if err != nil || metadata.ETag != "v2" || backend.fetches != 2 {
+		t.Fatalf("GetTableMetadata returned %+v, %v after %d fetches; want v2 fetched", metadata, err, backend.fetches)
+	}
+	if entry, err := mockCache.Get(key); err != nil || entry.ETag != "v2" {
+		t.Errorf("Expected the new etag cached, got %+v, %v", entry, err)
+	}
+}
+
+func TestExpiredSchemaRevalidated(t *testing.T) {
+	ctx := context.Background()
+	mockCache := cache.NewMockService()
+	backend := &versionedBackend{etag: "v1"}
+	client := NewClientWithBackend(mockCache, backend)
+	key := cache.SchemaKey("p", "d", "t")
+
+	if _, err := client.GetSchema(ctx, "p", "d", "t"); err != nil {
+		t.Fatal(err)
+	}
+	expire := func() {
+		expired := -time.Minute
+		entry, err := mockCache.GetStale(key)
+		if err != nil || entry.ETag != "v1" {
+			t.Fatalf("Expected the schema cached with the table's etag, got %+v, %v", entry, err)
+		}
+		if err := mockCache.Set(key, entry.Data, &expired, entry.ETag); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Unchanged: the entry lives on without a fetch
+	expire()
+	schema, err := client.GetSchema(ctx, "p", "d", "t")
+	if err != nil || len(schema.Fields) != 1 || schema.Fields[0].Name != "id" {
+		t.Fatalf("GetSchema returned %+v, %v", schema, err)
+	}
+	if backend.checks != 1 || backend.fetches != 1 {
+		t.Errorf("Revalidation made %d checks and %d fetches, want one check and no new fetch", backend.checks, backend.fetches-1)
+	}
+	if _, err := mockCache.Get(key); err != nil {
+		t.Error("Revalidated schema still expired")
+	}
+
+	// Changed: fetched again and cached with the new etag
+	expire()
+	backend.etag = "v2"
+	if _, err := client.GetSchema(ctx, "p", "d", "t"); err != nil || backend.fetches != 2 {
+		t.Fatalf("GetSchema returned %v after %d fetches; want the schema fetched again", err, backend.fetches)
+	}
+	if entry, err := mockCache.Get(key); err != nil || entry.ETag != "v2" {
+		t.Errorf("Expected the new etag cached, got %+v, %v", entry, err)
+	}
+}
+
+func TestFullTableMetadataReplacesPartial(t *testing.T) {
+	ctx := context.Background()
+	mockCache := cache.NewMockService()
+	backend := &versionedBackend{etag: "v1"}
+	client := NewClientWithBackend(mockCache, backend)
+	ttl := time.Hour
+	if err := mockCache.Set(cache.MetadataKey("p", "d", "t"), `{"type":"TABLE","bqsPartial":true}`, &ttl); err != nil {
+		t.Fatal(err)
+	}
+
+	// Partial metadata serves everything but detail views
+	metadata, err := client.GetTableMetadata(ctx, "p", "d", "t")
+	if err != nil || !metadata.Partial || backend.fetches != 0 {
+		t.Fatalf("GetTableMetadata returned %+v, %v after %d fetches; want the partial entry", metadata, err, backend.fetches)
+	}
+
+	metadata, err = client.GetFullTableMetadata(ctx, "p", "d", "t")
+	if err != nil || metadata.Partial || metadata.ETag != "v1" || backend.fetches != 1 {
+		t.Fatalf("GetFullTableMetadata returned %+v, %v after %d fetches; want it fetched", metadata, err, backend.fetches)
+	}
+
+	// The full entry replaced the partial one
+	if _, err := client.GetFullTableMetadata(ctx, "p", "d", "t"); err != nil || backend.fetches != 1 {
+		t.Errorf("GetFullTableMetadata returned %v after %d fetches; want the cached full entry", err, backend.fetches)
+	}
+}
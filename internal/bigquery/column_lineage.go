@@ -0,0 +1,766 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ColumnSource is a top-level column of a table or view that feeds a view column
+type ColumnSource struct {
+	Table  TableReference
+	Column string
+}
+
+// String returns the project.dataset.table.column form of the source
+func (s ColumnSource) String() string {
+	return s.Table.String() + "." + s.Column
+}
+
+// ShortName returns dataset.table.column, keeping the project only when it
+// differs from project
+func (s ColumnSource) ShortName(project string) string {
+	if s.Table.ProjectID == project {
+		return s.Table.DatasetID + "." + s.Table.TableID + "." + s.Column
+	}
+	return s.String()
+}
+
+// ViewColumn is an output column of a view and the source columns it is
+// computed from. Sources is empty for constants and columns that couldn't
+// be traced.
+type ViewColumn struct {
+	Name    string
+	Sources []ColumnSource
+}
+
+// starColumn names the placeholder ExtractColumnLineage returns for SELECT *
+// over a table whose columns aren't known
+const starColumn = "*"
+
+// ExtractColumnLineage traces each output column of a GoogleSQL query to the
+// source columns it reads, through aliases, CTEs, subqueries, UNNEST and set
+// operations. schemas maps project.dataset.table to its top-level column
+// names and is used to expand SELECT * and place unqualified columns; tables
+// missing from it are assumed to have whatever columns the query names. A
+// SELECT * over such a table yields a "*" column whose sources name the
+// tables it came from.
+func ExtractColumnLineage(sql, defaultProject string, schemas map[string][]string) []ViewColumn {
+	p := &columnParser{lexemes: lexSQL(sql), project: defaultProject, schemas: schemas}
+	rel := p.parseQuery(nil)
+
+	columns := make([]ViewColumn, 0, len(rel.columns)+1)
+	for _, column := range rel.columns {
+		columns = append(columns, ViewColumn{Name: column.name, Sources: sortSources(column.sources)})
+	}
+	if len(rel.opaque) > 0 {
+		star := ViewColumn{Name: starColumn}
+		for _, ref := range rel.opaque {
+			star.Sources = append(star.Sources, ColumnSource{Table: ref, Column: starColumn})
+		}
+		columns = append(columns, star)
+	}
+	return columns
+}
+
+// relColumn is one output column of a relation
+type relColumn struct {
+	name    string
+	sources []ColumnSource
+}
+
+// relation is the output of a table, CTE or query as seen by an enclosing query
+type relation struct {
+	columns []relColumn
+	opaque  []TableReference // Tables selected with * whose columns are unknown
+}
+
+// column finds an output column by name, ignoring case as GoogleSQL does
+func (r *relation) column(name string) (relColumn, bool) {
+	for _, column := range r.columns {
+		if strings.EqualFold(column.name, name) {
+			return column, true
+		}
+	}
+	return relColumn{}, false
+}
+
+// sourcesOf returns the sources of a named column. Columns of unknown tables
+// selected with * are assumed to exist under the same name.
+func (r *relation) sourcesOf(name string) []ColumnSource {
+	if column, ok := r.column(name); ok {
+		return column.sources
+	}
+	var sources []ColumnSource
+	for _, ref := range r.opaque {
+		sources = append(sources, ColumnSource{Table: ref, Column: name})
+	}
+	return sources
+}
+
+// allSources returns the sources of every column, for references to a whole row
+func (r *relation) allSources() []ColumnSource {
+	var sources []ColumnSource
+	for _, column := range r.columns {
+		sources = append(sources, column.sources...)
+	}
+	for _, ref := range r.opaque {
+		sources = append(sources, ColumnSource{Table: ref, Column: starColumn})
+	}
+	return sources
+}
+
+// fromItem is a source in a FROM clause. UNNEST items have no relation;
+// their alias stands for the array element, computed from value.
+type fromItem struct {
+	alias string // Lower-cased name the item is referenced by
+	rel   *relation
+	value []ColumnSource
+	using []string // Lower-cased USING columns of the join that added the item
+}
+
+// columnParser walks the lexemes of a query, one level of nesting per call
+type columnParser struct {
+	lexemes []sqlLexeme
+	pos     int
+	project string
+	schemas map[string][]string
+}
+
+// peek returns the lexeme offset places ahead, or a zero lexeme past the end
+func (p *columnParser) peek(offset int) sqlLexeme {
+	if p.pos+offset < len(p.lexemes) {
+		return p.lexemes[p.pos+offset]
+	}
+	return sqlLexeme{kind: lexPunct}
+}
+
+func (p *columnParser) atEnd() bool {
+	return p.pos >= len(p.lexemes)
+}
+
+// skipParens moves past the parenthesised group starting at the current "("
+func (p *columnParser) skipParens() {
+	depth := 0
+	for ; !p.atEnd(); p.pos++ {
+		if p.peek(0).is("(") {
+			depth++
+		} else if p.peek(0).is(")") {
+			depth--
+			if depth == 0 {
+				p.pos++
+				return
+			}
+		}
+	}
+}
+
+// atQueryEnd reports whether the current lexeme ends a query at this level
+func (p *columnParser) atQueryEnd() bool {
+	l := p.peek(0)
+	return p.atEnd() || l.is(")") || l.is(";")
+}
+
+// skipUntil moves forward at this nesting level until stop reports true or
+// the query ends
+func (p *columnParser) skipUntil(stop func(sqlLexeme) bool) {
+	for !p.atQueryEnd() && !stop(p.peek(0)) {
+		if p.peek(0).is("(") {
+			p.skipParens()
+			continue
+		}
+		p.pos++
+	}
+}
+
+// isSetOperator reports whether l combines two queries
+func isSetOperator(l sqlLexeme) bool {
+	return l.is("UNION") || l.is("INTERSECT") || l.is("EXCEPT")
+}
+
+// parseQuery parses [WITH ...] query [set_op query ...] [ORDER BY ...] [LIMIT ...]
+func (p *columnParser) parseQuery(ctes map[string]*relation) *relation {
+	if p.peek(0).is("WITH") {
+		p.pos++
+		if p.peek(0).is("RECURSIVE") {
+			p.pos++
+		}
+		// CTEs are visible to later CTEs and the query, not to the enclosing scope
+		scoped := make(map[string]*relation, len(ctes))
+		for name, rel := range ctes {
+			scoped[name] = rel
+		}
+		ctes = scoped
+		for p.peek(0).isIdentifier() {
+			name := strings.ToLower(p.peek(0).text)
+			p.pos++
+			if !p.peek(0).is("AS") || !p.peek(1).is("(") {
+				break
+			}
+			p.pos += 2
+			ctes[name] = p.parseQuery(ctes)
+			if p.peek(0).is(")") {
+				p.pos++
+			}
+			if !p.peek(0).is(",") {
+				break
+			}
+			p.pos++
+		}
+	}
+
+	rel := p.parseSetOperand(ctes)
+	for isSetOperator(p.peek(0)) {
+		p.pos++
+		if p.peek(0).is("ALL") || p.peek(0).is("DISTINCT") {
+			p.pos++
+		}
+		// Set operations match columns by position and take names from the first query
+		next := p.parseSetOperand(ctes)
+		for i := range rel.columns {
+			if i < len(next.columns) {
+				rel.columns[i].sources = append(rel.columns[i].sources, next.columns[i].sources...)
+			}
+		}
+		rel.opaque = append(rel.opaque, next.opaque...)
+	}
+
+	// ORDER BY and LIMIT don't change the columns
+	p.skipUntil(func(sqlLexeme) bool { return false })
+	return rel
+}
+
+// parseSetOperand parses a SELECT or a parenthesised query
+func (p *columnParser) parseSetOperand(ctes map[string]*relation) *relation {
+	switch {
+	case p.peek(0).is("("):
+		p.pos++
+		rel := p.parseQuery(ctes)
+		if p.peek(0).is(")") {
+			p.pos++
+		}
+		return rel
+	case p.peek(0).is("SELECT"):
+		return p.parseSelect(ctes)
+	default:
+		p.skipUntil(isSetOperator)
+		return &relation{}
+	}
+}
+
+// parseSelect parses one SELECT, leaving the parser at a set operator, ORDER
+// BY, LIMIT or the end of the query
+func (p *columnParser) parseSelect(ctes map[string]*relation) *relation {
+	p.pos++
+	if p.peek(0).is("DISTINCT") || p.peek(0).is("ALL") {
+		p.pos++
+	}
+	if p.peek(0).is("AS") { // AS STRUCT, AS VALUE
+		p.pos += 2
+	}
+
+	// The select list is resolved once the FROM clause is known
+	var items [][2]int
+	start := p.pos
+	for !p.atQueryEnd() {
+		l := p.peek(0)
+		if l.is(",") {
+			items = append(items, [2]int{start, p.pos})
+			p.pos++
+			start = p.pos
+			continue
+		}
+		if l.is("FROM") || l.is("WHERE") || l.is("GROUP") || l.is("HAVING") || l.is("QUALIFY") ||
+			l.is("WINDOW") || l.is("ORDER") || l.is("LIMIT") ||
+			(isSetOperator(l) && !(l.is("EXCEPT") && p.pos > 0 && p.lexemes[p.pos-1].is("*"))) {
+			break
+		}
+		if l.is("(") {
+			p.skipParens()
+			continue
+		}
+		p.pos++
+	}
+	items = append(items, [2]int{start, p.pos})
+
+	var from []fromItem
+	if p.peek(0).is("FROM") {
+		p.pos++
+		from = p.parseFrom(ctes)
+	}
+	p.skipUntil(func(l sqlLexeme) bool {
+		return isSetOperator(l) || l.is("ORDER") || l.is("LIMIT")
+	})
+
+	rel := &relation{}
+	anonymous := 0
+	for _, item := range items {
+		if item[0] < item[1] {
+			p.selectItem(rel, from, ctes, item[0], item[1], &anonymous)
+		}
+	}
+	return rel
+}
+
+// parseFrom parses the items of a FROM clause and the joins between them
+func (p *columnParser) parseFrom(ctes map[string]*relation) []fromItem {
+	var items []fromItem
+	for !p.atQueryEnd() {
+		items = p.parseFromItem(ctes, items)
+		if len(items) > 0 && p.peek(0).is("USING") && p.peek(1).is("(") {
+			items[len(items)-1].using = p.parseUsing()
+		}
+
+		// Skip aliases already read, join conditions and FOR SYSTEM_TIME AS OF
+		p.skipUntil(func(l sqlLexeme) bool {
+			return l.is(",") || l.is("JOIN") || l.is("WHERE") || l.is("GROUP") || l.is("HAVING") ||
+				l.is("QUALIFY") || l.is("WINDOW") || l.is("ORDER") || l.is("LIMIT") || isSetOperator(l)
+		})
+		if !p.peek(0).is(",") && !p.peek(0).is("JOIN") {
+			break
+		}
+		p.pos++
+	}
+	return items
+}
+
+// parseUsing reads the column list of a USING (...) join condition
+func (p *columnParser) parseUsing() []string {
+	p.pos += 2
+	var columns []string
+	for !p.atEnd() && !p.peek(0).is(")") {
+		if p.peek(0).isIdentifier() {
+			columns = append(columns, strings.ToLower(p.peek(0).text))
+		}
+		p.pos++
+	}
+	if p.peek(0).is(")") {
+		p.pos++
+	}
+	return columns
+}
+
+// parseFromItem parses a table, CTE, subquery or UNNEST and appends it to items
+func (p *columnParser) parseFromItem(ctes map[string]*relation, items []fromItem) []fromItem {
+	l := p.peek(0)
+	switch {
+	case l.is("(") && (p.peek(1).is("SELECT") || p.peek(1).is("WITH") || p.peek(1).is("(")):
+		p.pos++
+		rel := p.parseQuery(ctes)
+		if p.peek(0).is(")") {
+			p.pos++
+		}
+		return append(items, fromItem{alias: p.parseAlias(), rel: rel})
+
+	case l.is("UNNEST") && p.peek(1).is("("):
+		p.pos++
+		open := p.pos
+		p.skipParens()
+		value := p.resolveExpr(items, ctes, open+1, p.pos-1)
+		items = append(items, fromItem{alias: p.parseAlias(), value: value})
+		if p.peek(0).is("WITH") && p.peek(1).is("OFFSET") {
+			p.pos += 2
+			items = append(items, fromItem{alias: p.parseAlias(), value: []ColumnSource{}})
+		}
+		return items
+
+	case l.isIdentifier():
+		parts, next := parsePath(p.lexemes, p.pos)
+		p.pos = next
+		name := strings.ToLower(parts[len(parts)-1])
+
+		if p.peek(0).is("(") { // Table function; its columns are unknown
+			p.skipParens()
+			return append(items, fromItem{alias: p.aliasOr(name), rel: &relation{}})
+		}
+		// A path starting at an earlier item is an implicit UNNEST of its array
+		if len(parts) > 1 {
+			for _, item := range items {
+				if item.alias == strings.ToLower(parts[0]) {
+					return append(items, fromItem{alias: p.aliasOr(name), value: resolveColumn(parts, items)})
+				}
+			}
+		}
+		if rel, ok := ctes[name]; ok && len(parts) == 1 {
+			return append(items, fromItem{alias: p.aliasOr(name), rel: rel})
+		}
+		rel := &relation{}
+		if ref, ok := tableReference(parts, p.project, nil); ok {
+			rel = p.tableRelation(ref)
+		}
+		return append(items, fromItem{alias: p.aliasOr(name), rel: rel})
+	}
+	return items
+}
+
+// parseAlias reads an optional [AS] alias, returning it lower-cased
+func (p *columnParser) parseAlias() string {
+	if p.peek(0).is("AS") && p.peek(1).isIdentifier() {
+		p.pos += 2
+		return strings.ToLower(p.lexemes[p.pos-1].text)
+	}
+	if p.peek(0).kind == lexWord || p.peek(0).kind == lexQuoted {
+		p.pos++
+		return strings.ToLower(p.lexemes[p.pos-1].text)
+	}
+	return ""
+}
+
+// aliasOr reads an optional alias, defaulting to name
+func (p *columnParser) aliasOr(name string) string {
+	if alias := p.parseAlias(); alias != "" {
+		return alias
+	}
+	return name
+}
+
+// tableRelation returns a table's columns, each its own source
+func (p *columnParser) tableRelation(ref TableReference) *relation {
+	names, ok := p.schemas[ref.String()]
+	if !ok {
+		return &relation{opaque: []TableReference{ref}}
+	}
+	rel := &relation{}
+	for _, name := range names {
+		rel.columns = append(rel.columns, relColumn{name: name, sources: []ColumnSource{{Table: ref, Column: name}}})
+	}
+	return rel
+}
+
+// selectItem adds the columns of the select list item in lexemes[lo:hi] to rel
+func (p *columnParser) selectItem(rel *relation, from []fromItem, ctes map[string]*relation, lo, hi int, anonymous *int) {
+	lexemes := p.lexemes[lo:hi]
+
+	// SELECT * and SELECT alias.*, with EXCEPT and REPLACE modifiers
+	star := -1
+	var starFrom []fromItem
+	if lexemes[0].is("*") {
+		star, starFrom = 0, from
+	} else if len(lexemes) >= 3 && lexemes[0].isIdentifier() && lexemes[1].is(".") && lexemes[2].is("*") {
+		for _, item := range from {
+			if item.alias == strings.ToLower(lexemes[0].text) {
+				star, starFrom = 2, []fromItem{item}
+			}
+		}
+	}
+	if star >= 0 {
+		p.expandStar(rel, starFrom, from, ctes, lo+star+1, hi)
+		return
+	}
+
+	// A trailing identifier after AS, or directly after an expression, is the alias
+	end := len(lexemes)
+	alias := ""
+	if n := len(lexemes); n >= 2 && lexemes[n-1].isIdentifier() {
+		prev := lexemes[n-2]
+		interval := n >= 3 && lexemes[n-3].is("INTERVAL") // INTERVAL 1 DAY
+		if prev.is("AS") {
+			alias, end = lexemes[n-1].text, n-2
+		} else if prev.is(")") || prev.is("END") || prev.isIdentifier() || (prev.kind == lexLiteral && !interval) {
+			alias, end = lexemes[n-1].text, n-1
+		}
+	}
+
+	name := alias
+	if name == "" {
+		// A bare column keeps its name; other expressions get BigQuery's f0_, f1_, ...
+		if parts, next := parseColumnPath(lexemes, 0); len(parts) > 0 && next == end {
+			name = parts[len(parts)-1]
+		} else {
+			name = fmt.Sprintf("f%d_", *anonymous)
+			*anonymous++
+		}
+	}
+	rel.columns = append(rel.columns, relColumn{name: name, sources: p.resolveExpr(from, ctes, lo, lo+end)})
+}
+
+// expandStar adds the columns of items to rel, applying the EXCEPT and
+// REPLACE modifiers in lexemes[lo:hi]
+func (p *columnParser) expandStar(rel *relation, items, from []fromItem, ctes map[string]*relation, lo, hi int) {
+	except := map[string]bool{}
+	replace := map[string][]ColumnSource{}
+	entry := func(modifier sqlLexeme, start, end int) {
+		n := end - start
+		if n == 0 || !p.lexemes[end-1].isIdentifier() {
+			return
+		}
+		name := strings.ToLower(p.lexemes[end-1].text)
+		if modifier.is("EXCEPT") {
+			except[name] = true
+		} else if n >= 3 && p.lexemes[end-2].is("AS") {
+			replace[name] = p.resolveExpr(from, ctes, start, end-2)
+		}
+	}
+	for i := lo; i+1 < hi; i++ {
+		modifier := p.lexemes[i]
+		if (!modifier.is("EXCEPT") && !modifier.is("REPLACE")) || !p.lexemes[i+1].is("(") {
+			continue
+		}
+		// Entries are separated by commas directly inside the parentheses
+		depth, start := 0, i+2
+		j := i + 1
+		for ; j < hi; j++ {
+			switch l := p.lexemes[j]; {
+			case l.is("("):
+				depth++
+			case l.is(")"):
+				depth--
+			case l.is(",") && depth == 1:
+				entry(modifier, start, j)
+				start = j + 1
+			}
+			if depth == 0 {
+				entry(modifier, start, j)
+				break
+			}
+		}
+		i = j
+	}
+
+	// A column joined with USING appears once, read from every joined table
+	start := len(rel.columns)
+	var opaque []TableReference
+	add := func(column relColumn, using []string) {
+		name := strings.ToLower(column.name)
+		if except[name] {
+			return
+		}
+		if sources, ok := replace[name]; ok {
+			column.sources = sources
+		}
+		for _, joined := range using {
+			if joined != name {
+				continue
+			}
+			for i := start; i < len(rel.columns); i++ {
+				if strings.EqualFold(rel.columns[i].name, name) {
+					rel.columns[i].sources = append(append([]ColumnSource{}, rel.columns[i].sources...), column.sources...)
+					return
+				}
+			}
+			// The other side's columns aren't known, so it has the column by name
+			column.sources = append([]ColumnSource{}, column.sources...)
+			for _, ref := range opaque {
+				column.sources = append(column.sources, ColumnSource{Table: ref, Column: column.name})
+			}
+		}
+		rel.columns = append(rel.columns, column)
+	}
+	for _, item := range items {
+		if item.rel == nil {
+			// SELECT * includes UNNEST elements under their alias, but not offsets
+			if item.alias != "" && len(item.value) > 0 {
+				add(relColumn{name: item.alias, sources: item.value}, nil)
+			}
+			continue
+		}
+		for _, column := range item.rel.columns {
+			add(column, item.using)
+		}
+		rel.opaque = append(rel.opaque, item.rel.opaque...)
+		opaque = append(opaque, item.rel.opaque...)
+	}
+}
+
+// parseColumnPath reads a dotted column path such as t.col.field starting at i
+func parseColumnPath(lexemes []sqlLexeme, i int) ([]string, int) {
+	var parts []string
+	for i < len(lexemes) && lexemes[i].isIdentifier() {
+		if lexemes[i].kind == lexQuoted {
+			parts = append(parts, strings.Split(lexemes[i].text, ".")...)
+		} else {
+			parts = append(parts, lexemes[i].text)
+		}
+		i++
+		if i+1 < len(lexemes) && lexemes[i].is(".") && lexemes[i+1].isIdentifier() {
+			i++
+			continue
+		}
+		break
+	}
+	return parts, i
+}
+
+// datePartWords are arguments of date functions that look like column names
+var datePartWords = map[string]bool{
+	"MICROSECOND": true, "MILLISECOND": true, "SECOND": true, "MINUTE": true, "HOUR": true,
+	"DAY": true, "DAYOFWEEK": true, "DAYOFYEAR": true, "WEEK": true, "ISOWEEK": true,
+	"MONTH": true, "QUARTER": true, "YEAR": true, "ISOYEAR": true, "DATE": true, "TIME": true,
+}
+
+// resolveExpr returns the sources of every column an expression in
+// lexemes[lo:hi] reads, including those read by scalar subqueries
+func (p *columnParser) resolveExpr(from []fromItem, ctes map[string]*relation, lo, hi int) []ColumnSource {
+	var sources []ColumnSource
+	for i := lo; i < hi; i++ {
+		l := p.lexemes[i]
+		if l.is("(") && i+1 < hi && (p.lexemes[i+1].is("SELECT") || p.lexemes[i+1].is("WITH")) {
+			sub := &columnParser{lexemes: p.lexemes[:hi], pos: i + 1, project: p.project, schemas: p.schemas}
+			sources = append(sources, sub.parseQuery(ctes).allSources()...)
+			i = sub.pos
+			continue
+		}
+		if l.is("EXTRACT") && i+2 < hi && p.lexemes[i+1].is("(") {
+			i += 2 // The date part
+			continue
+		}
+		if !l.isIdentifier() {
+			continue
+		}
+		if i > lo {
+			// Struct fields, typed literals, CAST(x AS type) and named windows
+			prev := p.lexemes[i-1]
+			if prev.is(".") || prev.kind == lexLiteral || prev.is("AS") || prev.is("OVER") {
+				continue
+			}
+		}
+
+		parts, next := parseColumnPath(p.lexemes[:hi], i)
+		i = next - 1
+		if next < hi && (p.lexemes[next].is("(") || p.lexemes[next].kind == lexLiteral) {
+			continue // Function call or typed literal such as DATE '2024-01-01'
+		}
+		if len(parts) == 1 && datePartWords[strings.ToUpper(parts[0])] {
+			if resolved := resolveColumn(parts, from); len(resolved) > 0 && !isGuess(resolved, from) {
+				sources = append(sources, resolved...)
+			}
+			continue
+		}
+		sources = append(sources, resolveColumn(parts, from)...)
+	}
+	return sources
+}
+
+// resolveColumn finds the sources of a column reference: alias.column,
+// alias (a whole row or UNNEST element), or an unqualified column
+func resolveColumn(parts []string, from []fromItem) []ColumnSource {
+	first := strings.ToLower(parts[0])
+	for _, item := range from {
+		if item.alias != first {
+			continue
+		}
+		if item.rel == nil {
+			return item.value
+		}
+		if len(parts) >= 2 {
+			return item.rel.sourcesOf(parts[1])
+		}
+		return item.rel.allSources()
+	}
+
+	for _, item := range from {
+		if item.rel == nil {
+			continue
+		}
+		if column, ok := item.rel.column(parts[0]); ok {
+			return column.sources
+		}
+	}
+
+	// Otherwise it belongs to the one table whose columns aren't known
+	var unknown []fromItem
+	for _, item := range from {
+		if item.rel != nil && len(item.rel.opaque) > 0 {
+			unknown = append(unknown, item)
+		}
+	}
+	if len(unknown) == 1 {
+		return unknown[0].rel.sourcesOf(parts[0])
+	}
+	return nil
+}
+
+// isGuess reports whether sources were only attributed to a table with
+// unknown columns rather than found in a schema
+func isGuess(sources []ColumnSource, from []fromItem) bool {
+	for _, item := range from {
+		if item.rel == nil {
+			continue
+		}
+		for _, ref := range item.rel.opaque {
+			if sources[0].Table == ref {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sortSources sorts sources and removes duplicates
+func sortSources(sources []ColumnSource) []ColumnSource {
+	seen := map[string]bool{}
+	var unique []ColumnSource
+	for _, source := range sources {
+		key := strings.ToLower(source.String())
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, source)
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool { return unique[i].String() < unique[j].String() })
+	return unique
+}
+
+// ColumnLineage traces each column of a view or materialized view to the
+// columns of the tables it reads. Source schemas come from the cache where
+// possible; sources that can't be read are matched by column name. Columns
+// are returned in the view's schema order.
+func (c *Client) ColumnLineage(ctx context.Context, project, dataset, view string) ([]ViewColumn, error) {
+	metadata, err := c.GetTableMetadata(ctx, project, dataset, view)
+	if err != nil {
+		return nil, err
+	}
+	sql := metadata.DefinitionQuery()
+	if sql == "" {
+		return nil, fmt.Errorf("%s.%s.%s is a %s; only views and materialized views have column lineage",
+			project, dataset, view, strings.ToLower(strings.ReplaceAll(metadata.Type, "_", " ")))
+	}
+
+	schemas := map[string][]string{}
+	for _, ref := range ExtractTableReferences(sql, project) {
+		schema, err := c.GetSchema(ctx, ref.ProjectID, ref.DatasetID, ref.TableID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+		names := make([]string, 0, len(schema.Fields))
+		for _, field := range schema.Fields {
+			names = append(names, field.Name)
+		}
+		schemas[ref.String()] = names
+	}
+
+	columns := ExtractColumnLineage(sql, project, schemas)
+	if metadata.Schema == nil {
+		return columns, nil
+	}
+
+	// The view's schema is authoritative for names and order
+	var star []ColumnSource
+	for _, column := range columns {
+		if column.Name == starColumn {
+			star = column.Sources
+		}
+	}
+	aligned := make([]ViewColumn, 0, len(metadata.Schema.Fields))
+	for _, field := range metadata.Schema.Fields {
+		column := ViewColumn{Name: field.Name}
+		found := false
+		for _, derived := range columns {
+			if strings.EqualFold(derived.Name, field.Name) {
+				column.Sources, found = derived.Sources, true
+				break
+			}
+		}
+		if !found {
+			for _, source := range star {
+				column.Sources = append(column.Sources, ColumnSource{Table: source.Table, Column: field.Name})
+			}
+		}
+		aligned = append(aligned, column)
+	}
+	return aligned, nil
+}
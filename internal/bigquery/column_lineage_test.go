@@ -0,0 +1,193 @@
+package bigquery
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bqs/internal/cache"
+)
+
+// lineageMap flattens column lineage to column -> short source names
+func lineageMap(columns []ViewColumn) map[string]string {
+	out := make(map[string]string, len(columns))
+	for _, column := range columns {
+		names := make([]string, 0, len(column.Sources))
+		for _, source := range column.Sources {
+			names = append(names, source.ShortName("p"))
+		}
+		out[column.Name] = strings.Join(names, ",")
+	}
+	return out
+}
+
+func TestExtractColumnLineage(t *testing.T) {
+	schemas := map[string][]string{
+		"p.raw.events": {"event_id", "user_id", "ts", "items", "payload"},
+		"p.raw.users":  {"id", "name", "country"},
+	}
+
+	tests := []struct {
+		name string
+		sql  string
+		want map[string]string
+	}{
+		{
+			name: "aliases and expressions",
+			sql: `SELECT e.user_id AS uid, DATE(ts) day, COUNT(DISTINCT event_id) AS events, 1 AS one,
+				CASE WHEN ts IS NULL THEN 'none' ELSE 'some' END status, UPPER(u.name)
+				FROM raw.events e JOIN raw.users u ON e.user_id = u.id
+				GROUP BY 1, 2`,
+			want: map[string]string{
+				"uid":    "raw.events.user_id",
+				"day":    "raw.events.ts",
+				"events": "raw.events.event_id",
+				"one":    "",
+				"status": "raw.events.ts",
+				"f0_":    "raw.users.name",
+			},
+		},
+		{
+			name: "star with except and replace",
+			sql:  "SELECT * EXCEPT (payload, items) REPLACE (UPPER(user_id) AS user_id) FROM `p.raw.events`",
+			want: map[string]string{
+				"event_id": "raw.events.event_id",
+				"user_id":  "raw.events.user_id",
+				"ts":       "raw.events.ts",
+			},
+		},
+		{
+			name: "ctes, subqueries and alias star",
+			sql: `WITH active AS (SELECT user_id AS uid, MAX(ts) AS last_seen FROM raw.events GROUP BY 1)
+				SELECT a.*, s.country
+				FROM active a
+				JOIN (SELECT id, country FROM raw.users) s ON s.id = a.uid`,
+			want: map[string]string{
+				"uid":       "raw.events.user_id",
+				"last_seen": "raw.events.ts",
+				"country":   "raw.users.country",
+			},
+		},
+		{
+			name: "unnest and struct fields",
+			sql: `SELECT e.event_id, item.sku, pos, JSON_VALUE(e.payload.source) AS source
+				FROM raw.events e, UNNEST(e.items) AS item WITH OFFSET pos`,
+			want: map[string]string{
+				"event_id": "raw.events.event_id",
+				"sku":      "raw.events.items",
+				"pos":      "",
+				"source":   "raw.events.payload",
+			},
+		},
+		{
+			name: "union merges by position",
+			sql: `SELECT user_id AS id FROM raw.events
+				UNION ALL SELECT id FROM raw.users
+				ORDER BY id LIMIT 10`,
+			want: map[string]string{"id": "raw.events.user_id,raw.users.id"},
+		},
+		{
+			name: "scalar subquery and date parts",
+			sql: `SELECT id, (SELECT MAX(ts) FROM raw.events WHERE user_id = id) AS last_ts,
+				EXTRACT(YEAR FROM CURRENT_DATE()) AS y, TIMESTAMP_TRUNC(ts, MONTH) AS month
+				FROM raw.users, raw.events`,
+			want: map[string]string{
+				"id":      "raw.users.id",
+				"last_ts": "raw.events.ts",
+				"y":       "",
+				"month":   "raw.events.ts",
+			},
+		},
+		{
+			name: "unknown schema is matched by name",
+			sql:  "SELECT *, amount * 2 AS double FROM other.orders",
+			want: map[string]string{
+				"double": "other.orders.amount",
+				"*":      "other.orders.*",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lineageMap(ExtractColumnLineage(tt.sql, "p", schemas))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractColumnLineage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractColumnLineageJoinUsing(t *testing.T) {
+	schemas := map[string][]string{
+		"p.raw.events":   {"event_id", "user_id", "ts"},
+		"p.raw.sessions": {"user_id", "started"},
+	}
+
+	tests := []struct {
+		name  string
+		sql   string
+		names []string
+		want  map[string]string
+	}{
+		{
+			name:  "known schemas",
+			sql:   "SELECT * FROM raw.events JOIN raw.sessions USING (user_id)",
+			names: []string{"event_id", "user_id", "ts", "started"},
+			want: map[string]string{
+				"event_id": "raw.events.event_id",
+				"user_id":  "raw.events.user_id,raw.sessions.user_id",
+				"ts":       "raw.events.ts",
+				"started":  "raw.sessions.started",
+			},
+		},
+		{
+			name:  "unknown left schema",
+			sql:   "SELECT * FROM other.orders o LEFT JOIN raw.sessions s USING (user_id)",
+			names: []string{"user_id", "started", "*"},
+			want: map[string]string{
+				"user_id": "other.orders.user_id,raw.sessions.user_id",
+				"started": "raw.sessions.started",
+				"*":       "other.orders.*",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns := ExtractColumnLineage(tt.sql, "p", schemas)
+			var names []string
+			for _, column := range columns {
+				names = append(names, column.Name)
+			}
+			if !reflect.DeepEqual(names, tt.names) {
+				t.Errorf("Columns %v, want %v", names, tt.names)
+			}
+			if got := lineageMap(columns); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractColumnLineage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientColumnLineage(t *testing.T) {
+	ctx := context.Background()
+	client := NewClientWithBackend(cache.NewMockService(), NewFixtureBackend(fixtureDir))
+
+	columns, err := client.ColumnLineage(ctx, "demo-project", "analytics", "daily_users")
+	if err != nil {
+		t.Fatalf("ColumnLineage returned error: %v", err)
+	}
+	want := []ViewColumn{
+		{Name: "day", Sources: []ColumnSource{{Table: TableReference{ProjectID: "demo-project", DatasetID: "analytics", TableID: "events"}, Column: "event_timestamp"}}},
+		{Name: "users", Sources: []ColumnSource{{Table: TableReference{ProjectID: "demo-project", DatasetID: "analytics", TableID: "events"}, Column: "user_id"}}},
+	}
+	if !reflect.DeepEqual(columns, want) {
+		t.Errorf("ColumnLineage() = %+v, want %+v", columns, want)
+	}
+
+	if _, err := client.ColumnLineage(ctx, "demo-project", "analytics", "events"); err == nil {
+		t.Error("Expected an error for a table without a definition")
+	}
+}
@@ -0,0 +1,98 @@
+package bigquery
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Detail is one labelled line of a table's "Details" section
+type Detail struct {
+	Label string
+	Value string
+}
+
+// TableDetails describes partitioning, clustering, lifecycle and storage
+// settings of a table for display. The browser and static output share it.
+func TableDetails(t TableInfo) []Detail {
+	details := []Detail{{"Partitioning", describePartitioning(t)}}
+
+	if t.TimePartitioning != nil && t.TimePartitioning.ExpirationMs > 0 {
+		details = append(details, Detail{"Partition expiration", FormatExpiration(t.TimePartitioning.ExpirationMs)})
+	}
+	if t.TimePartitioning != nil || t.RangePartitioning != nil {
+		details = append(details, Detail{"Require partition filter", yesNo(t.PartitionFilterRequired())})
+	}
+
+	clustering := "None"
+	if t.Clustering != nil && len(t.Clustering.Fields) > 0 {
+		clustering = strings.Join(t.Clustering.Fields, ", ")
+	}
+	details = append(details, Detail{"Clustering", clustering})
+
+	labels := FormatLabels(t.Labels)
+	if labels == "" {
+		labels = "None"
+	}
+	details = append(details, Detail{"Labels", labels})
+
+	expires := "Never"
+	if t.ExpirationTime > 0 {
+		expires = FormatTime(t.ExpirationTime)
+	}
+	details = append(details, Detail{"Expires", expires})
+
+	encryption := "Google-managed"
+	if t.EncryptionConfiguration != nil && t.EncryptionConfiguration.KmsKeyName != "" {
+		encryption = "Customer-managed (" + t.EncryptionConfiguration.KmsKeyName + ")"
+	}
+	details = append(details, Detail{"Encryption", encryption})
+
+	if t.NumLongTermBytes > 0 {
+		details = append(details, Detail{"Long-term storage", FormatSize(t.NumLongTermBytes)})
+	}
+
+	if sb := t.StreamingBuffer; sb != nil {
+		details = append(details, Detail{"Streaming buffer", fmt.Sprintf("~%d rows, ~%s, oldest entry %s",
+			sb.EstimatedRows, FormatSize(sb.EstimatedBytes), FormatTime(sb.OldestEntryTime))})
+	}
+
+	return details
+}
+
+// SnapshotDetails names the base table and point in time of a snapshot or
+// clone. Other tables have none.
+func SnapshotDetails(t *TableMetadata) []Detail {
+	snapshot, ok := t.SnapshotInfo()
+	if !ok {
+		return nil
+	}
+	label := "Snapshot of"
+	if snapshot.Clone {
+		label = "Clone of"
+	}
+	return []Detail{{label, snapshot.BaseTable.String() + " as of " + snapshot.Time.UTC().Format("2006-01-02 15:04:05 UTC")}}
+}
+
+// describePartitioning summarises time or range partitioning, e.g.
+// "DAY on event_timestamp" or "Integer range on customer_id [0, 100) every 10"
+func describePartitioning(t TableInfo) string {
+	switch {
+	case t.TimePartitioning != nil:
+		if t.TimePartitioning.Field == "" {
+			return t.TimePartitioning.Type + " (ingestion time)"
+		}
+		return t.TimePartitioning.Type + " on " + t.TimePartitioning.Field
+	case t.RangePartitioning != nil:
+		r := t.RangePartitioning.Range
+		return fmt.Sprintf("Integer range on %s [%d, %d) every %d", t.RangePartitioning.Field, r.Start, r.End, r.Interval)
+	default:
+		return "None"
+	}
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
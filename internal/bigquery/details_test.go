@@ -0,0 +1,110 @@
+package bigquery
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTableDetails(t *testing.T) {
+	metadata, err := NewFixtureBackend(fixtureDir).GetTableMetadata(context.Background(), "demo-project", "analytics", "events")
+	if err != nil {
+		t.Fatalf("GetTableMetadata returned error: %v", err)
+	}
+
+	details := map[string]string{}
+	for _, d := range TableDetails(metadata.TableInfo) {
+		details[d.Label] = d.Value
+	}
+
+	expected := map[string]string{
+		"Partitioning":             "DAY on event_timestamp",
+		"Partition expiration":     "365d",
+		"Require partition filter": "Yes",
+		"Clustering":               "user_id, event_id",
+		"Labels":                   "pii=false, team=growth",
+		"Expires":                  "Never",
+		"Encryption":               "Customer-managed (projects/demo-project/locations/us/keyRings/bq/cryptoKeys/events)",
+		"Long-term storage":        "1.0 GB",
+	}
+	for label, value := range expected {
+		if details[label] != value {
+			t.Errorf("%s = %q, expected %q", label, details[label], value)
+		}
+	}
+	if details["Streaming buffer"] == "" {
+		t.Error("Expected streaming buffer details")
+	}
+}
+
+func TestTableDetailsDefaults(t *testing.T) {
+	var table TableInfo
+	table.RangePartitioning = &RangePartitioning{Field: "customer_id"}
+	table.RangePartitioning.Range.End = 100
+	table.RangePartitioning.Range.Interval = 10
+
+	details := TableDetails(table)
+	if details[0].Value != "Integer range on customer_id [0, 100) every 10" {
+		t.Errorf("Unexpected partitioning: %q", details[0].Value)
+	}
+
+	for _, d := range details {
+		switch d.Label {
+		case "Require partition filter":
+			if d.Value != "No" {
+				t.Errorf("Expected partition filter not required, got %q", d.Value)
+			}
+		case "Encryption":
+			if d.Value != "Google-managed" {
+				t.Errorf("Expected Google-managed encryption, got %q", d.Value)
+			}
+		case "Streaming buffer", "Long-term storage", "Partition expiration":
+			t.Errorf("Unexpected %s detail for an empty table", d.Label)
+		}
+	}
+
+	// Ingestion-time partitioning has no column
+	if got := TableDetails(TableInfo{TimePartitioning: &TimePartitioning{Type: "HOUR"}})[0].Value; got != "HOUR (ingestion time)" {
+		t.Errorf("Unexpected ingestion-time partitioning: %q", got)
+	}
+}
+
+func TestExternalDetails(t *testing.T) {
+	metadata := &TableMetadata{External: &ExternalDataConfiguration{
+		SourceURIs:   []string{"gs://b/1", "gs://b/2", "gs://b/3", "gs://b/4"},
+		SourceFormat: "CSV",
+		Autodetect:   true,
+		HivePartitioningOptions: &HivePartitioningOptions{
+			Mode: "CUSTOM", SourceURIPrefix: "gs://b", Fields: []string{"dt"}, RequirePartitionFilter: true,
+		},
+	}}
+	metadata.Type = "EXTERNAL"
+
+	details := map[string]string{}
+	for _, d := range ExternalDetails(metadata) {
+		details[d.Label] = d.Value
+	}
+	expected := map[string]string{
+		"Source format":     "CSV",
+		"Source URIs":       "gs://b/1, gs://b/2, gs://b/3 (+1 more)",
+		"Schema autodetect": "Yes",
+		"Hive partitioning": "CUSTOM under gs://b on dt (filter required)",
+	}
+	for label, value := range expected {
+		if details[label] != value {
+			t.Errorf("%s = %q, expected %q", label, details[label], value)
+		}
+	}
+	if _, ok := details["Connection"]; ok || metadata.DisplayType() != "EXTERNAL" {
+		t.Errorf("Expected a plain external table without a connection, got %v", details)
+	}
+
+	// An Iceberg table's URI is its metadata file
+	metadata.External = &ExternalDataConfiguration{SourceFormat: "ICEBERG", SourceURIs: []string{"gs://b/metadata/v3.metadata.json"}}
+	if all := MetadataDetails(metadata); all[1].Label != "Metadata location" || all[1].Value != "gs://b/metadata/v3.metadata.json" {
+		t.Errorf("Unexpected Iceberg details: %+v", all[:2])
+	}
+
+	if details := ExternalDetails(&TableMetadata{}); len(details) != 0 {
+		t.Errorf("Expected no external details for a native table, got %+v", details)
+	}
+}
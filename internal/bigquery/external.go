@@ -0,0 +1,126 @@
+package bigquery
+
+import (
+	"fmt"
+	"strings"
+
+	"bqs/internal/config"
+)
+
+// ExternalDataConfiguration describes where the data of an external table
+// lives and how BigQuery reads it
+type ExternalDataConfiguration struct {
+	SourceURIs              []string                 `json:"sourceUris,omitempty"`
+	SourceFormat            string                   `json:"sourceFormat,omitempty"` // CSV, PARQUET, ICEBERG, GOOGLE_SHEETS, ...
+	Autodetect              bool                     `json:"autodetect,omitempty"`
+	HivePartitioningOptions *HivePartitioningOptions `json:"hivePartitioningOptions,omitempty"`
+	ConnectionID            string                   `json:"connectionId,omitempty"` // Set on BigLake tables
+	MetadataCacheMode       string                   `json:"metadataCacheMode,omitempty"`
+}
+
+// HivePartitioningOptions describes partition keys encoded in source paths,
+// e.g. gs://bucket/events/dt=2026-10-01/
+type HivePartitioningOptions struct {
+	Mode                   string   `json:"mode,omitempty"` // AUTO, STRINGS or CUSTOM
+	SourceURIPrefix        string   `json:"sourceUriPrefix,omitempty"`
+	RequirePartitionFilter bool     `json:"requirePartitionFilter,omitempty"`
+	Fields                 []string `json:"fields,omitempty"`
+}
+
+// BigLakeConfiguration is set on BigLake managed tables for Apache Iceberg,
+// whose data and metadata BigQuery writes to Cloud Storage
+type BigLakeConfiguration struct {
+	ConnectionID string `json:"connectionId,omitempty"`
+	StorageURI   string `json:"storageUri,omitempty"`
+	FileFormat   string `json:"fileFormat,omitempty"`
+	TableFormat  string `json:"tableFormat,omitempty"`
+}
+
+// DisplayType refines the table type for display: clones and BigLake tables
+// are reported as CLONE and BIGLAKE, which tables.get never returns
+func (t *TableMetadata) DisplayType() string {
+	switch {
+	case t.Clone != nil:
+		return "CLONE"
+	case t.BigLake != nil:
+		return "BIGLAKE"
+	case t.External != nil && t.External.ConnectionID != "":
+		return "BIGLAKE"
+	default:
+		return t.Type
+	}
+}
+
+// ExternalDetails describes where an external or BigLake table's data lives.
+// Tables stored in BigQuery have none.
+func ExternalDetails(t *TableMetadata) []Detail {
+	var details []Detail
+	if ext := t.External; ext != nil {
+		details = append(details, Detail{"Source format", orNone(ext.SourceFormat)})
+		// An Iceberg table's single URI is its metadata file
+		label := "Source URIs"
+		if ext.SourceFormat == "ICEBERG" {
+			label = "Metadata location"
+		}
+		details = append(details, Detail{label, summarizeURIs(ext.SourceURIs)})
+		details = append(details, Detail{"Schema autodetect", yesNo(ext.Autodetect)})
+		if hive := ext.HivePartitioningOptions; hive != nil {
+			details = append(details, Detail{"Hive partitioning", describeHivePartitioning(hive)})
+		}
+		if ext.ConnectionID != "" {
+			details = append(details, Detail{"Connection", ext.ConnectionID})
+		}
+		if ext.MetadataCacheMode != "" {
+			details = append(details, Detail{"Metadata cache", ext.MetadataCacheMode})
+		}
+	}
+	if bl := t.BigLake; bl != nil {
+		details = append(details, Detail{"Storage URI", orNone(bl.StorageURI)})
+		details = append(details, Detail{"Table format", strings.TrimSpace(bl.TableFormat + " " + bl.FileFormat)})
+		if bl.ConnectionID != "" {
+			details = append(details, Detail{"Connection", bl.ConnectionID})
+		}
+	}
+	return details
+}
+
+// MetadataDetails lists every detail of a table for display: what it was
+// copied from, where external data lives, then the TableDetails settings
+func MetadataDetails(t *TableMetadata) []Detail {
+	details := append(SnapshotDetails(t), ExternalDetails(t)...)
+	return append(details, TableDetails(t.TableInfo)...)
+}
+
+// summarizeURIs lists the first few source URIs
+func summarizeURIs(uris []string) string {
+	if len(uris) == 0 {
+		return "None"
+	}
+	if len(uris) <= config.ExternalURIsShown {
+		return strings.Join(uris, ", ")
+	}
+	return fmt.Sprintf("%s (+%d more)", strings.Join(uris[:config.ExternalURIsShown], ", "), len(uris)-config.ExternalURIsShown)
+}
+
+// describeHivePartitioning summarises hive partitioning, e.g.
+// "AUTO under gs://bucket/events (filter required)"
+func describeHivePartitioning(hive *HivePartitioningOptions) string {
+	description := orNone(hive.Mode)
+	if hive.SourceURIPrefix != "" {
+		description += " under " + hive.SourceURIPrefix
+	}
+	if len(hive.Fields) > 0 {
+		description += " on " + strings.Join(hive.Fields, ", ")
+	}
+	if hive.RequirePartitionFilter {
+		description += " (filter required)"
+	}
+	return description
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "None"
+	}
+	return s
+}
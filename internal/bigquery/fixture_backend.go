@@ -0,0 +1,465 @@
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"bqs/internal/config"
+)
+
+// FixtureBackend serves metadata from JSON files on disk instead of BigQuery.
+// Files are laid out as <root>/<project>/<dataset>/<table>.json and hold the
+// same document `bq show --format=json` prints for the table. An optional
+// <root>/<project>/<dataset>.json holds the `bq show` document for the dataset,
+// and <root>/<project>/<dataset>/_rows/<table>.json the `bq head --format=json`
+// output for the table. Canned query results live in <root>/<project>/_queries.json,
+// partitions in <root>/<project>/<dataset>/_partitions/<table>.json, job
+// history in <root>/<project>/<dataset>/_jobs/<table>.json and TABLE_STORAGE
+// rows in <root>/<project>/_storage.json.
+type FixtureBackend struct {
+	root     string
+	pageSize int
+}
+
+// NewFixtureBackend creates a backend reading fixtures below root
+func NewFixtureBackend(root string) *FixtureBackend {
+	return &FixtureBackend{root: root, pageSize: config.TableListPageSize}
+}
+
+// ListProjects returns one entry per project directory below the root
+func (b *FixtureBackend) ListProjects(ctx context.Context) ([]ProjectInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(b.root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture root: %w", err)
+	}
+
+	projects := []ProjectInfo{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			projects = append(projects, ProjectInfo{ProjectReference: ProjectReference{ProjectID: entry.Name()}})
+		}
+	}
+
+	return projects, nil
+}
+
+// ListDatasets returns one entry per dataset directory in the project,
+// merged with the dataset fixture when present
+func (b *FixtureBackend) ListDatasets(ctx context.Context, project string) ([]DatasetInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(b.root, project))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("project %s not found in fixtures", project)
+		}
+		return nil, fmt.Errorf("failed to read fixture project: %w", err)
+	}
+
+	datasets := []DatasetInfo{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dataset := entry.Name()
+
+		metadata, err := b.GetDatasetMetadata(ctx, project, dataset)
+		if err != nil {
+			return nil, err
+		}
+		info := metadata.DatasetInfo
+
+		tables, err := b.listTables(ctx, project, dataset)
+		if err != nil {
+			return nil, err
+		}
+		count := len(tables)
+		info.TableCount = &count
+
+		datasets = append(datasets, info)
+	}
+
+	return datasets, nil
+}
+
+// GetDatasetMetadata reads the dataset fixture. A dataset directory without
+// one yields metadata holding just the reference.
+func (b *FixtureBackend) GetDatasetMetadata(ctx context.Context, project, dataset string) (*DatasetMetadata, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(filepath.Join(b.root, project, dataset)); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("dataset %s.%s not found in fixtures", project, dataset)
+		}
+		return nil, fmt.Errorf("failed to read fixture dataset: %w", err)
+	}
+
+	var metadata DatasetMetadata
+	path := filepath.Join(b.root, project, dataset+".json")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &metadata); err != nil {
+			return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+		}
+	}
+	metadata.DatasetReference = DatasetReference{ProjectID: project, DatasetID: dataset}
+
+	return &metadata, nil
+}
+
+// ListTablesPage returns one page of the table fixtures in the dataset
+// directory, sorted by ID. Page tokens are offsets into that order.
+func (b *FixtureBackend) ListTablesPage(ctx context.Context, project, dataset, pageToken string) (*TablePage, error) {
+	tables, err := b.listTables(ctx, project, dataset)
+	if err != nil {
+		return nil, err
+	}
+
+	start := 0
+	if pageToken != "" {
+		start, err = strconv.Atoi(pageToken)
+		if err != nil || start < 0 || start > len(tables) {
+			return nil, fmt.Errorf("invalid page token %q", pageToken)
+		}
+	}
+
+	end := start + b.pageSize
+	page := &TablePage{TotalItems: len(tables)}
+	if end < len(tables) {
+		page.NextPageToken = strconv.Itoa(end)
+	} else {
+		end = len(tables)
+	}
+	page.Tables = tables[start:end]
+
+	return page, nil
+}
+
+// listTables reads every table fixture in the dataset directory, sorted by ID
+func (b *FixtureBackend) listTables(ctx context.Context, project, dataset string) ([]TableInfo, error) {
+	tables, err := b.ListTableMetadata(ctx, project, dataset)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]TableInfo, len(tables))
+	for i, table := range tables {
+		infos[i] = table.TableInfo
+	}
+	return infos, nil
+}
+
+// ListTableMetadata reads every table fixture in the dataset directory, sorted by ID
+func (b *FixtureBackend) ListTableMetadata(ctx context.Context, project, dataset string) ([]TableMetadata, error) {
+	dir := filepath.Join(b.root, project, dataset)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("dataset %s.%s not found in fixtures", project, dataset)
+		}
+		return nil, fmt.Errorf("failed to read fixture dataset: %w", err)
+	}
+
+	tables := []TableMetadata{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		table := strings.TrimSuffix(entry.Name(), ".json")
+		metadata, err := b.GetTableMetadata(ctx, project, dataset, table)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, *metadata)
+	}
+
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].TableReference.TableID < tables[j].TableReference.TableID
+	})
+
+	return tables, nil
+}
+
+// GetSchema returns the schema section of the table fixture
+func (b *FixtureBackend) GetSchema(ctx context.Context, project, dataset, table string) (*Schema, error) {
+	metadata, err := b.GetTableMetadata(ctx, project, dataset, table)
+	if err != nil {
+		return nil, err
+	}
+	schema := &Schema{tableETag: metadata.ETag}
+	if metadata.Schema != nil {
+		schema.Fields = metadata.Schema.Fields
+	}
+	return schema, nil
+}
+
+// GetTableMetadata reads and parses the table fixture
+func (b *FixtureBackend) GetTableMetadata(ctx context.Context, project, dataset, table string) (*TableMetadata, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(b.root, project, dataset, table+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("table %s.%s.%s not found in fixtures", project, dataset, table)
+		}
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	var metadata TableMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+
+	// Fill in the reference from the file layout when the fixture omits it
+	if metadata.TableReference.ProjectID == "" {
+		metadata.TableReference.ProjectID = project
+	}
+	if metadata.TableReference.DatasetID == "" {
+		metadata.TableReference.DatasetID = dataset
+	}
+	if metadata.TableReference.TableID == "" {
+		metadata.TableReference.TableID = table
+	}
+
+	return &metadata, nil
+}
+
+// TableChanged compares the etag with the table fixture's. Fixtures without
+// an etag always count as changed.
+func (b *FixtureBackend) TableChanged(ctx context.Context, project, dataset, table, etag string) (bool, error) {
+	metadata, err := b.GetTableMetadata(ctx, project, dataset, table)
+	if err != nil {
+		return false, err
+	}
+	return metadata.ETag == "" || metadata.ETag != etag, nil
+}
+
+// ListRows returns the first rows of the table's row fixture. Tables without
+// one have no rows.
+func (b *FixtureBackend) ListRows(ctx context.Context, project, dataset, table string, maxResults int) ([]map[string]interface{}, error) {
+	if _, err := b.GetTableMetadata(ctx, project, dataset, table); err != nil {
+		return nil, err
+	}
+
+	rows := []map[string]interface{}{}
+	path := filepath.Join(b.root, project, dataset, "_rows", table+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return rows, nil
+		}
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+
+	if len(rows) > maxResults {
+		rows = rows[:maxResults]
+	}
+	return rows, nil
+}
+
+// ListPartitions returns the table's partition fixture. Tables without one
+// have no partitions.
+func (b *FixtureBackend) ListPartitions(ctx context.Context, project, dataset, table string) ([]PartitionInfo, error) {
+	if _, err := b.GetTableMetadata(ctx, project, dataset, table); err != nil {
+		return nil, err
+	}
+
+	partitions := []PartitionInfo{}
+	path := filepath.Join(b.root, project, dataset, "_partitions", table+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return partitions, nil
+		}
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	if err := json.Unmarshal(data, &partitions); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	return partitions, nil
+}
+
+// ListTableStorage returns the storage fixture rows of datasets in the
+// location. Projects without a storage fixture have no storage.
+func (b *FixtureBackend) ListTableStorage(ctx context.Context, project, location string) ([]TableStorage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var rows []TableStorage
+	path := filepath.Join(b.root, project, "_storage.json")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &rows); err != nil {
+			return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+		}
+	}
+
+	// Like the regional view, only datasets in the location are included
+	tables := []TableStorage{}
+	for _, row := range rows {
+		metadata, err := b.GetDatasetMetadata(ctx, project, row.DatasetID)
+		if err != nil {
+			continue
+		}
+		if strings.EqualFold(metadata.Location, location) {
+			tables = append(tables, row)
+		}
+	}
+	return tables, nil
+}
+
+// ListTableJobs returns the table's job history fixture, whatever the
+// window, so demo fixtures don't age out. Tables without one have no jobs.
+func (b *FixtureBackend) ListTableJobs(ctx context.Context, project, location, dataset, table string, days int) ([]TableJob, error) {
+	if _, err := b.GetTableMetadata(ctx, project, dataset, table); err != nil {
+		return nil, err
+	}
+
+	jobs := []TableJob{}
+	path := filepath.Join(b.root, project, dataset, "_jobs", table+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return jobs, nil
+		}
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	return jobs, nil
+}
+
+// ListTableReads counts the reads in the job history fixtures of the
+// dataset's tables, whatever the window
+func (b *FixtureBackend) ListTableReads(ctx context.Context, project, location, dataset string, days int) ([]TableReads, error) {
+	tables, err := b.ListTableMetadata(ctx, project, dataset)
+	if err != nil {
+		return nil, err
+	}
+
+	reads := []TableReads{}
+	for _, table := range tables {
+		id := table.TableReference.TableID
+		jobs, err := b.ListTableJobs(ctx, project, location, dataset, id, days)
+		if err != nil {
+			return nil, err
+		}
+		r := TableReads{TableID: id}
+		for _, job := range jobs {
+			if job.Wrote {
+				continue
+			}
+			r.Reads++
+			if job.CreationTime > r.LastRead {
+				r.LastRead = job.CreationTime
+			}
+		}
+		if r.Reads > 0 {
+			reads = append(reads, r)
+		}
+	}
+	return reads, nil
+}
+
+// queryFixture is one entry of a project's _queries.json
+type queryFixture struct {
+	Query               string                   `json:"query"`
+	TotalBytesProcessed int64                    `json:"totalBytesProcessed,string"`
+	StatementType       string                   `json:"statementType"`
+	Schema              *Schema                  `json:"schema"`
+	Rows                []map[string]interface{} `json:"rows"`
+}
+
+// findQuery returns the canned result for sql, matching the query text with
+// whitespace collapsed
+func (b *FixtureBackend) findQuery(ctx context.Context, project, sql string) (*queryFixture, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(b.root, project, "_queries.json")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	var queries []queryFixture
+	if err == nil {
+		if err := json.Unmarshal(data, &queries); err != nil {
+			return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+		}
+	}
+
+	for i := range queries {
+		if normalizeSQL(queries[i].Query) == normalizeSQL(sql) {
+			return &queries[i], nil
+		}
+	}
+	return nil, fmt.Errorf("invalid query: no fixture in %s matches %q", path, normalizeSQL(sql))
+}
+
+// DryRunQuery returns the statistics of the matching query fixture
+func (b *FixtureBackend) DryRunQuery(ctx context.Context, project, sql string) (*QueryEstimate, error) {
+	fixture, err := b.findQuery(ctx, project, sql)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueryEstimate{
+		TotalBytesProcessed: fixture.TotalBytesProcessed,
+		StatementType:       fixture.StatementType,
+		Schema:              fixture.Schema,
+	}, nil
+}
+
+// RunQuery returns the first rows of the matching query fixture, failing the
+// way BigQuery does when the query would bill more than the limit
+func (b *FixtureBackend) RunQuery(ctx context.Context, project, sql string, opts QueryOptions) (*QueryResult, error) {
+	fixture, err := b.findQuery(ctx, project, sql)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxBytesBilled > 0 && fixture.TotalBytesProcessed > opts.MaxBytesBilled {
+		return nil, fmt.Errorf("query exceeded limit for bytes billed: %d", opts.MaxBytesBilled)
+	}
+
+	rows := fixture.Rows
+	if rows == nil {
+		rows = []map[string]interface{}{}
+	}
+	if opts.MaxRows > 0 && len(rows) > opts.MaxRows {
+		rows = rows[:opts.MaxRows]
+	}
+	return &QueryResult{
+		TablePreview:        TablePreview{Schema: fixture.Schema, Rows: rows},
+		TotalBytesProcessed: fixture.TotalBytesProcessed,
+	}, nil
+}
@@ -0,0 +1,385 @@
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bqs/internal/cache"
+	"bqs/internal/errors"
+)
+
+const fixtureDir = "testdata/fixtures"
+
+func TestFixtureBackendListTables(t *testing.T) {
+	ctx := context.Background()
+	backend := NewFixtureBackend(fixtureDir)
+
+	page, err := backend.ListTablesPage(ctx, "demo-project", "analytics", "")
+	if err != nil {
+		t.Fatalf("ListTablesPage returned error: %v", err)
+	}
+	tables := page.Tables
+	if page.NextPageToken != "" || page.TotalItems != 2 {
+		t.Errorf("Expected a single page of 2 tables, got %+v", page)
+	}
+
+	if len(tables) != 2 {
+		t.Fatalf("Expected 2 tables, got %d", len(tables))
+	}
+
+	// Tables are sorted by ID
+	if tables[0].TableReference.TableID != "daily_users" || tables[1].TableReference.TableID != "events" {
+		t.Errorf("Unexpected table order: %s, %s", tables[0].TableReference.TableID, tables[1].TableReference.TableID)
+	}
+
+	if tables[0].Type != "VIEW" {
+		t.Errorf("Expected daily_users to be a VIEW, got %s", tables[0].Type)
+	}
+}
+
+func TestClientListProjectsWithFavorites(t *testing.T) {
+	ctx := context.Background()
+	backend := NewFixtureBackend(fixtureDir)
+	client := NewClientWithBackend(cache.NewMockService(), backend)
+
+	projects, err := client.ListProjects(ctx, []string{"bigquery-public-data", "demo-project"})
+	if err != nil {
+		t.Fatalf("ListProjects returned error: %v", err)
+	}
+
+	// Favourites come first, in order, and listed projects aren't repeated
+	if len(projects) != 2 {
+		t.Fatalf("Expected 2 projects, got %+v", projects)
+	}
+	if projects[0].ProjectReference.ProjectID != "bigquery-public-data" || !projects[0].Favorite {
+		t.Errorf("Expected unlisted favourite first, got %+v", projects[0])
+	}
+	if projects[1].ProjectReference.ProjectID != "demo-project" || !projects[1].Favorite {
+		t.Errorf("Expected listed favourite second, got %+v", projects[1])
+	}
+
+	// The listing is cached without favourites baked in
+	backend.root = t.TempDir()
+	cached, err := client.ListProjects(ctx, nil)
+	if err != nil || len(cached) != 1 || cached[0].Favorite {
+		t.Errorf("Expected cached project list, got %+v (err=%v)", cached, err)
+	}
+}
+
+func TestClientListDatasets(t *testing.T) {
+	ctx := context.Background()
+	backend := NewFixtureBackend(fixtureDir)
+	client := NewClientWithBackend(cache.NewMockService(), backend)
+
+	datasets, err := client.ListDatasets(ctx, "demo-project")
+	if err != nil {
+		t.Fatalf("ListDatasets returned error: %v", err)
+	}
+	if len(datasets) != 1 {
+		t.Fatalf("Expected 1 dataset, got %d", len(datasets))
+	}
+
+	ds := datasets[0]
+	if ds.DatasetReference.DatasetID != "analytics" || ds.Location != "US" || ds.Labels["team"] != "growth" {
+		t.Errorf("Unexpected dataset: %+v", ds)
+	}
+	if ds.TableCount == nil || *ds.TableCount != 2 {
+		t.Errorf("Expected a table count of 2, got %v", ds.TableCount)
+	}
+
+	// The listing is cached
+	backend.root = t.TempDir()
+	if cached, err := client.ListDatasets(ctx, "demo-project"); err != nil || len(cached) != 1 {
+		t.Errorf("Expected cached dataset list, got %d datasets (err=%v)", len(cached), err)
+	}
+
+	_, err = client.ListDatasets(ctx, "missing-project")
+	if bqsErr, ok := err.(*errors.BQSError); !ok || bqsErr.Type != errors.ErrorTypeNotFound {
+		t.Errorf("Expected not found error for missing project, got %v", err)
+	}
+}
+
+func TestClientGetDatasetMetadata(t *testing.T) {
+	ctx := context.Background()
+	backend := NewFixtureBackend(fixtureDir)
+	client := NewClientWithBackend(cache.NewMockService(), backend)
+
+	metadata, err := client.GetDatasetMetadata(ctx, "demo-project", "analytics")
+	if err != nil {
+		t.Fatalf("GetDatasetMetadata returned error: %v", err)
+	}
+	if metadata.DefaultPartitionExpirationMs != 2592000000 || metadata.DefaultCollation != "und:ci" || metadata.StorageBillingModel != "PHYSICAL" {
+		t.Errorf("Unexpected dataset metadata: %+v", metadata)
+	}
+
+	grantees := []string{"specialGroup:projectOwners", "group:analysts@example.com", "view:demo-project.reporting.weekly_summary"}
+	if len(metadata.Access) != len(grantees) {
+		t.Fatalf("Expected %d access entries, got %+v", len(grantees), metadata.Access)
+	}
+	for i, grantee := range grantees {
+		if got := metadata.Access[i].Grantee(); got != grantee {
+			t.Errorf("Access[%d].Grantee() = %s, expected %s", i, got, grantee)
+		}
+	}
+
+	// Served from its own cache entry afterwards
+	backend.root = t.TempDir()
+	if _, err := client.GetDatasetMetadata(ctx, "demo-project", "analytics"); err != nil {
+		t.Errorf("Expected cached dataset metadata, got %v", err)
+	}
+
+	_, err = client.GetDatasetMetadata(ctx, "demo-project", "missing")
+	if bqsErr, ok := err.(*errors.BQSError); !ok || bqsErr.Type != errors.ErrorTypeNotFound {
+		t.Errorf("Expected not found error for missing dataset, got %v", err)
+	}
+}
+
+func TestClientListTablesWalksPages(t *testing.T) {
+	ctx := context.Background()
+	backend := NewFixtureBackend(fixtureDir)
+	backend.pageSize = 1
+	client := NewClientWithBackend(cache.NewMockService(), backend)
+
+	var progress [][2]int
+	tables, err := client.ListTablesWithProgress(ctx, "demo-project", "analytics", func(loaded, total int) {
+		progress = append(progress, [2]int{loaded, total})
+	})
+	if err != nil {
+		t.Fatalf("ListTablesWithProgress returned error: %v", err)
+	}
+
+	if len(tables) != 2 {
+		t.Fatalf("Expected tables from both pages, got %d", len(tables))
+	}
+	if len(progress) != 2 || progress[0] != [2]int{1, 2} || progress[1] != [2]int{2, 2} {
+		t.Errorf("Unexpected progress reports: %v", progress)
+	}
+
+	// The complete listing is cached
+	backend.root = t.TempDir()
+	cached, err := client.ListTables(ctx, "demo-project", "analytics")
+	if err != nil || len(cached) != 2 {
+		t.Errorf("Expected full cached listing, got %d tables (err=%v)", len(cached), err)
+	}
+}
+
+func TestClientListTablesSinglePageReportsNoProgress(t *testing.T) {
+	client := NewClientWithBackend(cache.NewMockService(), NewFixtureBackend(fixtureDir))
+
+	tables, err := client.ListTablesWithProgress(context.Background(), "demo-project", "analytics", func(loaded, total int) {
+		t.Errorf("Unexpected progress report %d/%d for a single-page listing", loaded, total)
+	})
+	if err != nil {
+		t.Fatalf("ListTablesWithProgress returned error: %v", err)
+	}
+	if len(tables) != 2 {
+		t.Errorf("Expected 2 tables, got %d", len(tables))
+	}
+}
+
+func TestFixtureBackendGetTableMetadata(t *testing.T) {
+	ctx := context.Background()
+	backend := NewFixtureBackend(fixtureDir)
+
+	metadata, err := backend.GetTableMetadata(ctx, "demo-project", "analytics", "events")
+	if err != nil {
+		t.Fatalf("GetTableMetadata returned error: %v", err)
+	}
+
+	if metadata.NumRows != 1234567 {
+		t.Errorf("Expected 1234567 rows, got %d", metadata.NumRows)
+	}
+
+	if metadata.Schema == nil || len(metadata.Schema.Fields) != 4 {
+		t.Fatalf("Expected 4 schema fields, got %+v", metadata.Schema)
+	}
+
+	if len(metadata.Schema.Fields[3].Fields) != 2 {
+		t.Errorf("Expected nested event_params fields, got %+v", metadata.Schema.Fields[3])
+	}
+}
+
+func TestViewDefinitions(t *testing.T) {
+	metadata, err := NewFixtureBackend(fixtureDir).GetTableMetadata(context.Background(), "demo-project", "analytics", "daily_users")
+	if err != nil {
+		t.Fatalf("GetTableMetadata returned error: %v", err)
+	}
+	if metadata.View == nil || !strings.HasPrefix(metadata.DefinitionQuery(), "-- Distinct users per day\nSELECT") {
+		t.Errorf("Expected view query, got %+v", metadata.View)
+	}
+
+	var mv TableMetadata
+	data := `{"type": "MATERIALIZED_VIEW", "materializedView": {"query": "SELECT 1", "enableRefresh": false, "refreshIntervalMs": "1800000", "lastRefreshTime": "1733236200000"}}`
+	if err := json.Unmarshal([]byte(data), &mv); err != nil {
+		t.Fatal(err)
+	}
+	if mv.DefinitionQuery() != "SELECT 1" || mv.MaterializedView.RefreshEnabled() || mv.MaterializedView.RefreshIntervalMs != 1800000 {
+		t.Errorf("Unexpected materialized view: %+v", mv.MaterializedView)
+	}
+	if !(&MaterializedViewDefinition{}).RefreshEnabled() {
+		t.Error("Refresh should default to enabled")
+	}
+}
+
+func TestFixtureBackendNotFound(t *testing.T) {
+	ctx := context.Background()
+	client := NewClientWithBackend(cache.NewMockService(), NewFixtureBackend(fixtureDir))
+
+	_, err := client.GetTableMetadata(ctx, "demo-project", "analytics", "missing")
+	bqsErr, ok := err.(*errors.BQSError)
+	if !ok {
+		t.Fatalf("Expected BQSError, got %T: %v", err, err)
+	}
+	if bqsErr.Type != errors.ErrorTypeNotFound {
+		t.Errorf("Expected ErrorTypeNotFound, got %v", bqsErr.Type)
+	}
+
+	_, err = client.ListTables(ctx, "demo-project", "missing")
+	if bqsErr, ok := err.(*errors.BQSError); !ok || bqsErr.Type != errors.ErrorTypeNotFound {
+		t.Errorf("Expected not found error for missing dataset, got %v", err)
+	}
+}
+
+func TestClientCachesBackendResults(t *testing.T) {
+	ctx := context.Background()
+	// Copy a fixture into a temp dir so it can be removed after the first fetch
+	root := t.TempDir()
+	dir := filepath.Join(root, "demo-project", "analytics")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(fixtureDir, "demo-project", "analytics", "events.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "events.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClientWithBackend(cache.NewMockService(), NewFixtureBackend(root))
+
+	tables, err := client.ListTables(ctx, "demo-project", "analytics")
+	if err != nil {
+		t.Fatalf("ListTables returned error: %v", err)
+	}
+	if len(tables) != 1 || tables[0].TableID != "events" {
+		t.Fatalf("Expected TableID to be filled from tableReference, got %+v", tables)
+	}
+
+	if _, err := client.GetTableMetadata(ctx, "demo-project", "analytics", "events"); err != nil {
+		t.Fatalf("GetTableMetadata returned error: %v", err)
+	}
+	if !client.IsTableMetadataCached("demo-project", "analytics", "events") {
+		t.Error("Expected metadata to be cached after fetch")
+	}
+
+	// Subsequent calls must be served from cache
+	if err := os.RemoveAll(root); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetTableMetadata(ctx, "demo-project", "analytics", "events"); err != nil {
+		t.Errorf("Expected cached metadata after fixture removal, got %v", err)
+	}
+	if _, err := client.ListTables(ctx, "demo-project", "analytics"); err != nil {
+		t.Errorf("Expected cached table list after fixture removal, got %v", err)
+	}
+}
+
+func TestFixtureBackendTableChanged(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	dir := filepath.Join(root, "p", "d")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tagged.json"), []byte(`{"type": "TABLE", "etag": "v1"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "untagged.json"), []byte(`{"type": "TABLE"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	backend := NewFixtureBackend(root)
+
+	for _, tc := range []struct {
+		table, etag string
+		changed     bool
+	}{
+		{"tagged", "v1", false},
+		{"tagged", "v0", true},
+		{"untagged", "", true},
+	} {
+		changed, err := backend.TableChanged(ctx, "p", "d", tc.table, tc.etag)
+		if err != nil || changed != tc.changed {
+			t.Errorf("TableChanged(%s, %q) = %v, %v; want %v", tc.table, tc.etag, changed, err, tc.changed)
+		}
+	}
+	if _, err := backend.TableChanged(ctx, "p", "d", "missing", "v1"); err == nil {
+		t.Error("Expected TableChanged to fail for a missing table")
+	}
+}
+
+func TestClientWarmDatasetCache(t *testing.T) {
+	ctx := context.Background()
+	client := NewClientWithBackend(cache.NewMockService(), NewFixtureBackend(fixtureDir))
+
+	// Already cached entries come from tables.get and must be kept
+	if _, err := client.GetTableMetadata(ctx, "demo-project", "analytics", "events"); err != nil {
+		t.Fatalf("GetTableMetadata returned error: %v", err)
+	}
+
+	cached, total, err := client.WarmDatasetCache(ctx, "demo-project", "analytics")
+	if err != nil {
+		t.Fatalf("WarmDatasetCache returned error: %v", err)
+	}
+	if total < 2 || cached != total-1 {
+		t.Errorf("Expected all but one of %d tables to be newly cached, got %d", total, cached)
+	}
+
+	tables, err := client.ListTables(ctx, "demo-project", "analytics")
+	if err != nil {
+		t.Fatalf("ListTables returned error: %v", err)
+	}
+	for _, table := range tables {
+		if !client.IsTableMetadataCached("demo-project", "analytics", table.TableID) {
+			t.Errorf("Expected %s to be cached", table.TableID)
+		}
+	}
+	schema, err := client.GetSchema(ctx, "demo-project", "analytics", "daily_users")
+	if err != nil || len(schema.Fields) == 0 {
+		t.Errorf("Expected cached schema for daily_users, got %+v (%v)", schema, err)
+	}
+
+	if cached, _, err := client.WarmDatasetCache(ctx, "demo-project", "analytics"); err != nil || cached != 0 {
+		t.Errorf("Expected nothing left to cache, got %d (%v)", cached, err)
+	}
+}
+
+func TestClientHonoursCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := NewClientWithBackend(cache.NewMockService(), NewFixtureBackend(fixtureDir))
+	_, err := client.GetTableMetadata(ctx, "demo-project", "analytics", "events")
+	if err != context.Canceled {
+		t.Errorf("Expected context.Canceled without retrying, got %v", err)
+	}
+	if client.IsTableMetadataCached("demo-project", "analytics", "events") {
+		t.Error("Cancelled fetch must not populate the cache")
+	}
+}
+
+func TestNewDefaultBackend(t *testing.T) {
+	t.Setenv("BQS_FIXTURE_DIR", "")
+	if _, ok := NewDefaultBackend().(*CLIBackend); !ok {
+		t.Error("Expected CLI backend by default")
+	}
+
+	t.Setenv("BQS_FIXTURE_DIR", fixtureDir)
+	if _, ok := NewDefaultBackend().(*FixtureBackend); !ok {
+		t.Error("Expected fixture backend when BQS_FIXTURE_DIR is set")
+	}
+}
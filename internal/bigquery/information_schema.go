@@ -0,0 +1,550 @@
+package bigquery
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sqlStringEscaper and sqlIdentEscaper escape the characters GoogleSQL string
+// literals and quoted identifiers can't hold as they are
+var (
+	sqlStringEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`)
+	sqlIdentEscaper  = strings.NewReplacer(`\`, `\\`, "`", "\\`")
+)
+
+// sqlString quotes s as a GoogleSQL string literal
+func sqlString(s string) string {
+	return "'" + sqlStringEscaper.Replace(s) + "'"
+}
+
+// sqlIdent quotes a dotted path, e.g. a project, dataset and view name, as a
+// single GoogleSQL quoted identifier
+func sqlIdent(parts ...string) string {
+	escaped := make([]string, len(parts))
+	for i, part := range parts {
+		escaped[i] = sqlIdentEscaper.Replace(part)
+	}
+	return "`" + strings.Join(escaped, ".") + "`"
+}
+
+// datasetSchemaQuery builds the bulk metadata query for a dataset. It returns
+// one row per table; columns and options are aggregated into JSON strings so
+// every backend can hand back flat rows. Dataset-qualified INFORMATION_SCHEMA
+// views are used so the dataset's region doesn't need to be known up front,
+// and __TABLES__ supplies the row counts and sizes INFORMATION_SCHEMA.TABLES lacks.
+func datasetSchemaQuery(project, dataset string) string {
+	view := func(name string) string {
+		return sqlIdent(project, dataset, name)
+	}
+	return fmt.Sprintf(`WITH columns AS (
+  SELECT
+    p.table_name,
+    TO_JSON_STRING(ARRAY_AGG(STRUCT(p.field_path, p.data_type, p.description, c.is_nullable, c.ordinal_position)
+      ORDER BY c.ordinal_position, p.field_path)) AS columns
+  FROM %s p
+  JOIN %s c USING (table_name, column_name)
+  GROUP BY p.table_name
+), options AS (
+  SELECT table_name, TO_JSON_STRING(ARRAY_AGG(STRUCT(option_name, option_value))) AS options
+  FROM %s
+  GROUP BY table_name
+)
+SELECT
+  t.table_name,
+  t.table_type,
+  UNIX_MILLIS(t.creation_time) AS creation_time,
+  s.last_modified_time,
+  s.row_count,
+  s.size_bytes,
+  t.ddl,
+  t.base_table_catalog,
+  t.base_table_schema,
+  t.base_table_name,
+  UNIX_MILLIS(t.snapshot_time_ms) AS snapshot_time,
+  c.columns,
+  o.options
+FROM %s t
+LEFT JOIN %s s ON s.table_id = t.table_name
+LEFT JOIN columns c USING (table_name)
+LEFT JOIN options o USING (table_name)
+ORDER BY t.table_name`,
+		view("INFORMATION_SCHEMA.COLUMN_FIELD_PATHS"),
+		view("INFORMATION_SCHEMA.COLUMNS"),
+		view("INFORMATION_SCHEMA.TABLE_OPTIONS"),
+		view("INFORMATION_SCHEMA.TABLES"),
+		view("__TABLES__"))
+}
+
+// informationSchemaRow is one row of the bulk metadata query as printed by
+// bq query --format=json: integers arrive as strings and NULLs as null
+type informationSchemaRow struct {
+	TableName        string `json:"table_name"`
+	TableType        string `json:"table_type"`
+	CreationTime     int64  `json:"creation_time,string"`
+	LastModifiedTime int64  `json:"last_modified_time,string"`
+	RowCount         int64  `json:"row_count,string"`
+	SizeBytes        int64  `json:"size_bytes,string"`
+	DDL              string `json:"ddl"`
+	BaseTableCatalog string `json:"base_table_catalog"` // Set on snapshots and clones
+	BaseTableSchema  string `json:"base_table_schema"`
+	BaseTableName    string `json:"base_table_name"`
+	SnapshotTime     int64  `json:"snapshot_time,string"`
+	Columns          string `json:"columns"` // JSON array of informationSchemaColumn
+	Options          string `json:"options"` // JSON array of informationSchemaOption
+}
+
+// informationSchemaColumn is one COLUMN_FIELD_PATHS entry. IsNullable and
+// OrdinalPosition belong to the top-level column the path is part of.
+type informationSchemaColumn struct {
+	FieldPath       string `json:"field_path"`
+	DataType        string `json:"data_type"`
+	Description     string `json:"description"`
+	IsNullable      string `json:"is_nullable"`
+	OrdinalPosition int    `json:"ordinal_position"`
+}
+
+// informationSchemaOption is one TABLE_OPTIONS entry. Values are SQL literals,
+// e.g. "a description" or [STRUCT("team", "growth")].
+type informationSchemaOption struct {
+	OptionName  string `json:"option_name"`
+	OptionValue string `json:"option_value"`
+}
+
+// tableTypes maps INFORMATION_SCHEMA table types to the tables.get names
+var tableTypes = map[string]string{
+	"BASE TABLE":        "TABLE",
+	"CLONE":             "TABLE",
+	"VIEW":              "VIEW",
+	"MATERIALIZED VIEW": "MATERIALIZED_VIEW",
+	"EXTERNAL":          "EXTERNAL",
+	"SNAPSHOT":          "SNAPSHOT",
+}
+
+// tableMetadataFromRows converts bulk query rows into table metadata, marked
+// Partial. The result matches tables.get except for fields INFORMATION_SCHEMA
+// doesn't expose: etag, location, streaming buffer, long-term bytes and MV
+// refresh times.
+func tableMetadataFromRows(project, dataset string, rows []informationSchemaRow) ([]TableMetadata, error) {
+	tables := make([]TableMetadata, 0, len(rows))
+	for _, row := range rows {
+		metadata, err := row.tableMetadata(project, dataset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse metadata for %s: %w", row.TableName, err)
+		}
+		tables = append(tables, *metadata)
+	}
+	return tables, nil
+}
+
+// tableMetadata converts a single bulk query row
+func (r informationSchemaRow) tableMetadata(project, dataset string) (*TableMetadata, error) {
+	metadata := &TableMetadata{Partial: true}
+	metadata.TableReference = TableReference{ProjectID: project, DatasetID: dataset, TableID: r.TableName}
+	metadata.Type = r.TableType
+	if t, ok := tableTypes[r.TableType]; ok {
+		metadata.Type = t
+	}
+	metadata.CreationTime = r.CreationTime
+	metadata.LastModifiedTime = r.LastModifiedTime
+	metadata.NumRows = r.RowCount
+	metadata.NumBytes = r.SizeBytes
+
+	if r.BaseTableName != "" {
+		base := TableReference{ProjectID: r.BaseTableCatalog, DatasetID: r.BaseTableSchema, TableID: r.BaseTableName}
+		at := time.UnixMilli(r.SnapshotTime).UTC()
+		if r.TableType == "CLONE" {
+			metadata.Clone = &CloneDefinition{BaseTableReference: base, CloneTime: at}
+		} else {
+			metadata.Snapshot = &SnapshotDefinition{BaseTableReference: base, SnapshotTime: at}
+		}
+	}
+
+	var columns []informationSchemaColumn
+	if r.Columns != "" {
+		if err := json.Unmarshal([]byte(r.Columns), &columns); err != nil {
+			return nil, fmt.Errorf("invalid columns: %w", err)
+		}
+	}
+	metadata.Schema = schemaFromFieldPaths(columns)
+
+	// The DDL creates the partitioning and view definitions that options refine
+	applyDDL(metadata, r.DDL)
+
+	var options []informationSchemaOption
+	if r.Options != "" {
+		if err := json.Unmarshal([]byte(r.Options), &options); err != nil {
+			return nil, fmt.Errorf("invalid options: %w", err)
+		}
+	}
+	for _, option := range options {
+		applyTableOption(metadata, option)
+	}
+
+	return metadata, nil
+}
+
+// fieldNode is a schema field under construction
+type fieldNode struct {
+	field    SchemaField
+	dataType string
+	children []*fieldNode
+}
+
+// schemaFromFieldPaths rebuilds the nested schema from COLUMN_FIELD_PATHS
+// entries. Field paths carry the name and type of every nested field; the
+// order and NOT NULL constraints of nested fields come from the parent's
+// STRUCT type, since field paths have no ordinal of their own.
+func schemaFromFieldPaths(columns []informationSchemaColumn) *Schema {
+	sorted := make([]informationSchemaColumn, len(columns))
+	copy(sorted, columns)
+	// Parents sort before their children: "a" < "a.b"
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].OrdinalPosition != sorted[j].OrdinalPosition {
+			return sorted[i].OrdinalPosition < sorted[j].OrdinalPosition
+		}
+		return sorted[i].FieldPath < sorted[j].FieldPath
+	})
+
+	var roots []*fieldNode
+	nodes := make(map[string]*fieldNode)
+	for _, column := range sorted {
+		typ, repeated := parseFieldType(column.DataType)
+		node := &fieldNode{
+			field:    SchemaField{Type: typ, Mode: "NULLABLE", Description: column.Description},
+			dataType: column.DataType,
+		}
+		nodes[column.FieldPath] = node
+
+		i := strings.LastIndex(column.FieldPath, ".")
+		if i < 0 {
+			node.field.Name = column.FieldPath
+			if column.IsNullable == "NO" {
+				node.field.Mode = "REQUIRED"
+			}
+			roots = append(roots, node)
+		} else {
+			node.field.Name = column.FieldPath[i+1:]
+			parent, ok := nodes[column.FieldPath[:i]]
+			if !ok {
+				continue // Parent filtered out; nothing to attach to
+			}
+			if structMembers(parent.dataType)[node.field.Name] {
+				node.field.Mode = "REQUIRED"
+			}
+			parent.children = append(parent.children, node)
+		}
+		if repeated {
+			node.field.Mode = "REPEATED"
+		}
+	}
+
+	schema := &Schema{Fields: []SchemaField{}}
+	for _, root := range roots {
+		schema.Fields = append(schema.Fields, root.build())
+	}
+	return schema
+}
+
+// build converts the node and its children into a SchemaField, ordering
+// children as they appear in the STRUCT type
+func (n *fieldNode) build() SchemaField {
+	field := n.field
+	if len(n.children) == 0 {
+		return field
+	}
+	order := structMemberOrder(n.dataType)
+	sort.SliceStable(n.children, func(i, j int) bool {
+		return order[n.children[i].field.Name] < order[n.children[j].field.Name]
+	})
+	for _, child := range n.children {
+		field.Fields = append(field.Fields, child.build())
+	}
+	return field
+}
+
+// legacyTypeNames maps GoogleSQL type names to the names tables.get uses
+var legacyTypeNames = map[string]string{
+	"INT64":   "INTEGER",
+	"FLOAT64": "FLOAT",
+	"BOOL":    "BOOLEAN",
+	"STRUCT":  "RECORD",
+}
+
+// parseFieldType turns a GoogleSQL data type such as ARRAY<STRUCT<a INT64>>
+// or NUMERIC(10, 2) into a schema field type and whether it is repeated
+func parseFieldType(dataType string) (string, bool) {
+	element, repeated := arrayElement(dataType)
+	name := element
+	if i := strings.IndexAny(name, "<( "); i >= 0 {
+		name = name[:i]
+	}
+	name = strings.ToUpper(name)
+	if legacy, ok := legacyTypeNames[name]; ok {
+		name = legacy
+	}
+	return name, repeated
+}
+
+// arrayElement strips an ARRAY<...> wrapper, reporting whether there was one
+func arrayElement(dataType string) (string, bool) {
+	dataType = strings.TrimSpace(dataType)
+	if strings.HasPrefix(strings.ToUpper(dataType), "ARRAY<") && strings.HasSuffix(dataType, ">") {
+		return strings.TrimSpace(dataType[len("ARRAY<") : len(dataType)-1]), true
+	}
+	return dataType, false
+}
+
+// splitStructType splits the members of a STRUCT (or ARRAY<STRUCT>) type at
+// top-level commas, e.g. STRUCT<a INT64, b STRUCT<c STRING>> yields
+// "a INT64" and "b STRUCT<c STRING>". Other types yield nothing.
+func splitStructType(dataType string) []string {
+	element, _ := arrayElement(dataType)
+	if !strings.HasPrefix(strings.ToUpper(element), "STRUCT<") || !strings.HasSuffix(element, ">") {
+		return nil
+	}
+	body := element[len("STRUCT<") : len(element)-1]
+
+	var members []string
+	depth, start := 0, 0
+	for i, r := range body {
+		switch r {
+		case '<', '(':
+			depth++
+		case '>', ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				members = append(members, strings.TrimSpace(body[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(members, strings.TrimSpace(body[start:]))
+}
+
+// memberName returns the field name of a STRUCT member, unquoting `name`
+func memberName(member string) string {
+	if strings.HasPrefix(member, "`") {
+		if end := strings.Index(member[1:], "`"); end >= 0 {
+			return member[1 : end+1]
+		}
+	}
+	if i := strings.IndexByte(member, ' '); i >= 0 {
+		return member[:i]
+	}
+	return member
+}
+
+// structMemberOrder maps STRUCT member names to their positions
+func structMemberOrder(dataType string) map[string]int {
+	order := make(map[string]int)
+	for i, member := range splitStructType(dataType) {
+		order[memberName(member)] = i
+	}
+	return order
+}
+
+// structMembers maps STRUCT member names to whether they are NOT NULL
+func structMembers(dataType string) map[string]bool {
+	required := make(map[string]bool)
+	for _, member := range splitStructType(dataType) {
+		// Only the member's own type can end in NOT NULL; nested ones are inside <>
+		required[memberName(member)] = strings.HasSuffix(strings.ToUpper(member), " NOT NULL")
+	}
+	return required
+}
+
+var (
+	partitionByPattern = regexp.MustCompile(`(?m)^PARTITION BY (.+)$`)
+	clusterByPattern   = regexp.MustCompile(`(?m)^CLUSTER BY (.+)$`)
+	definitionPattern  = regexp.MustCompile(`(?s)\nAS\s+(.*)$`)
+	rangeBucketPattern = regexp.MustCompile("^RANGE_BUCKET\\(\\s*`?(\\w+)`?\\s*,\\s*GENERATE_ARRAY\\(\\s*(-?\\d+)\\s*,\\s*(-?\\d+)\\s*,\\s*(-?\\d+)\\s*\\)\\s*\\)")
+	truncPattern       = regexp.MustCompile("^\\w+_TRUNC\\(\\s*`?(\\w+)`?\\s*,\\s*(\\w+)\\s*\\)")
+	datePattern        = regexp.MustCompile("^DATE\\(\\s*`?(\\w+)`?\\s*\\)")
+	columnPattern      = regexp.MustCompile("^`?(\\w+)`?$")
+	labelPattern       = regexp.MustCompile(`STRUCT\(\s*("(?:[^"\\]|\\.)*")\s*,\s*("(?:[^"\\]|\\.)*")\s*\)`)
+	stringPattern      = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`)
+	connectionPattern  = regexp.MustCompile("WITH CONNECTION\\s+`([^`]+)`")
+	hivePattern        = regexp.MustCompile(`WITH PARTITION COLUMNS(\s*\()?`)
+)
+
+// applyDDL fills in partitioning, clustering and view definitions from the
+// CREATE statement INFORMATION_SCHEMA.TABLES reports for the table
+func applyDDL(metadata *TableMetadata, ddl string) {
+	if expr := ddlClause(partitionByPattern, ddl); expr != "" {
+		if m := rangeBucketPattern.FindStringSubmatch(expr); m != nil {
+			partitioning := &RangePartitioning{Field: m[1]}
+			partitioning.Range.Start, _ = strconv.ParseInt(m[2], 10, 64)
+			partitioning.Range.End, _ = strconv.ParseInt(m[3], 10, 64)
+			partitioning.Range.Interval, _ = strconv.ParseInt(m[4], 10, 64)
+			metadata.RangePartitioning = partitioning
+		} else if m := truncPattern.FindStringSubmatch(expr); m != nil {
+			metadata.TimePartitioning = timePartitioning(m[1], strings.ToUpper(m[2]))
+		} else if m := datePattern.FindStringSubmatch(expr); m != nil {
+			metadata.TimePartitioning = timePartitioning(m[1], "DAY")
+		} else if m := columnPattern.FindStringSubmatch(expr); m != nil {
+			metadata.TimePartitioning = timePartitioning(m[1], "DAY")
+		}
+	}
+
+	if fields := ddlClause(clusterByPattern, ddl); fields != "" {
+		clustering := &Clustering{}
+		for _, field := range strings.Split(fields, ",") {
+			clustering.Fields = append(clustering.Fields, strings.Trim(strings.TrimSpace(field), "`"))
+		}
+		metadata.Clustering = clustering
+	}
+
+	// External and BigLake tables read their data through a connection
+	if match := connectionPattern.FindStringSubmatch(ddl); match != nil {
+		if metadata.Type == "EXTERNAL" {
+			externalConfiguration(metadata).ConnectionID = match[1]
+		} else {
+			bigLakeConfiguration(metadata).ConnectionID = match[1]
+		}
+	}
+	if match := hivePattern.FindStringSubmatch(ddl); match != nil && metadata.Type == "EXTERNAL" {
+		// Partition columns listed in the DDL are a custom layout
+		mode := "AUTO"
+		if match[1] != "" {
+			mode = "CUSTOM"
+		}
+		externalConfiguration(metadata).HivePartitioningOptions = &HivePartitioningOptions{Mode: mode}
+	}
+
+	if match := definitionPattern.FindStringSubmatch(ddl); match != nil {
+		query := strings.TrimSuffix(strings.TrimSpace(match[1]), ";")
+		switch metadata.Type {
+		case "VIEW":
+			metadata.View = &ViewDefinition{Query: query}
+		case "MATERIALIZED_VIEW":
+			metadata.MaterializedView = &MaterializedViewDefinition{Query: query}
+		}
+	}
+}
+
+// ddlClause returns the argument of a single-line DDL clause, without the
+// statement terminator when the clause ends the DDL
+func ddlClause(pattern *regexp.Regexp, ddl string) string {
+	match := pattern.FindStringSubmatch(ddl)
+	if match == nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(match[1]), ";"))
+}
+
+// timePartitioning builds time partitioning on a column, treating the
+// ingestion-time pseudo columns as partitioning without a field
+func timePartitioning(column, unit string) *TimePartitioning {
+	if column == "_PARTITIONTIME" || column == "_PARTITIONDATE" {
+		column = ""
+	}
+	return &TimePartitioning{Type: unit, Field: column}
+}
+
+// applyTableOption copies a TABLE_OPTIONS entry into the metadata
+func applyTableOption(metadata *TableMetadata, option informationSchemaOption) {
+	value := strings.TrimSpace(option.OptionValue)
+	switch option.OptionName {
+	case "description":
+		metadata.Description = unquoteSQLString(value)
+	case "friendly_name":
+		metadata.FriendlyName = unquoteSQLString(value)
+	case "labels":
+		metadata.Labels = make(map[string]string)
+		for _, m := range labelPattern.FindAllStringSubmatch(value, -1) {
+			metadata.Labels[unquoteSQLString(m[1])] = unquoteSQLString(m[2])
+		}
+	case "expiration_timestamp":
+		metadata.ExpirationTime = parseTimestampLiteral(value)
+	case "kms_key_name":
+		metadata.EncryptionConfiguration = &EncryptionConfiguration{KmsKeyName: unquoteSQLString(value)}
+	case "require_partition_filter":
+		metadata.RequirePartitionFilter = strings.EqualFold(value, "true")
+	case "partition_expiration_days":
+		if days, err := strconv.ParseFloat(value, 64); err == nil && metadata.TimePartitioning != nil {
+			metadata.TimePartitioning.ExpirationMs = int64(days * float64(24*time.Hour/time.Millisecond))
+		}
+	case "format":
+		externalConfiguration(metadata).SourceFormat = strings.ToUpper(unquoteSQLString(value))
+	case "uris":
+		ext := externalConfiguration(metadata)
+		for _, uri := range stringPattern.FindAllString(value, -1) {
+			ext.SourceURIs = append(ext.SourceURIs, unquoteSQLString(uri))
+		}
+	case "hive_partition_uri_prefix":
+		hivePartitioning(metadata).SourceURIPrefix = unquoteSQLString(value)
+	case "require_hive_partition_filter":
+		hivePartitioning(metadata).RequirePartitionFilter = strings.EqualFold(value, "true")
+	case "metadata_cache_mode":
+		externalConfiguration(metadata).MetadataCacheMode = unquoteSQLString(value)
+	case "storage_uri":
+		bigLakeConfiguration(metadata).StorageURI = unquoteSQLString(value)
+	case "file_format":
+		bigLakeConfiguration(metadata).FileFormat = strings.ToUpper(unquoteSQLString(value))
+	case "table_format":
+		bigLakeConfiguration(metadata).TableFormat = strings.ToUpper(unquoteSQLString(value))
+	case "enable_refresh":
+		if metadata.MaterializedView != nil {
+			enabled := strings.EqualFold(value, "true")
+			metadata.MaterializedView.EnableRefresh = &enabled
+		}
+	case "refresh_interval_minutes":
+		if minutes, err := strconv.ParseFloat(value, 64); err == nil && metadata.MaterializedView != nil {
+			metadata.MaterializedView.RefreshIntervalMs = int64(minutes * float64(time.Minute/time.Millisecond))
+		}
+	}
+}
+
+// externalConfiguration returns the table's external data configuration,
+// creating it on first use
+func externalConfiguration(metadata *TableMetadata) *ExternalDataConfiguration {
+	if metadata.External == nil {
+		metadata.External = &ExternalDataConfiguration{}
+	}
+	return metadata.External
+}
+
+// hivePartitioning returns the external table's hive partitioning options,
+// creating them on first use. Options only come with partitioned tables.
+func hivePartitioning(metadata *TableMetadata) *HivePartitioningOptions {
+	ext := externalConfiguration(metadata)
+	if ext.HivePartitioningOptions == nil {
+		ext.HivePartitioningOptions = &HivePartitioningOptions{Mode: "AUTO"}
+	}
+	return ext.HivePartitioningOptions
+}
+
+// bigLakeConfiguration returns the table's BigLake configuration, creating
+// it on first use
+func bigLakeConfiguration(metadata *TableMetadata) *BigLakeConfiguration {
+	if metadata.BigLake == nil {
+		metadata.BigLake = &BigLakeConfiguration{}
+	}
+	return metadata.BigLake
+}
+
+// unquoteSQLString decodes a double-quoted SQL string literal, falling back
+// to stripping the quotes when it uses escapes Go doesn't know
+func unquoteSQLString(literal string) string {
+	if s, err := strconv.Unquote(literal); err == nil {
+		return s
+	}
+	return strings.Trim(literal, `"'`)
+}
+
+// parseTimestampLiteral parses a TIMESTAMP "..." literal into Unix milliseconds
+func parseTimestampLiteral(literal string) int64 {
+	value := unquoteSQLString(strings.TrimSpace(strings.TrimPrefix(literal, "TIMESTAMP")))
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07", "2006-01-02 15:04:05.999999999 MST"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UnixMilli()
+		}
+	}
+	return 0
+}
@@ -0,0 +1,195 @@
+package bigquery
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSchemaFromFieldPaths(t *testing.T) {
+	// Nested paths deliberately out of STRUCT order
+	columns := []informationSchemaColumn{
+		{FieldPath: "user", DataType: "STRUCT<id INT64 NOT NULL, `select` STRING, tags ARRAY<STRING>>", IsNullable: "YES", OrdinalPosition: 2},
+		{FieldPath: "user.tags", DataType: "ARRAY<STRING>", OrdinalPosition: 2},
+		{FieldPath: "user.select", DataType: "STRING", OrdinalPosition: 2},
+		{FieldPath: "user.id", DataType: "INT64", Description: "User ID", OrdinalPosition: 2},
+		{FieldPath: "event_id", DataType: "STRING(36)", IsNullable: "NO", OrdinalPosition: 1},
+		{FieldPath: "items", DataType: "ARRAY<STRUCT<sku STRING, price NUMERIC(10, 2)>>", IsNullable: "NO", OrdinalPosition: 3},
+		{FieldPath: "items.price", DataType: "NUMERIC(10, 2)", OrdinalPosition: 3},
+		{FieldPath: "items.sku", DataType: "STRING", OrdinalPosition: 3},
+	}
+
+	want := &Schema{Fields: []SchemaField{
+		{Name: "event_id", Type: "STRING", Mode: "REQUIRED"},
+		{Name: "user", Type: "RECORD", Mode: "NULLABLE", Fields: []SchemaField{
+			{Name: "id", Type: "INTEGER", Mode: "REQUIRED", Description: "User ID"},
+			{Name: "select", Type: "STRING", Mode: "NULLABLE"},
+			{Name: "tags", Type: "STRING", Mode: "REPEATED"},
+		}},
+		{Name: "items", Type: "RECORD", Mode: "REPEATED", Fields: []SchemaField{
+			{Name: "sku", Type: "STRING", Mode: "NULLABLE"},
+			{Name: "price", Type: "NUMERIC", Mode: "NULLABLE"},
+		}},
+	}}
+
+	if got := schemaFromFieldPaths(columns); !reflect.DeepEqual(got, want) {
+		t.Errorf("schemaFromFieldPaths() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestApplyDDL(t *testing.T) {
+	tests := []struct {
+		name      string
+		ddl       string
+		time      *TimePartitioning
+		rangeSpec string
+		cluster   []string
+	}{
+		{"date column", "CREATE TABLE `p.d.t`\n(\n  day DATE\n)\nPARTITION BY day;", &TimePartitioning{Type: "DAY", Field: "day"}, "", nil},
+		{"timestamp", "CREATE TABLE `p.d.t`\n(\n  ts TIMESTAMP\n)\nPARTITION BY DATE(ts)\nCLUSTER BY user_id, `event_id`;", &TimePartitioning{Type: "DAY", Field: "ts"}, "", []string{"user_id", "event_id"}},
+		{"hourly", "CREATE TABLE `p.d.t`\n(\n  ts TIMESTAMP\n)\nPARTITION BY TIMESTAMP_TRUNC(ts, HOUR);", &TimePartitioning{Type: "HOUR", Field: "ts"}, "", nil},
+		{"ingestion time", "CREATE TABLE `p.d.t`\n(\n  x INT64\n)\nPARTITION BY _PARTITIONDATE;", &TimePartitioning{Type: "DAY"}, "", nil},
+		{"integer range", "CREATE TABLE `p.d.t`\n(\n  id INT64\n)\nPARTITION BY RANGE_BUCKET(id, GENERATE_ARRAY(0, 100, 10));", nil, "id 0-100/10", nil},
+		{"unpartitioned", "CREATE TABLE `p.d.t`\n(\n  x INT64\n);", nil, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := &TableMetadata{}
+			applyDDL(metadata, tt.ddl)
+			if !reflect.DeepEqual(metadata.TimePartitioning, tt.time) {
+				t.Errorf("TimePartitioning = %+v, want %+v", metadata.TimePartitioning, tt.time)
+			}
+			rangeSpec := ""
+			if rp := metadata.RangePartitioning; rp != nil {
+				rangeSpec = fmt.Sprintf("%s %d-%d/%d", rp.Field, rp.Range.Start, rp.Range.End, rp.Range.Interval)
+			}
+			if rangeSpec != tt.rangeSpec {
+				t.Errorf("RangePartitioning = %q, want %q", rangeSpec, tt.rangeSpec)
+			}
+			var cluster []string
+			if metadata.Clustering != nil {
+				cluster = metadata.Clustering.Fields
+			}
+			if !reflect.DeepEqual(cluster, tt.cluster) {
+				t.Errorf("Clustering = %v, want %v", cluster, tt.cluster)
+			}
+		})
+	}
+}
+
+func TestInformationSchemaRowMetadata(t *testing.T) {
+	// As printed by bq query --format=json; views have no __TABLES__ stats
+	data := `[
+		{
+			"table_name": "events", "table_type": "BASE TABLE", "creation_time": "1733047800000",
+			"last_modified_time": "1733236200000", "row_count": "42", "size_bytes": "1024",
+			"ddl": "CREATE TABLE ` + "`demo-project.analytics.events`" + `\n(\n  event_timestamp TIMESTAMP\n)\nPARTITION BY DATE(event_timestamp)\nOPTIONS(\n  require_partition_filter=true\n);",
+			"columns": "[{\"field_path\":\"event_timestamp\",\"data_type\":\"TIMESTAMP\",\"description\":null,\"is_nullable\":\"NO\",\"ordinal_position\":1}]",
+			"options": "[{\"option_name\":\"description\",\"option_value\":\"\\\"Raw \\\\\\\"events\\\\\\\"\\\"\"},{\"option_name\":\"labels\",\"option_value\":\"[STRUCT(\\\"team\\\", \\\"growth\\\"), STRUCT(\\\"pii\\\", \\\"true\\\")]\"},{\"option_name\":\"require_partition_filter\",\"option_value\":\"true\"},{\"option_name\":\"partition_expiration_days\",\"option_value\":\"365.0\"},{\"option_name\":\"expiration_timestamp\",\"option_value\":\"TIMESTAMP \\\"2025-01-01T00:00:00.000Z\\\"\"},{\"option_name\":\"kms_key_name\",\"option_value\":\"\\\"projects/p/locations/us/keyRings/r/cryptoKeys/k\\\"\"}]"
+		},
+		{
+			"table_name": "recent", "table_type": "MATERIALIZED VIEW", "creation_time": "1733150520000",
+			"last_modified_time": null, "row_count": null, "size_bytes": null,
+			"ddl": "CREATE MATERIALIZED VIEW ` + "`demo-project.analytics.recent`" + `\nOPTIONS(\n  enable_refresh=false\n)\nAS SELECT event_timestamp\nFROM events;",
+			"columns": null,
+			"options": "[{\"option_name\":\"enable_refresh\",\"option_value\":\"false\"},{\"option_name\":\"refresh_interval_minutes\",\"option_value\":\"60.0\"}]"
+		}
+	]`
+
+	var rows []informationSchemaRow
+	if err := json.Unmarshal([]byte(data), &rows); err != nil {
+		t.Fatalf("Failed to decode rows: %v", err)
+	}
+	tables, err := tableMetadataFromRows("demo-project", "analytics", rows)
+	if err != nil {
+		t.Fatalf("tableMetadataFromRows returned error: %v", err)
+	}
+	if len(tables) != 2 {
+		t.Fatalf("Expected 2 tables, got %d", len(tables))
+	}
+
+	events := tables[0]
+	if !events.Partial {
+		t.Error("Expected metadata rebuilt from INFORMATION_SCHEMA to be marked partial")
+	}
+	if events.TableReference.TableID != "events" || events.Type != "TABLE" || events.NumRows != 42 || events.NumBytes != 1024 {
+		t.Errorf("Unexpected table info: %+v", events.TableInfo)
+	}
+	if events.Description != `Raw "events"` || events.Labels["team"] != "growth" || events.Labels["pii"] != "true" {
+		t.Errorf("Unexpected description or labels: %q %v", events.Description, events.Labels)
+	}
+	if !events.PartitionFilterRequired() || events.TimePartitioning == nil || events.TimePartitioning.ExpirationMs != 31536000000 {
+		t.Errorf("Unexpected partitioning: %+v", events.TimePartitioning)
+	}
+	if events.ExpirationTime != 1735689600000 || events.EncryptionConfiguration == nil {
+		t.Errorf("Unexpected expiration or encryption: %d %+v", events.ExpirationTime, events.EncryptionConfiguration)
+	}
+	if events.Schema == nil || len(events.Schema.Fields) != 1 || events.Schema.Fields[0].Mode != "REQUIRED" {
+		t.Errorf("Unexpected schema: %+v", events.Schema)
+	}
+
+	mv := tables[1]
+	if mv.Type != "MATERIALIZED_VIEW" || mv.NumRows != 0 || mv.MaterializedView == nil {
+		t.Fatalf("Unexpected materialized view: %+v", mv)
+	}
+	if mv.DefinitionQuery() != "SELECT event_timestamp\nFROM events" {
+		t.Errorf("Unexpected query: %q", mv.DefinitionQuery())
+	}
+	if mv.MaterializedView.RefreshEnabled() || mv.MaterializedView.RefreshIntervalMs != 3600000 {
+		t.Errorf("Unexpected refresh settings: %+v", mv.MaterializedView)
+	}
+}
+
+func TestInformationSchemaExternalRows(t *testing.T) {
+	data := `[
+		{
+			"table_name": "raw_events", "table_type": "EXTERNAL", "creation_time": "1733047800000",
+			"ddl": "CREATE EXTERNAL TABLE ` + "`p.lake.raw_events`" + `\nWITH PARTITION COLUMNS\nWITH CONNECTION ` + "`p.us.lake`" + `\nOPTIONS(\n  format=\"PARQUET\",\n  uris=[\"gs://lake/events/*\"]\n);",
+			"options": "[{\"option_name\":\"format\",\"option_value\":\"\\\"PARQUET\\\"\"},{\"option_name\":\"uris\",\"option_value\":\"[\\\"gs://lake/events/*\\\", \\\"gs://lake/late/*\\\"]\"},{\"option_name\":\"hive_partition_uri_prefix\",\"option_value\":\"\\\"gs://lake/events\\\"\"},{\"option_name\":\"require_hive_partition_filter\",\"option_value\":\"true\"}]"
+		},
+		{
+			"table_name": "orders", "table_type": "BASE TABLE", "creation_time": "1733047800000",
+			"ddl": "CREATE TABLE ` + "`p.lake.orders`" + `\n(\n  id INT64\n)\nWITH CONNECTION ` + "`p.us.lake`" + `\nOPTIONS(\n  file_format=\"PARQUET\",\n  table_format=\"ICEBERG\",\n  storage_uri=\"gs://lake/orders\"\n);",
+			"options": "[{\"option_name\":\"file_format\",\"option_value\":\"\\\"PARQUET\\\"\"},{\"option_name\":\"table_format\",\"option_value\":\"\\\"ICEBERG\\\"\"},{\"option_name\":\"storage_uri\",\"option_value\":\"\\\"gs://lake/orders\\\"\"}]"
+		}
+	]`
+
+	var rows []informationSchemaRow
+	if err := json.Unmarshal([]byte(data), &rows); err != nil {
+		t.Fatalf("Failed to decode rows: %v", err)
+	}
+	tables, err := tableMetadataFromRows("p", "lake", rows)
+	if err != nil {
+		t.Fatalf("tableMetadataFromRows returned error: %v", err)
+	}
+
+	ext := tables[0].External
+	if ext == nil || ext.SourceFormat != "PARQUET" || ext.ConnectionID != "p.us.lake" || len(ext.SourceURIs) != 2 || ext.SourceURIs[1] != "gs://lake/late/*" {
+		t.Fatalf("Unexpected external configuration: %+v", ext)
+	}
+	if hive := ext.HivePartitioningOptions; hive == nil || hive.Mode != "AUTO" || hive.SourceURIPrefix != "gs://lake/events" || !hive.RequirePartitionFilter {
+		t.Errorf("Unexpected hive partitioning: %+v", hive)
+	}
+	if tables[0].DisplayType() != "BIGLAKE" {
+		t.Errorf("Expected a BigLake external table, got %s", tables[0].DisplayType())
+	}
+
+	bl := tables[1].BigLake
+	if bl == nil || bl.StorageURI != "gs://lake/orders" || bl.TableFormat != "ICEBERG" || bl.FileFormat != "PARQUET" || bl.ConnectionID != "p.us.lake" {
+		t.Errorf("Unexpected BigLake configuration: %+v", bl)
+	}
+	if tables[1].External != nil || tables[1].Type != "TABLE" || tables[1].DisplayType() != "BIGLAKE" {
+		t.Errorf("Unexpected BigLake managed table: %+v", tables[1])
+	}
+}
+
+func TestSQLQuoting(t *testing.T) {
+	if got, want := sqlString(`it's C:\tmp`+"\n"), `'it\'s C:\\tmp\n'`; got != want {
+		t.Errorf("sqlString() = %s, want %s", got, want)
+	}
+	if got, want := sqlIdent("my-project", "odd`name\\", "INFORMATION_SCHEMA.TABLES"), "`my-project.odd\\`name\\\\.INFORMATION_SCHEMA.TABLES`"; got != want {
+		t.Errorf("sqlIdent() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,180 @@
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"bqs/internal/cache"
+	"bqs/internal/config"
+	"bqs/internal/errors"
+	"bqs/internal/retry"
+)
+
+// TableJob is a job that read or wrote a table, as a row of
+// INFORMATION_SCHEMA.JOBS_BY_PROJECT in the form bq query --format=json prints
+type TableJob struct {
+	JobID               string `json:"job_id"`
+	User                string `json:"user_email"`
+	JobType             string `json:"job_type"`             // QUERY, LOAD, COPY or EXTRACT
+	StatementType       string `json:"statement_type"`       // SELECT, INSERT, MERGE, ...; empty for other job types
+	CreationTime        int64  `json:"creation_time,string"` // Unix milliseconds
+	TotalBytesProcessed int64  `json:"total_bytes_processed,string"`
+	State               string `json:"state"` // PENDING, RUNNING or DONE
+	Failed              bool   `json:"failed,string"`
+	Wrote               bool   `json:"wrote,string"` // The table was the job's destination
+}
+
+// Operation describes what a job did to the table, e.g. "MERGE" or "LOAD",
+// falling back to the job type
+func (j TableJob) Operation() string {
+	if j.StatementType != "" {
+		return j.StatementType
+	}
+	return j.JobType
+}
+
+// tableJobsQuery lists the jobs of the last days that referenced or wrote a
+// table, newest first. JOBS_BY_PROJECT only exists as a regional view, and
+// only holds the jobs run in the table's own project.
+func tableJobsQuery(project, location, dataset, table string, days int) string {
+	isTable := func(column string) string {
+		return fmt.Sprintf("%s.project_id = %s AND %s.dataset_id = %s AND %s.table_id = %s",
+			column, sqlString(project), column, sqlString(dataset), column, sqlString(table))
+	}
+	return fmt.Sprintf(`SELECT
+  job_id,
+  user_email,
+  job_type,
+  statement_type,
+  UNIX_MILLIS(creation_time) AS creation_time,
+  total_bytes_processed,
+  state,
+  error_result IS NOT NULL AS failed,
+  IFNULL(%s, FALSE) AS wrote
+FROM %s
+WHERE creation_time >= TIMESTAMP_SUB(CURRENT_TIMESTAMP(), INTERVAL %d DAY)
+  AND (EXISTS (SELECT 1 FROM UNNEST(referenced_tables) AS r WHERE %s) OR IFNULL(%s, FALSE))
+ORDER BY creation_time DESC
+LIMIT %d`,
+		isTable("destination_table"), sqlIdent(project, "region-"+location, "INFORMATION_SCHEMA.JOBS_BY_PROJECT"), days,
+		isTable("r"), isTable("destination_table"), config.JobHistoryLimit)
+}
+
+// TableJobs retrieves the jobs of the last days that read or wrote a table,
+// newest first, with caching and retry logic. Listing other users' jobs needs
+// bigquery.jobs.listAll on the project; without it the error is an
+// ErrorTypePermission BQSError.
+func (c *Client) TableJobs(ctx context.Context, project, dataset, table string, days int) ([]TableJob, error) {
+	cacheKey := cache.TableJobsKey(project, dataset, table, days)
+
+	// Try cache first
+	if entry, err := c.cache.Get(cacheKey); err == nil {
+		var jobs []TableJob
+		if err := json.Unmarshal([]byte(entry.Data), &jobs); err == nil {
+			return jobs, nil
+		}
+	}
+
+	// Cache miss, fetch from BigQuery with retry
+	return fetchShared(ctx, c, cacheKey, config.JobHistoryTTL, "table jobs", func() ([]TableJob, error) {
+		// The jobs view is regional, so the dataset's location is needed first
+		location, err := c.datasetLocation(ctx, project, dataset)
+		if err != nil {
+			return nil, err
+		}
+
+		var jobs []TableJob
+		err = retry.WithDefaultRetry(ctx, "list table jobs", func() error {
+			var fetchErr error
+			jobs, fetchErr = c.backend.ListTableJobs(ctx, project, location, dataset, table, days)
+			if fetchErr != nil {
+				return errors.WrapBigQueryError(fetchErr, "list_jobs", project, dataset, table)
+			}
+			return nil
+		})
+		return jobs, err
+	})
+}
+
+// datasetLocation looks up a dataset's location in lower case, the form the
+// regional INFORMATION_SCHEMA views are named with
+func (c *Client) datasetLocation(ctx context.Context, project, dataset string) (string, error) {
+	metadata, err := c.GetDatasetMetadata(ctx, project, dataset)
+	if err != nil {
+		return "", err
+	}
+	if metadata.Location == "" {
+		return "", fmt.Errorf("location of dataset %s.%s is unknown", project, dataset)
+	}
+	return strings.ToLower(metadata.Location), nil
+}
+
+// TableUsage summarizes the jobs that read or wrote a table
+type TableUsage struct {
+	Reads          int
+	Writes         int
+	Failed         int
+	BytesProcessed int64
+	LastRead       int64       // Unix milliseconds, 0 if never read
+	LastWrite      int64       // Unix milliseconds, 0 if never written
+	Users          []UserUsage // Busiest first
+	Truncated      bool        // The job history hit config.JobHistoryLimit
+}
+
+// UserUsage counts one user's reads and writes of a table
+type UserUsage struct {
+	User    string
+	Reads   int
+	Writes  int
+	LastJob int64 // Unix milliseconds
+}
+
+// SummarizeTableJobs counts reads and writes of a table overall and per user.
+// A job writing the table counts as a write even if it read it too.
+func SummarizeTableJobs(jobs []TableJob) TableUsage {
+	usage := TableUsage{Truncated: len(jobs) >= config.JobHistoryLimit}
+	users := make(map[string]*UserUsage)
+	for _, job := range jobs {
+		user, ok := users[job.User]
+		if !ok {
+			user = &UserUsage{User: job.User}
+			users[job.User] = user
+		}
+		if job.CreationTime > user.LastJob {
+			user.LastJob = job.CreationTime
+		}
+		if job.Failed {
+			usage.Failed++
+		}
+		usage.BytesProcessed += job.TotalBytesProcessed
+
+		if job.Wrote {
+			usage.Writes++
+			user.Writes++
+			if job.CreationTime > usage.LastWrite {
+				usage.LastWrite = job.CreationTime
+			}
+		} else {
+			usage.Reads++
+			user.Reads++
+			if job.CreationTime > usage.LastRead {
+				usage.LastRead = job.CreationTime
+			}
+		}
+	}
+
+	for _, user := range users {
+		usage.Users = append(usage.Users, *user)
+	}
+	sort.Slice(usage.Users, func(i, j int) bool {
+		a, b := usage.Users[i], usage.Users[j]
+		if a.Reads+a.Writes != b.Reads+b.Writes {
+			return a.Reads+a.Writes > b.Reads+b.Writes
+		}
+		return a.User < b.User
+	})
+	return usage
+}
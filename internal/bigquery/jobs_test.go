@@ -0,0 +1,101 @@
+package bigquery
+
+import (
+	"context"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bqs/internal/cache"
+	"bqs/internal/errors"
+)
+
+func TestSummarizeTableJobs(t *testing.T) {
+	jobs := []TableJob{
+		{User: "a@x", CreationTime: 400, TotalBytesProcessed: 10},
+		{User: "etl@x", CreationTime: 300, TotalBytesProcessed: 20, Wrote: true},
+		{User: "a@x", CreationTime: 200, Failed: true},
+		{User: "b@x", CreationTime: 100, TotalBytesProcessed: 5},
+		{User: "etl@x", CreationTime: 50, Wrote: true},
+	}
+	usage := SummarizeTableJobs(jobs)
+	if usage.Reads != 3 || usage.Writes != 2 || usage.Failed != 1 || usage.BytesProcessed != 35 {
+		t.Errorf("Unexpected counts: %+v", usage)
+	}
+	if usage.LastRead != 400 || usage.LastWrite != 300 || usage.Truncated {
+		t.Errorf("Unexpected last jobs: %+v", usage)
+	}
+
+	want := []UserUsage{
+		{User: "a@x", Reads: 2, LastJob: 400},
+		{User: "etl@x", Writes: 2, LastJob: 300},
+		{User: "b@x", Reads: 1, LastJob: 100},
+	}
+	if len(usage.Users) != len(want) {
+		t.Fatalf("Users = %+v, want %+v", usage.Users, want)
+	}
+	for i := range want {
+		if usage.Users[i] != want[i] {
+			t.Errorf("Users[%d] = %+v, want %+v", i, usage.Users[i], want[i])
+		}
+	}
+}
+
+func TestClientTableJobs(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	metadata := &TableMetadata{}
+	metadata.Type = "TABLE"
+	metadata.TableReference = TableReference{ProjectID: "p", DatasetID: "raw", TableID: "events"}
+	writeTableFixture(t, root, metadata)
+	if err := os.WriteFile(filepath.Join(root, "p", "raw.json"), []byte(`{"location": "EU"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	jobsDir := filepath.Join(root, "p", "raw", "_jobs")
+	if err := os.MkdirAll(jobsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `[{"job_id": "j1", "user_email": "a@x", "job_type": "QUERY", "statement_type": "MERGE",
+		"creation_time": "1759363200000", "total_bytes_processed": "100", "state": "DONE", "failed": "false", "wrote": "true"}]`
+	jobsFile := filepath.Join(jobsDir, "events.json")
+	if err := os.WriteFile(jobsFile, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClientWithBackend(cache.NewMockService(), NewFixtureBackend(root))
+	jobs, err := client.TableJobs(ctx, "p", "raw", "events", 30)
+	if err != nil {
+		t.Fatalf("TableJobs returned error: %v", err)
+	}
+	want := TableJob{JobID: "j1", User: "a@x", JobType: "QUERY", StatementType: "MERGE",
+		CreationTime: 1759363200000, TotalBytesProcessed: 100, State: "DONE", Wrote: true}
+	if len(jobs) != 1 || jobs[0] != want {
+		t.Errorf("TableJobs() = %+v, want [%+v]", jobs, want)
+	}
+
+	// Jobs are served from the cache
+	if err := os.Remove(jobsFile); err != nil {
+		t.Fatal(err)
+	}
+	if again, err := client.TableJobs(ctx, "p", "raw", "events", 30); err != nil || len(again) != 1 {
+		t.Errorf("Expected the cached jobs, got %v, %v", again, err)
+	}
+
+	if _, err := client.TableJobs(ctx, "p", "raw", "missing", 30); err == nil {
+		t.Error("Expected an error for a missing table")
+	}
+}
+
+func TestTableJobsPermissionError(t *testing.T) {
+	err := errors.WrapBigQueryError(&errors.APIError{StatusCode: 403, Message: "Access Denied"}, "list_jobs", "p", "raw", "events")
+	var bqsErr *errors.BQSError
+	if !stderrors.As(err, &bqsErr) || bqsErr.Type != errors.ErrorTypePermission {
+		t.Fatalf("Expected a permission error, got %v", err)
+	}
+	if !strings.Contains(bqsErr.UserFriendlyMessage(), "bigquery.jobs.listAll") {
+		t.Errorf("Expected the message to name the missing permission, got %q", bqsErr.UserFriendlyMessage())
+	}
+}
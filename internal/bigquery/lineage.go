@@ -0,0 +1,229 @@
+package bigquery
+
+import (
+	"context"
+	"sort"
+)
+
+// LineageDirection selects which edges a lineage walk follows
+type LineageDirection int
+
+const (
+	LineageUpstream   LineageDirection = iota // From a view to the tables it reads
+	LineageDownstream                         // From a table to the views reading it
+)
+
+// LineageGraph is a table-level dependency graph built from the definitions
+// of views and materialized views. Tables are keyed by project.dataset.table.
+type LineageGraph struct {
+	upstream   map[string]map[string]bool
+	downstream map[string]map[string]bool
+	refs       map[string]TableReference
+	types      map[string]string
+	resolved   map[string]bool // Tables whose own definition has been read
+	scanned    map[string]bool // project.dataset whose views have all been added
+}
+
+// NewLineageGraph creates an empty lineage graph
+func NewLineageGraph() *LineageGraph {
+	return &LineageGraph{
+		upstream:   make(map[string]map[string]bool),
+		downstream: make(map[string]map[string]bool),
+		refs:       make(map[string]TableReference),
+		types:      make(map[string]string),
+		resolved:   make(map[string]bool),
+		scanned:    make(map[string]bool),
+	}
+}
+
+// AddTable records a table's type and, for views and materialized views, an
+// edge to every table its query reads
+func (g *LineageGraph) AddTable(metadata *TableMetadata) {
+	ref := metadata.TableReference
+	id := g.add(ref)
+	g.types[id] = metadata.Type
+	g.resolved[id] = true
+
+	for _, source := range ExtractTableReferences(metadata.DefinitionQuery(), ref.ProjectID) {
+		sourceID := g.add(source)
+		if sourceID == id {
+			continue
+		}
+		if g.upstream[id] == nil {
+			g.upstream[id] = make(map[string]bool)
+		}
+		if g.downstream[sourceID] == nil {
+			g.downstream[sourceID] = make(map[string]bool)
+		}
+		g.upstream[id][sourceID] = true
+		g.downstream[sourceID][id] = true
+	}
+}
+
+// add registers a table reference and returns its key
+func (g *LineageGraph) add(ref TableReference) string {
+	id := ref.String()
+	g.refs[id] = ref
+	return id
+}
+
+// Type returns the table type (TABLE, VIEW, ...) or "" if it isn't known
+func (g *LineageGraph) Type(ref TableReference) string {
+	return g.types[ref.String()]
+}
+
+// Upstream returns the tables a view reads, sorted
+func (g *LineageGraph) Upstream(ref TableReference) []TableReference {
+	return g.sorted(g.upstream[ref.String()])
+}
+
+// Downstream returns the known views reading a table, sorted
+func (g *LineageGraph) Downstream(ref TableReference) []TableReference {
+	return g.sorted(g.downstream[ref.String()])
+}
+
+// Neighbours returns the upstream or downstream tables of ref
+func (g *LineageGraph) Neighbours(ref TableReference, direction LineageDirection) []TableReference {
+	if direction == LineageUpstream {
+		return g.Upstream(ref)
+	}
+	return g.Downstream(ref)
+}
+
+func (g *LineageGraph) sorted(ids map[string]bool) []TableReference {
+	refs := make([]TableReference, 0, len(ids))
+	for id := range ids {
+		refs = append(refs, g.refs[id])
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].String() < refs[j].String() })
+	return refs
+}
+
+// LineageNode is one table in a lineage tree. Repeated marks a table already
+// expanded elsewhere in the tree, which also breaks cycles.
+type LineageNode struct {
+	Ref      TableReference
+	Type     string
+	Children []*LineageNode
+	Repeated bool
+}
+
+// Tree walks the graph from root in one direction, at most depth levels deep
+func (g *LineageGraph) Tree(root TableReference, direction LineageDirection, depth int) *LineageNode {
+	expanded := map[string]bool{}
+	var walk func(ref TableReference, level int) *LineageNode
+	walk = func(ref TableReference, level int) *LineageNode {
+		node := &LineageNode{Ref: ref, Type: g.Type(ref)}
+		if expanded[ref.String()] {
+			node.Repeated = true
+			return node
+		}
+		expanded[ref.String()] = true
+		if level < depth {
+			for _, next := range g.Neighbours(ref, direction) {
+				node.Children = append(node.Children, walk(next, level+1))
+			}
+		}
+		return node
+	}
+	return walk(root, 0)
+}
+
+// ScanDatasetLineage adds every view and materialized view of a dataset to
+// the graph, reading definitions from cached metadata where available
+func (c *Client) ScanDatasetLineage(ctx context.Context, graph *LineageGraph, project, dataset string) error {
+	key := project + "." + dataset
+	if graph.scanned[key] {
+		return nil
+	}
+
+	tables, err := c.ListTables(ctx, project, dataset)
+	if err != nil {
+		return err
+	}
+
+	// One bulk query beats a tables.get per view; on failure fall back to those
+	uncached := 0
+	for _, table := range tables {
+		if isLineageSource(table.Type) && !c.IsTableMetadataCached(project, dataset, table.TableID) {
+			uncached++
+		}
+	}
+	if uncached > 1 {
+		_, _, _ = c.WarmDatasetCache(ctx, project, dataset)
+	}
+
+	for _, table := range tables {
+		ref := TableReference{ProjectID: project, DatasetID: dataset, TableID: table.TableID}
+		graph.types[graph.add(ref)] = table.Type
+		if !isLineageSource(table.Type) {
+			continue
+		}
+		if err := c.resolveLineage(ctx, graph, ref); err != nil {
+			return err
+		}
+	}
+
+	graph.scanned[key] = true
+	return nil
+}
+
+// TraceLineage fills in the graph for a walk of depth levels from root.
+// Upstream walks read the definition of each view reached. Downstream walks
+// scan the dataset of each table reached, so views in other datasets are
+// only found when their dataset has been scanned too. Tables that can't be
+// read are left as leaves; only failures on root's dataset are returned.
+func (c *Client) TraceLineage(ctx context.Context, graph *LineageGraph, root TableReference, direction LineageDirection, depth int) error {
+	if err := c.ScanDatasetLineage(ctx, graph, root.ProjectID, root.DatasetID); err != nil {
+		return err
+	}
+	if err := c.resolveLineage(ctx, graph, root); err != nil {
+		return err
+	}
+
+	frontier := []TableReference{root}
+	visited := map[string]bool{root.String(): true}
+	for level := 0; level < depth && len(frontier) > 0; level++ {
+		var next []TableReference
+		for _, ref := range frontier {
+			for _, neighbour := range graph.Neighbours(ref, direction) {
+				if visited[neighbour.String()] {
+					continue
+				}
+				visited[neighbour.String()] = true
+				next = append(next, neighbour)
+
+				if direction == LineageUpstream {
+					_ = c.resolveLineage(ctx, graph, neighbour)
+				} else {
+					_ = c.ScanDatasetLineage(ctx, graph, neighbour.ProjectID, neighbour.DatasetID)
+				}
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+			}
+		}
+		frontier = next
+	}
+
+	return nil
+}
+
+// isLineageSource reports whether a table type has a defining query
+func isLineageSource(tableType string) bool {
+	return tableType == "VIEW" || tableType == "MATERIALIZED_VIEW"
+}
+
+// resolveLineage reads one table's metadata into the graph unless it has been already
+func (c *Client) resolveLineage(ctx context.Context, graph *LineageGraph, ref TableReference) error {
+	if graph.resolved[ref.String()] {
+		return nil
+	}
+	metadata, err := c.GetTableMetadata(ctx, ref.ProjectID, ref.DatasetID, ref.TableID)
+	if err != nil {
+		return err
+	}
+	metadata.TableReference = ref
+	graph.AddTable(metadata)
+	return nil
+}
@@ -0,0 +1,196 @@
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"bqs/internal/cache"
+)
+
+func refStrings(refs []TableReference) []string {
+	out := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		out = append(out, ref.String())
+	}
+	return out
+}
+
+func TestExtractTableReferences(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{
+			name: "backtick path",
+			sql:  "SELECT * FROM `demo-project.analytics.events`",
+			want: []string{"demo-project.analytics.events"},
+		},
+		{
+			name: "dataset only uses default project",
+			sql:  "SELECT * FROM analytics.events e JOIN raw.users AS u ON e.user_id = u.id",
+			want: []string{"p.analytics.events", "p.raw.users"},
+		},
+		{
+			name: "unquoted dashed project and split backticks",
+			sql:  "SELECT * FROM my-project-1.raw.users, `other`.`raw`.`orders` o",
+			want: []string{"my-project-1.raw.users", "other.raw.orders"},
+		},
+		{
+			name: "ctes and subqueries",
+			sql: `WITH recent AS (SELECT * FROM raw.events WHERE ts > '2024-01-01')
+				SELECT * FROM recent r JOIN (SELECT id FROM raw.users) u ON r.user_id = u.id`,
+			want: []string{"p.raw.events", "p.raw.users"},
+		},
+		{
+			name: "unnest, extract and table functions are skipped",
+			sql: `SELECT EXTRACT(DAY FROM ts), x FROM raw.events, UNNEST(items) AS x
+				CROSS JOIN ML.PREDICT(MODEL m.model, TABLE raw.features)
+				WHERE a IS DISTINCT FROM b`,
+			want: []string{"p.raw.events"},
+		},
+		{
+			name: "information schema and comments",
+			sql: `-- FROM raw.commented
+				SELECT * FROM raw.INFORMATION_SCHEMA.TABLES
+				UNION ALL SELECT * FROM raw.tables_*`,
+			want: []string{"p.raw.tables_*"},
+		},
+		{
+			name: "duplicates collapse",
+			sql:  "SELECT * FROM raw.a JOIN raw.a b USING (id) FOR SYSTEM_TIME AS OF ts",
+			want: []string{"p.raw.a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := refStrings(ExtractTableReferences(tt.sql, "p"))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractTableReferences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func viewMetadata(project, dataset, table, query string) *TableMetadata {
+	metadata := &TableMetadata{View: &ViewDefinition{Query: query}}
+	metadata.Type = "VIEW"
+	metadata.TableReference = TableReference{ProjectID: project, DatasetID: dataset, TableID: table}
+	return metadata
+}
+
+func TestLineageGraphTree(t *testing.T) {
+	graph := NewLineageGraph()
+	graph.AddTable(viewMetadata("p", "d", "a", "SELECT * FROM d.b JOIN d.c USING (id)"))
+	graph.AddTable(viewMetadata("p", "d", "b", "SELECT * FROM d.c"))
+	// A cycle must not loop forever
+	graph.AddTable(viewMetadata("p", "d", "c", "SELECT * FROM d.a"))
+
+	root := TableReference{ProjectID: "p", DatasetID: "d", TableID: "a"}
+	tree := graph.Tree(root, LineageUpstream, 5)
+	if len(tree.Children) != 2 || tree.Children[0].Ref.TableID != "b" || tree.Children[1].Ref.TableID != "c" {
+		t.Fatalf("Unexpected upstream children: %+v", tree.Children)
+	}
+	if c := tree.Children[0].Children[0]; c.Ref.TableID != "c" || c.Children[0].Ref.TableID != "a" || !c.Children[0].Repeated {
+		t.Errorf("Expected the cycle back to a to be marked repeated, got %+v", c)
+	}
+	if !tree.Children[1].Repeated {
+		t.Error("Expected c to be expanded only once")
+	}
+
+	if tree := graph.Tree(root, LineageUpstream, 1); len(tree.Children[0].Children) != 0 {
+		t.Error("Expected depth 1 to stop after direct parents")
+	}
+
+	down := refStrings(graph.Downstream(TableReference{ProjectID: "p", DatasetID: "d", TableID: "c"}))
+	if want := []string{"p.d.a", "p.d.b"}; !reflect.DeepEqual(down, want) {
+		t.Errorf("Downstream() = %v, want %v", down, want)
+	}
+}
+
+func TestClientTraceLineage(t *testing.T) {
+	ctx := context.Background()
+	client := NewClientWithBackend(cache.NewMockService(), NewFixtureBackend(fixtureDir))
+	events := TableReference{ProjectID: "demo-project", DatasetID: "analytics", TableID: "events"}
+	dailyUsers := TableReference{ProjectID: "demo-project", DatasetID: "analytics", TableID: "daily_users"}
+
+	graph := NewLineageGraph()
+	if err := client.TraceLineage(ctx, graph, events, LineageDownstream, 3); err != nil {
+		t.Fatalf("TraceLineage returned error: %v", err)
+	}
+	tree := graph.Tree(events, LineageDownstream, 3)
+	if tree.Type != "TABLE" || len(tree.Children) != 1 || tree.Children[0].Ref != dailyUsers || tree.Children[0].Type != "VIEW" {
+		t.Errorf("Unexpected downstream tree: %+v", tree)
+	}
+
+	graph = NewLineageGraph()
+	if err := client.TraceLineage(ctx, graph, dailyUsers, LineageUpstream, 3); err != nil {
+		t.Fatalf("TraceLineage returned error: %v", err)
+	}
+	if up := graph.Upstream(dailyUsers); len(up) != 1 || up[0] != events {
+		t.Errorf("Upstream() = %v, want [%v]", up, events)
+	}
+
+	missing := TableReference{ProjectID: "demo-project", DatasetID: "analytics", TableID: "missing"}
+	if err := client.TraceLineage(ctx, NewLineageGraph(), missing, LineageUpstream, 3); err == nil {
+		t.Error("Expected an error for a missing root table")
+	}
+}
+
+func TestClientTraceLineageAcrossDatasets(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	write := func(dataset string, metadata *TableMetadata) {
+		dir := filepath.Join(root, "p", dataset)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(metadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, metadata.TableReference.TableID+".json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	raw := &TableMetadata{}
+	raw.Type = "TABLE"
+	raw.TableReference = TableReference{ProjectID: "p", DatasetID: "raw", TableID: "events"}
+	write("raw", raw)
+	write("raw", viewMetadata("p", "raw", "clean", "SELECT * FROM raw.events"))
+	write("mart", viewMetadata("p", "mart", "daily", "SELECT * FROM `p.raw.clean`"))
+
+	client := NewClientWithBackend(cache.NewMockService(), NewFixtureBackend(root))
+
+	// Walking up from mart reads raw definitions without scanning raw
+	graph := NewLineageGraph()
+	daily := TableReference{ProjectID: "p", DatasetID: "mart", TableID: "daily"}
+	if err := client.TraceLineage(ctx, graph, daily, LineageUpstream, 3); err != nil {
+		t.Fatalf("TraceLineage returned error: %v", err)
+	}
+	tree := graph.Tree(daily, LineageUpstream, 3)
+	if len(tree.Children) != 1 || len(tree.Children[0].Children) != 1 || tree.Children[0].Children[0].Ref != raw.TableReference {
+		t.Errorf("Unexpected upstream tree: %+v", tree)
+	}
+
+	// Views in other datasets are only found once their dataset is scanned
+	graph = NewLineageGraph()
+	if err := client.TraceLineage(ctx, graph, raw.TableReference, LineageDownstream, 3); err != nil {
+		t.Fatalf("TraceLineage returned error: %v", err)
+	}
+	if got := refStrings(graph.Downstream(TableReference{ProjectID: "p", DatasetID: "raw", TableID: "clean"})); len(got) != 0 {
+		t.Errorf("Expected no downstream views before scanning mart, got %v", got)
+	}
+	if err := client.ScanDatasetLineage(ctx, graph, "p", "mart"); err != nil {
+		t.Fatalf("ScanDatasetLineage returned error: %v", err)
+	}
+	tree = graph.Tree(raw.TableReference, LineageDownstream, 3)
+	if len(tree.Children) != 1 || len(tree.Children[0].Children) != 1 || tree.Children[0].Children[0].Ref != daily {
+		t.Errorf("Unexpected downstream tree after scanning mart: %+v", tree)
+	}
+}
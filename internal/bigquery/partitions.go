@@ -0,0 +1,163 @@
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"bqs/internal/cache"
+	"bqs/internal/config"
+	"bqs/internal/errors"
+	"bqs/internal/retry"
+)
+
+// PartitionInfo is one partition of a table as INFORMATION_SCHEMA.PARTITIONS
+// reports it, in the row form bq query --format=json prints
+type PartitionInfo struct {
+	PartitionID        string `json:"partition_id"` // e.g. 20261001, __NULL__; empty for unpartitioned tables
+	TotalRows          int64  `json:"total_rows,string"`
+	TotalLogicalBytes  int64  `json:"total_logical_bytes,string"`
+	TotalBillableBytes int64  `json:"total_billable_bytes,string"`
+	LastModifiedTime   int64  `json:"last_modified_time,string"` // Unix milliseconds
+	StorageTier        string `json:"storage_tier"`              // ACTIVE or LONG_TERM
+}
+
+// partitionLayouts parses time partition IDs by length: YEAR, MONTH, DAY and
+// HOUR partitioning
+var partitionLayouts = map[int]string{
+	4:  "2006",
+	6:  "200601",
+	8:  "20060102",
+	10: "2006010215",
+}
+
+// Time returns the start of a time partition. ok is false for the __NULL__
+// and __UNPARTITIONED__ partitions. Integer-range partition IDs parse too, so
+// only call this for time-partitioned tables.
+func (p PartitionInfo) Time() (time.Time, bool) {
+	layout, ok := partitionLayouts[len(p.PartitionID)]
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(layout, p.PartitionID)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// partitionsQuery lists the partitions of a table, oldest first
+func partitionsQuery(project, dataset, table string) string {
+	return fmt.Sprintf(`SELECT
+  partition_id,
+  total_rows,
+  total_logical_bytes,
+  total_billable_bytes,
+  UNIX_MILLIS(last_modified_time) AS last_modified_time,
+  storage_tier
+FROM %s
+WHERE table_name = %s
+ORDER BY partition_id`, sqlIdent(project, dataset, "INFORMATION_SCHEMA.PARTITIONS"), sqlString(table))
+}
+
+// ListPartitions retrieves the partitions of a table with caching and retry
+// logic. Unpartitioned tables have a single partition with an empty ID.
+func (c *Client) ListPartitions(ctx context.Context, project, dataset, table string) ([]PartitionInfo, error) {
+	cacheKey := cache.PartitionsKey(project, dataset, table)
+
+	// Try cache first
+	if entry, err := c.cache.Get(cacheKey); err == nil {
+		var partitions []PartitionInfo
+		if err := json.Unmarshal([]byte(entry.Data), &partitions); err == nil {
+			return partitions, nil
+		}
+	}
+
+	// Cache miss, fetch from BigQuery with retry
+	return fetchShared(ctx, c, cacheKey, config.PartitionsTTL, "partitions", func() ([]PartitionInfo, error) {
+		var partitions []PartitionInfo
+		err := retry.WithDefaultRetry(ctx, "list partitions", func() error {
+			var fetchErr error
+			partitions, fetchErr = c.backend.ListPartitions(ctx, project, dataset, table)
+			if fetchErr != nil {
+				return errors.WrapBigQueryError(fetchErr, "list_partitions", project, dataset, table)
+			}
+			return nil
+		})
+		return partitions, err
+	})
+}
+
+// PartitionFilter selects partitions for display. The zero value keeps all.
+type PartitionFilter struct {
+	Since     time.Time // Keep time partitions starting at or after Since
+	EmptyOnly bool      // Keep partitions without rows
+	TopBySize int       // Keep the largest partitions by logical bytes, largest first
+}
+
+// FilterPartitions applies a filter, keeping partition ID order unless
+// TopBySize reorders by size
+func FilterPartitions(partitions []PartitionInfo, filter PartitionFilter) []PartitionInfo {
+	kept := make([]PartitionInfo, 0, len(partitions))
+	for _, p := range partitions {
+		if !filter.Since.IsZero() {
+			if t, ok := p.Time(); !ok || t.Before(filter.Since) {
+				continue
+			}
+		}
+		if filter.EmptyOnly && p.TotalRows != 0 {
+			continue
+		}
+		kept = append(kept, p)
+	}
+
+	if filter.TopBySize > 0 {
+		sort.SliceStable(kept, func(i, j int) bool {
+			return kept[i].TotalLogicalBytes > kept[j].TotalLogicalBytes
+		})
+		if len(kept) > filter.TopBySize {
+			kept = kept[:filter.TopBySize]
+		}
+	}
+	return kept
+}
+
+// DailyRows buckets the rows of DAY and HOUR partitions by day, over at most
+// the given number of days up to the newest partition. Days without a
+// partition count zero. Returns the first day, or ok false when no partition
+// is daily or hourly.
+func DailyRows(partitions []PartitionInfo, days int) (time.Time, []int64, bool) {
+	byDay := make(map[time.Time]int64)
+	var first, last time.Time
+	for _, p := range partitions {
+		if len(p.PartitionID) != 8 && len(p.PartitionID) != 10 {
+			continue
+		}
+		t, ok := p.Time()
+		if !ok {
+			continue
+		}
+		day := t.Truncate(24 * time.Hour)
+		byDay[day] += p.TotalRows
+		if first.IsZero() || day.Before(first) {
+			first = day
+		}
+		if day.After(last) {
+			last = day
+		}
+	}
+	if len(byDay) == 0 || days <= 0 {
+		return time.Time{}, nil, false
+	}
+
+	if earliest := last.AddDate(0, 0, -(days - 1)); first.Before(earliest) {
+		first = earliest
+	}
+	rows := make([]int64, int(last.Sub(first)/(24*time.Hour))+1)
+	for i := range rows {
+		rows[i] = byDay[first.AddDate(0, 0, i)]
+	}
+	return first, rows, true
+}
@@ -0,0 +1,135 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"bqs/internal/cache"
+)
+
+func TestPartitionInfoTime(t *testing.T) {
+	tests := []struct {
+		id   string
+		want time.Time
+		ok   bool
+	}{
+		{"2026", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{"202610", time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), true},
+		{"20261016", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), true},
+		{"2026101613", time.Date(2026, 10, 16, 13, 0, 0, 0, time.UTC), true},
+		{"__NULL__", time.Time{}, false},
+		{"__UNPARTITIONED__", time.Time{}, false},
+		{"", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := PartitionInfo{PartitionID: tt.id}.Time()
+		if ok != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("Time(%q) = %v, %v, want %v, %v", tt.id, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFilterPartitions(t *testing.T) {
+	partitions := []PartitionInfo{
+		{PartitionID: "__NULL__", TotalRows: 3, TotalLogicalBytes: 30},
+		{PartitionID: "20261001", TotalRows: 10, TotalLogicalBytes: 100},
+		{PartitionID: "20261002", TotalRows: 0, TotalLogicalBytes: 0},
+		{PartitionID: "20261003", TotalRows: 50, TotalLogicalBytes: 500},
+		{PartitionID: "20261004", TotalRows: 20, TotalLogicalBytes: 200},
+	}
+	ids := func(partitions []PartitionInfo) string {
+		var ids []string
+		for _, p := range partitions {
+			ids = append(ids, p.PartitionID)
+		}
+		return fmt.Sprint(ids)
+	}
+
+	tests := []struct {
+		name   string
+		filter PartitionFilter
+		want   string
+	}{
+		{"none", PartitionFilter{}, "[__NULL__ 20261001 20261002 20261003 20261004]"},
+		{"since", PartitionFilter{Since: time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC)}, "[20261003 20261004]"},
+		{"empty", PartitionFilter{EmptyOnly: true}, "[20261002]"},
+		{"top by size", PartitionFilter{TopBySize: 2}, "[20261003 20261004]"},
+		{"since and top", PartitionFilter{Since: time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC), TopBySize: 5}, "[20261003 20261004 20261002]"},
+	}
+	for _, tt := range tests {
+		if got := ids(FilterPartitions(partitions, tt.filter)); got != tt.want {
+			t.Errorf("%s: FilterPartitions() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDailyRows(t *testing.T) {
+	partitions := []PartitionInfo{
+		{PartitionID: "__NULL__", TotalRows: 7},
+		{PartitionID: "2026100123", TotalRows: 1},
+		{PartitionID: "2026100200", TotalRows: 2},
+		{PartitionID: "2026100223", TotalRows: 3},
+		{PartitionID: "2026100400", TotalRows: 4},
+	}
+	first, rows, ok := DailyRows(partitions, 10)
+	if !ok || !first.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)) || fmt.Sprint(rows) != "[1 5 0 4]" {
+		t.Errorf("DailyRows() = %v, %v, %v", first, rows, ok)
+	}
+
+	// Only the most recent days are kept
+	first, rows, ok = DailyRows(partitions, 2)
+	if !ok || !first.Equal(time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC)) || fmt.Sprint(rows) != "[0 4]" {
+		t.Errorf("DailyRows(2) = %v, %v, %v", first, rows, ok)
+	}
+
+	if _, _, ok := DailyRows([]PartitionInfo{{PartitionID: "202610"}, {PartitionID: ""}}, 10); ok {
+		t.Error("Expected no daily rows for monthly and unpartitioned tables")
+	}
+}
+
+func TestClientListPartitions(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	metadata := &TableMetadata{}
+	metadata.Type = "TABLE"
+	metadata.TableReference = TableReference{ProjectID: "p", DatasetID: "raw", TableID: "events"}
+	writeTableFixture(t, root, metadata)
+
+	partitionsDir := filepath.Join(root, "p", "raw", "_partitions")
+	if err := os.MkdirAll(partitionsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `[{"partition_id": "20261001", "total_rows": "10", "total_logical_bytes": "100",
+		"total_billable_bytes": "40", "last_modified_time": "1759363200000", "storage_tier": "ACTIVE"}]`
+	partitionsFile := filepath.Join(partitionsDir, "events.json")
+	if err := os.WriteFile(partitionsFile, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClientWithBackend(cache.NewMockService(), NewFixtureBackend(root))
+	partitions, err := client.ListPartitions(ctx, "p", "raw", "events")
+	if err != nil {
+		t.Fatalf("ListPartitions returned error: %v", err)
+	}
+	want := PartitionInfo{PartitionID: "20261001", TotalRows: 10, TotalLogicalBytes: 100,
+		TotalBillableBytes: 40, LastModifiedTime: 1759363200000, StorageTier: "ACTIVE"}
+	if len(partitions) != 1 || partitions[0] != want {
+		t.Errorf("ListPartitions() = %+v, want [%+v]", partitions, want)
+	}
+
+	// Partitions are served from the cache
+	if err := os.Remove(partitionsFile); err != nil {
+		t.Fatal(err)
+	}
+	if again, err := client.ListPartitions(ctx, "p", "raw", "events"); err != nil || len(again) != 1 {
+		t.Errorf("Expected the cached partitions, got %v, %v", again, err)
+	}
+
+	if _, err := client.ListPartitions(ctx, "p", "raw", "missing"); err == nil {
+		t.Error("Expected an error for a missing table")
+	}
+}
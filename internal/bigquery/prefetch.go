@@ -0,0 +1,262 @@
+package bigquery
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// PrefetchResult reports a table whose metadata a Prefetcher fetched
+type PrefetchResult struct {
+	Ref TableReference
+	Err error
+}
+
+// Prefetcher warms the metadata cache in the background. A fixed pool of
+// workers takes tables off a queue, no more often than the rate limit allows,
+// and fetches them with GetTableMetadata, which caches them. The queue is
+// replaced as the user moves around, so only the current dataset is fetched.
+//
+// A nil Prefetcher is valid and does nothing, which is how prefetching is
+// turned off.
+type Prefetcher struct {
+	client      *Client
+	parallelism int
+	interval    time.Duration // Minimum time between two fetches; 0 for no limit
+	timeout     time.Duration // Limit on each fetch; 0 for no limit
+
+	mu      sync.Mutex
+	queue   []TableReference
+	paused  bool
+	started bool
+	next    time.Time     // Earliest start of the next fetch
+	wake    chan struct{} // Closed and replaced whenever the queue or pause state changes
+
+	results chan PrefetchResult
+}
+
+// NewPrefetcher creates a prefetcher with the given number of workers, making
+// at most rate fetches per second, each given up after timeout. A rate or
+// timeout of 0 means no limit; a parallelism of 0 or less turns prefetching
+// off and returns nil.
+func NewPrefetcher(client *Client, parallelism int, rate float64, timeout time.Duration) *Prefetcher {
+	if parallelism <= 0 {
+		return nil
+	}
+	var interval time.Duration
+	if rate > 0 {
+		interval = time.Duration(float64(time.Second) / rate)
+	}
+	return &Prefetcher{
+		client:      client,
+		parallelism: parallelism,
+		interval:    interval,
+		timeout:     timeout,
+		wake:        make(chan struct{}),
+		results:     make(chan PrefetchResult, parallelism),
+	}
+}
+
+// Start starts the workers, which stop when ctx is done. Results is closed
+// once they have all stopped. Calling Start more than once has no effect.
+func (p *Prefetcher) Start(ctx context.Context) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.started {
+		return
+	}
+	p.started = true
+
+	var wg sync.WaitGroup
+	for i := 0; i < p.parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(ctx)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(p.results)
+	}()
+}
+
+// Results delivers a result for every table fetched. It must be drained while
+// the prefetcher runs, or the workers stall. A nil Prefetcher returns nil.
+func (p *Prefetcher) Results() <-chan PrefetchResult {
+	if p == nil {
+		return nil
+	}
+	return p.results
+}
+
+// Prefetch replaces the queue with the visible tables followed by the rest,
+// leaving out tables already cached and duplicates
+func (p *Prefetcher) Prefetch(visible, rest []TableReference) {
+	if p == nil {
+		return
+	}
+	seen := make(map[TableReference]bool, len(visible)+len(rest))
+	queue := make([]TableReference, 0, len(visible)+len(rest))
+	for _, refs := range [][]TableReference{visible, rest} {
+		for _, ref := range refs {
+			if seen[ref] || p.client.IsTableMetadataCached(ref.ProjectID, ref.DatasetID, ref.TableID) {
+				continue
+			}
+			seen[ref] = true
+			queue = append(queue, ref)
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queue = queue
+	p.signal()
+}
+
+// Prioritize moves the given tables, if still queued, to the front of the
+// queue in the given order, e.g. when scrolling brings them into view
+func (p *Prefetcher) Prioritize(refs []TableReference) {
+	if p == nil {
+		return
+	}
+	wanted := make(map[TableReference]bool, len(refs))
+	for _, ref := range refs {
+		wanted[ref] = true
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	queued := make(map[TableReference]bool)
+	rest := make([]TableReference, 0, len(p.queue))
+	for _, ref := range p.queue {
+		if wanted[ref] {
+			queued[ref] = true
+		} else {
+			rest = append(rest, ref)
+		}
+	}
+	front := make([]TableReference, 0, len(queued)+len(rest))
+	for _, ref := range refs {
+		if queued[ref] {
+			front = append(front, ref)
+			delete(queued, ref)
+		}
+	}
+	p.queue = append(front, rest...)
+}
+
+// Clear empties the queue. Fetches already running still complete.
+func (p *Prefetcher) Clear() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queue = nil
+	p.signal()
+}
+
+// Pause stops the workers from starting new fetches, e.g. while a foreground
+// load is running, so they don't compete with it. Running fetches complete.
+func (p *Prefetcher) Pause() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = true
+}
+
+// Resume lets the workers continue after Pause
+func (p *Prefetcher) Resume() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.paused {
+		p.paused = false
+		p.signal()
+	}
+}
+
+// Pending returns the number of tables still queued
+func (p *Prefetcher) Pending() int {
+	if p == nil {
+		return 0
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.queue)
+}
+
+// signal wakes the workers waiting for a change. Callers hold p.mu.
+func (p *Prefetcher) signal() {
+	close(p.wake)
+	p.wake = make(chan struct{})
+}
+
+// work fetches queued tables until ctx is done
+func (p *Prefetcher) work(ctx context.Context) {
+	for {
+		ref, ok := p.take(ctx)
+		if !ok {
+			return
+		}
+		// The table may have been opened since it was queued
+		if p.client.IsTableMetadataCached(ref.ProjectID, ref.DatasetID, ref.TableID) {
+			continue
+		}
+
+		fetchCtx, cancel := ctx, context.CancelFunc(func() {})
+		if p.timeout > 0 {
+			fetchCtx, cancel = context.WithTimeout(ctx, p.timeout)
+		}
+		_, err := p.client.GetTableMetadata(fetchCtx, ref.ProjectID, ref.DatasetID, ref.TableID)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+
+		select {
+		case p.results <- PrefetchResult{Ref: ref, Err: err}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// take waits until a table is queued, the prefetcher isn't paused and the
+// rate limit allows another fetch, then dequeues the table. It returns false
+// once ctx is done.
+func (p *Prefetcher) take(ctx context.Context) (TableReference, bool) {
+	for {
+		p.mu.Lock()
+		var timer <-chan time.Time
+		if !p.paused && len(p.queue) > 0 {
+			now := time.Now()
+			if wait := p.next.Sub(now); wait > 0 {
+				timer = time.After(wait)
+			} else {
+				ref := p.queue[0]
+				p.queue = p.queue[1:]
+				p.next = now.Add(p.interval)
+				p.mu.Unlock()
+				return ref, true
+			}
+		}
+		wake := p.wake
+		p.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return TableReference{}, false
+		case <-wake:
+		case <-timer:
+		}
+	}
+}
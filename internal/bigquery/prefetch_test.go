@@ -0,0 +1,205 @@
+package bigquery
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"bqs/internal/cache"
+)
+
+// prefetchBackend serves empty table metadata, recording the tables fetched
+// and how many fetches ran at once. Fetches block while gate is non-nil and open.
+type prefetchBackend struct {
+	Backend
+	gate chan struct{}
+
+	mu      sync.Mutex
+	fetched []string
+	running int
+	peak    int
+}
+
+func (b *prefetchBackend) GetTableMetadata(ctx context.Context, project, dataset, table string) (*TableMetadata, error) {
+	b.mu.Lock()
+	b.fetched = append(b.fetched, table)
+	b.running++
+	if b.running > b.peak {
+		b.peak = b.running
+	}
+	b.mu.Unlock()
+
+	defer func() {
+		b.mu.Lock()
+		b.running--
+		b.mu.Unlock()
+	}()
+	if b.gate != nil {
+		select {
+		case <-b.gate:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return &TableMetadata{}, nil
+}
+
+func prefetchRefs(tables ...string) []TableReference {
+	refs := make([]TableReference, len(tables))
+	for i, table := range tables {
+		refs[i] = TableReference{ProjectID: "p", DatasetID: "d", TableID: table}
+	}
+	return refs
+}
+
+// receivePrefetched waits for n results and returns their table IDs
+func receivePrefetched(t *testing.T, p *Prefetcher, n int) []string {
+	t.Helper()
+	var tables []string
+	for len(tables) < n {
+		select {
+		case result := <-p.Results():
+			if result.Err != nil {
+				t.Fatalf("Prefetching %s failed: %v", result.Ref.TableID, result.Err)
+			}
+			tables = append(tables, result.Ref.TableID)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out after %d of %d results: %v", len(tables), n, tables)
+		}
+	}
+	return tables
+}
+
+func TestPrefetcherOrder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockCache := cache.NewMockService()
+	client := NewClientWithBackend(mockCache, &prefetchBackend{})
+	if err := mockCache.Set(cache.MetadataKey("p", "d", "cached"), "{}", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewPrefetcher(client, 1, 0, time.Minute)
+	p.Pause()
+	p.Start(ctx)
+	p.Prefetch(prefetchRefs("b", "cached"), prefetchRefs("a", "b", "c", "d"))
+	if p.Pending() != 4 {
+		t.Fatalf("Pending() = %d, want 4: cached tables and duplicates are left out", p.Pending())
+	}
+	p.Prioritize(prefetchRefs("d", "c", "x"))
+	p.Resume()
+
+	got := receivePrefetched(t, p, 4)
+	want := []string{"d", "c", "b", "a"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Fetched %v, want %v", got, want)
+		}
+	}
+	if !client.IsTableMetadataCached("p", "d", "a") {
+		t.Error("Prefetched metadata wasn't cached")
+	}
+}
+
+func TestPrefetcherParallelismAndPause(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	backend := &prefetchBackend{gate: make(chan struct{})}
+	p := NewPrefetcher(NewClientWithBackend(cache.NewMockService(), backend), 2, 0, time.Minute)
+	p.Start(ctx)
+	p.Prefetch(nil, prefetchRefs("a", "b", "c", "d", "e"))
+
+	// Both workers block on the gate, so exactly two fetches start
+	deadline := time.Now().Add(5 * time.Second)
+	for p.Pending() != 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if p.Pending() != 3 {
+		t.Fatalf("Pending() = %d, want 3 with two workers busy", p.Pending())
+	}
+
+	// Pausing lets the running fetches complete but starts no new ones
+	p.Pause()
+	backend.gate <- struct{}{}
+	backend.gate <- struct{}{}
+	receivePrefetched(t, p, 2)
+	time.Sleep(20 * time.Millisecond)
+	if p.Pending() != 3 {
+		t.Fatalf("Pending() = %d while paused, want 3", p.Pending())
+	}
+
+	p.Resume()
+	close(backend.gate)
+	receivePrefetched(t, p, 3)
+
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+	if backend.peak != 2 {
+		t.Errorf("Peak concurrency %d, want 2", backend.peak)
+	}
+	if len(backend.fetched) != 5 {
+		t.Errorf("Fetched %v, want each table once", backend.fetched)
+	}
+}
+
+func TestPrefetcherRateLimit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := NewPrefetcher(NewClientWithBackend(cache.NewMockService(), &prefetchBackend{}), 4, 20, time.Minute)
+	p.Start(ctx)
+	start := time.Now()
+	p.Prefetch(prefetchRefs("a", "b", "c"), nil)
+	receivePrefetched(t, p, 3)
+
+	// At 20 per second the third fetch starts 100ms after the first
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Three fetches took %s, faster than the rate limit allows", elapsed)
+	}
+}
+
+func TestPrefetcherOffAndStopped(t *testing.T) {
+	if p := NewPrefetcher(nil, 0, 10, time.Minute); p != nil {
+		t.Fatal("NewPrefetcher with no workers should turn prefetching off")
+	}
+	var off *Prefetcher
+	off.Prefetch(prefetchRefs("a"), nil)
+	off.Pause()
+	off.Resume()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := NewPrefetcher(NewClientWithBackend(cache.NewMockService(), &prefetchBackend{}), 3, 0, time.Minute)
+	p.Start(ctx)
+	cancel()
+	select {
+	case _, ok := <-p.Results():
+		if ok {
+			t.Error("Unexpected result after cancellation")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Results wasn't closed after the context was cancelled")
+	}
+}
+
+func TestPrefetcherTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The gate never opens, so the fetch only ends when it times out
+	backend := &prefetchBackend{gate: make(chan struct{})}
+	p := NewPrefetcher(NewClientWithBackend(cache.NewMockService(), backend), 1, 0, 50*time.Millisecond)
+	p.Start(ctx)
+	p.Prefetch(prefetchRefs("slow"), nil)
+
+	select {
+	case result := <-p.Results():
+		if result.Err == nil {
+			t.Error("Expected the blocked fetch to fail once it timed out")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("The fetch wasn't given up after its timeout")
+	}
+}
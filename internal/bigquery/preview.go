@@ -0,0 +1,118 @@
+package bigquery
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// TablePreview holds the first rows of a table in `bq head --format=json`
+// form: one object per row keyed by column name, RECORD values as nested
+// objects, REPEATED values as arrays and scalars as strings (nil for NULL).
+// Schema gives the column order.
+type TablePreview struct {
+	Schema *Schema
+	Rows   []map[string]interface{}
+}
+
+// Columns returns the top-level column names in schema order
+func (p *TablePreview) Columns() []string {
+	if p.Schema == nil {
+		return nil
+	}
+	columns := make([]string, len(p.Schema.Fields))
+	for i, field := range p.Schema.Fields {
+		columns[i] = field.Name
+	}
+	return columns
+}
+
+// Previewable reports whether a table type stores rows that can be read
+// without running a query. Views, materialized views and external tables
+// can't be listed with tabledata.list.
+func Previewable(tableType string) bool {
+	switch tableType {
+	case "TABLE", "SNAPSHOT", "CLONE", "":
+		return true
+	default:
+		return false
+	}
+}
+
+// IsNestedValue reports whether a preview value is a RECORD or REPEATED value
+func IsNestedValue(value interface{}) bool {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// FormatCellValue renders a preview value on one line: NULL for nil, the
+// value itself for scalars and compact JSON for nested values
+func FormatCellValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return v
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "?"
+		}
+		return string(data)
+	}
+}
+
+// decodeTableDataRow converts a tabledata.list row ({"f": [{"v": ...}]})
+// into bq head form using the table schema
+func decodeTableDataRow(fields []SchemaField, row interface{}) map[string]interface{} {
+	decoded := make(map[string]interface{}, len(fields))
+	record, _ := row.(map[string]interface{})
+	cells, _ := record["f"].([]interface{})
+	for i, field := range fields {
+		if i >= len(cells) {
+			break
+		}
+		cell, _ := cells[i].(map[string]interface{})
+		decoded[field.Name] = decodeTableDataValue(field, cell["v"])
+	}
+	return decoded
+}
+
+// decodeTableDataValue converts a single tabledata.list cell value
+func decodeTableDataValue(field SchemaField, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	if field.Mode == "REPEATED" {
+		items, _ := value.([]interface{})
+		element := field
+		element.Mode = "NULLABLE"
+		decoded := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			cell, _ := item.(map[string]interface{})
+			decoded = append(decoded, decodeTableDataValue(element, cell["v"]))
+		}
+		return decoded
+	}
+
+	if field.Type == "RECORD" || field.Type == "STRUCT" {
+		return decodeTableDataRow(field.Fields, value)
+	}
+
+	// Timestamps arrive as microseconds since the epoch (useInt64Timestamp);
+	// print them the way bq head does
+	if field.Type == "TIMESTAMP" {
+		if s, ok := value.(string); ok {
+			if micros, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return time.UnixMicro(micros).UTC().Format("2006-01-02 15:04:05.999999")
+			}
+		}
+	}
+
+	return value
+}
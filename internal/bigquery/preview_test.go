@@ -0,0 +1,51 @@
+package bigquery
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bqs/internal/cache"
+)
+
+func TestClientPreviewTable(t *testing.T) {
+	ctx := context.Background()
+	client := NewClientWithBackend(cache.NewMockService(), NewFixtureBackend(fixtureDir))
+
+	preview, err := client.PreviewTable(ctx, "demo-project", "analytics", "events", 2)
+	if err != nil {
+		t.Fatalf("PreviewTable returned error: %v", err)
+	}
+	if want := []string{"event_id", "user_id", "event_timestamp", "event_params"}; !reflect.DeepEqual(preview.Columns(), want) {
+		t.Errorf("Columns() = %v, want %v", preview.Columns(), want)
+	}
+	if len(preview.Rows) != 2 || preview.Rows[0]["event_id"] != "e-0001" || preview.Rows[1]["user_id"] != nil {
+		t.Errorf("Unexpected rows: %+v", preview.Rows)
+	}
+
+	_, err = client.PreviewTable(ctx, "demo-project", "analytics", "daily_users", 2)
+	if err == nil || !strings.Contains(err.Error(), "is a view") {
+		t.Errorf("Expected views to be rejected, got %v", err)
+	}
+}
+
+func TestFormatCellValue(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{nil, "NULL"},
+		{"42", "42"},
+		{[]interface{}{"a", "b"}, `["a","b"]`},
+		{map[string]interface{}{"key": "page", "value": nil}, `{"key":"page","value":null}`},
+	}
+	for _, tt := range tests {
+		if got := FormatCellValue(tt.value); got != tt.want {
+			t.Errorf("FormatCellValue(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+		if IsNestedValue(tt.value) != strings.ContainsAny(tt.want, "[{") {
+			t.Errorf("IsNestedValue(%v) = %v", tt.value, IsNestedValue(tt.value))
+		}
+	}
+}
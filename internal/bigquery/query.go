@@ -0,0 +1,56 @@
+package bigquery
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"bqs/internal/config"
+)
+
+// QueryEstimate is the outcome of a dry run: what running the query would
+// process, and the shape of its result
+type QueryEstimate struct {
+	TotalBytesProcessed int64
+	StatementType       string // SELECT, INSERT, CREATE_TABLE, ...
+	Schema              *Schema
+}
+
+// EstimatedCost returns the on-demand price of the query in USD. Capacity
+// (slot) pricing doesn't bill per byte, so this is an upper bound there.
+func (e *QueryEstimate) EstimatedCost() float64 {
+	return float64(e.TotalBytesProcessed) / (1 << 40) * config.OnDemandPricePerTiB
+}
+
+// QueryOptions limits a query run
+type QueryOptions struct {
+	MaxRows        int   // Rows to return; the job itself may produce more
+	MaxBytesBilled int64 // BigQuery fails the job instead of billing more; 0 for no limit
+
+	// RequestID is shared by retries of the same run so backends that
+	// support it don't start a second job; the client fills it in, and
+	// only retries runs on backends that support it
+	RequestID string
+}
+
+// QueryResult holds the first rows of a query result in the same form as a
+// table preview, plus job statistics
+type QueryResult struct {
+	TablePreview
+	TotalBytesProcessed int64
+	CacheHit            bool
+}
+
+// normalizeSQL collapses whitespace so equivalent query texts compare equal
+func normalizeSQL(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
+
+// newRequestID returns a random request ID for QueryOptions
+func newRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
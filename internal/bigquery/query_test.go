@@ -0,0 +1,142 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"bqs/internal/cache"
+	"bqs/internal/errors"
+)
+
+const topUsersQuery = `
+	SELECT user_id, COUNT(*) AS events
+	FROM ` + "`demo-project.analytics.events`" + `
+	WHERE DATE(event_timestamp) = '2024-12-01'
+	GROUP BY user_id
+	ORDER BY events DESC`
+
+func TestClientDryRunQuery(t *testing.T) {
+	client := NewClientWithBackend(cache.NewMockService(), NewFixtureBackend(fixtureDir))
+
+	estimate, err := client.DryRunQuery(context.Background(), "demo-project", topUsersQuery)
+	if err != nil {
+		t.Fatalf("DryRunQuery returned error: %v", err)
+	}
+	if estimate.TotalBytesProcessed != 50<<20 || estimate.StatementType != "SELECT" {
+		t.Errorf("Unexpected estimate: %+v", estimate)
+	}
+	if len(estimate.Schema.Fields) != 2 {
+		t.Errorf("Expected the result schema, got %+v", estimate.Schema)
+	}
+}
+
+func TestClientRunQuery(t *testing.T) {
+	ctx := context.Background()
+	client := NewClientWithBackend(cache.NewMockService(), NewFixtureBackend(fixtureDir))
+
+	result, err := client.RunQuery(ctx, "demo-project", topUsersQuery, QueryOptions{MaxRows: 2})
+	if err != nil {
+		t.Fatalf("RunQuery returned error: %v", err)
+	}
+	if want := []string{"user_id", "events"}; !reflect.DeepEqual(result.Columns(), want) {
+		t.Errorf("Columns() = %v, want %v", result.Columns(), want)
+	}
+	if len(result.Rows) != 2 || result.Rows[0]["user_id"] != "u-42" || result.Rows[1]["events"] != "9" {
+		t.Errorf("Unexpected rows: %+v", result.Rows)
+	}
+}
+
+func TestClientRunQueryFailures(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	client := NewClientWithBackend(cache.NewMockService(), NewFixtureBackend(fixtureDir))
+
+	// Rejected queries must fail at once rather than being retried
+	_, err := client.RunQuery(ctx, "demo-project", "SELECT * FROM `demo-project.analytics.events`",
+		QueryOptions{MaxRows: 10, MaxBytesBilled: 1 << 30})
+	bqsErr, ok := err.(*errors.BQSError)
+	if !ok || bqsErr.Retryable || !strings.Contains(bqsErr.Message, "bytes billed") {
+		t.Errorf("Expected a non-retryable byte limit error, got %v", err)
+	}
+
+	_, err = client.DryRunQuery(ctx, "demo-project", "SELECT nonsense")
+	bqsErr, ok = err.(*errors.BQSError)
+	if !ok || bqsErr.Retryable || !strings.HasPrefix(bqsErr.Message, "Query failed") {
+		t.Errorf("Expected a non-retryable query error, got %v", err)
+	}
+}
+
+func TestClientRunQueryRunsDMLOnce(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake bq is a shell script")
+	}
+	// A bq that logs each run and fails with an error that is otherwise retried
+	dir := t.TempDir()
+	log := filepath.Join(dir, "runs.log")
+	script := "#!/bin/sh\necho \"$@\" >> '" + log + "'\necho 'BigQuery error in query operation: Error processing job: backendError' >&2\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(dir, "bq"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	client := NewClientWithBackend(cache.NewMockService(), NewCLIBackend())
+	_, err := client.RunQuery(context.Background(), "demo-project",
+		"DELETE FROM `demo-project.analytics.events` WHERE TRUE", QueryOptions{MaxRows: 10})
+	if err == nil {
+		t.Fatal("Expected the failing DELETE to fail")
+	}
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runs := strings.Count(string(data), "\n"); runs != 1 {
+		t.Errorf("bq query ran %d times, want the DELETE executed exactly once", runs)
+	}
+}
+
+// idempotentBackend fails its first query with an error that is otherwise
+// retried, recording the request ID of every attempt
+type idempotentBackend struct {
+	Backend
+	requestIDs []string
+}
+
+func (b *idempotentBackend) IdempotentQueries() bool {
+	return true
+}
+
+func (b *idempotentBackend) RunQuery(ctx context.Context, project, sql string, opts QueryOptions) (*QueryResult, error) {
+	b.requestIDs = append(b.requestIDs, opts.RequestID)
+	if len(b.requestIDs) == 1 {
+		return nil, fmt.Errorf("Error processing job: backendError")
+	}
+	return b.Backend.RunQuery(ctx, project, sql, opts)
+}
+
+func TestClientRunQueryRetriesIdempotentBackends(t *testing.T) {
+	backend := &idempotentBackend{Backend: NewFixtureBackend(fixtureDir)}
+	client := NewClientWithBackend(cache.NewMockService(), backend)
+
+	if _, err := client.RunQuery(context.Background(), "demo-project", topUsersQuery, QueryOptions{MaxRows: 2}); err != nil {
+		t.Fatalf("RunQuery returned error: %v", err)
+	}
+	if len(backend.requestIDs) != 2 || backend.requestIDs[0] == "" || backend.requestIDs[0] != backend.requestIDs[1] {
+		t.Errorf("Expected one retry with the same request ID, got %q", backend.requestIDs)
+	}
+}
+
+func TestQueryEstimateCost(t *testing.T) {
+	estimate := &QueryEstimate{TotalBytesProcessed: 2 << 40}
+	if cost := estimate.EstimatedCost(); math.Abs(cost-12.5) > 1e-9 {
+		t.Errorf("EstimatedCost() = %v, want 12.5", cost)
+	}
+}
@@ -0,0 +1,566 @@
+package bigquery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"bqs/internal/auth"
+	"bqs/internal/config"
+	"bqs/internal/errors"
+)
+
+// DefaultRESTEndpoint is the public BigQuery v2 API root
+const DefaultRESTEndpoint = "https://bigquery.googleapis.com/bigquery/v2"
+
+// RESTBackend talks to the BigQuery v2 REST API directly over net/http,
+// avoiding the Python startup cost of the bq tool
+type RESTBackend struct {
+	endpoint   string
+	tokens     auth.TokenSource
+	httpClient *http.Client
+}
+
+// NewRESTBackend creates a REST backend for the given API root.
+// An empty endpoint selects DefaultRESTEndpoint.
+func NewRESTBackend(endpoint string, tokens auth.TokenSource) *RESTBackend {
+	if endpoint == "" {
+		endpoint = DefaultRESTEndpoint
+	}
+	return &RESTBackend{
+		endpoint:   strings.TrimRight(endpoint, "/"),
+		tokens:     tokens,
+		httpClient: &http.Client{Timeout: config.RESTRequestTimeout},
+	}
+}
+
+// tableListResponse is the tables.list response body
+type tableListResponse struct {
+	Tables        []TableInfo `json:"tables"`
+	NextPageToken string      `json:"nextPageToken"`
+	TotalItems    int         `json:"totalItems"`
+}
+
+// projectListResponse is the projects.list response body
+type projectListResponse struct {
+	Projects      []ProjectInfo `json:"projects"`
+	NextPageToken string        `json:"nextPageToken"`
+}
+
+// datasetListResponse is the datasets.list response body
+type datasetListResponse struct {
+	Datasets      []DatasetInfo `json:"datasets"`
+	NextPageToken string        `json:"nextPageToken"`
+}
+
+// tableDataResponse is the tabledata.list response body. Rows hold one
+// {"v": value} cell per schema field, nested for RECORD and REPEATED fields.
+type tableDataResponse struct {
+	Rows      []interface{} `json:"rows"`
+	PageToken string        `json:"pageToken"`
+}
+
+// queryRequest is the jobs.query request body
+type queryRequest struct {
+	Query              string         `json:"query"`
+	UseLegacySQL       bool           `json:"useLegacySql"`
+	DryRun             bool           `json:"dryRun,omitempty"`
+	MaxResults         int            `json:"maxResults,omitempty"`
+	TimeoutMs          int64          `json:"timeoutMs,omitempty"`
+	MaximumBytesBilled int64          `json:"maximumBytesBilled,omitempty,string"`
+	RequestID          string         `json:"requestId,omitempty"` // Makes retried jobs.query calls idempotent
+	FormatOptions      *formatOptions `json:"formatOptions,omitempty"`
+}
+
+// formatOptions controls how jobs.query encodes result values
+type formatOptions struct {
+	UseInt64Timestamp bool `json:"useInt64Timestamp"`
+}
+
+// queryResponse is the jobs.query and jobs.getQueryResults response body.
+// Rows hold one {"v": value} cell per schema field, nested for RECORD and
+// REPEATED fields.
+type queryResponse struct {
+	JobComplete  bool `json:"jobComplete"`
+	JobReference struct {
+		ProjectID string `json:"projectId"`
+		JobID     string `json:"jobId"`
+		Location  string `json:"location"`
+	} `json:"jobReference"`
+	Schema              Schema        `json:"schema"`
+	Rows                []interface{} `json:"rows"`
+	PageToken           string        `json:"pageToken"`
+	TotalBytesProcessed int64         `json:"totalBytesProcessed,string"`
+	CacheHit            bool          `json:"cacheHit"`
+}
+
+// apiErrorResponse is the error envelope returned for failed requests
+type apiErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Errors  []struct {
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	} `json:"error"`
+}
+
+// ListTablesPage calls tables.list for a single page
+func (b *RESTBackend) ListTablesPage(ctx context.Context, project, dataset, pageToken string) (*TablePage, error) {
+	query := url.Values{"maxResults": {strconv.Itoa(config.TableListPageSize)}}
+	if pageToken != "" {
+		query.Set("pageToken", pageToken)
+	}
+
+	var resp tableListResponse
+	path := fmt.Sprintf("/projects/%s/datasets/%s/tables", url.PathEscape(project), url.PathEscape(dataset))
+	if err := b.get(ctx, path, query, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	return &TablePage{
+		Tables:        resp.Tables,
+		NextPageToken: resp.NextPageToken,
+		TotalItems:    resp.TotalItems,
+	}, nil
+}
+
+// GetSchema calls tables.get restricted to the schema field
+func (b *RESTBackend) GetSchema(ctx context.Context, project, dataset, table string) (*Schema, error) {
+	var resp struct {
+		Schema Schema `json:"schema"`
+		ETag   string `json:"etag"`
+	}
+	if err := b.get(ctx, tablePath(project, dataset, table), url.Values{"fields": {"schema,etag"}}, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get schema: %w", err)
+	}
+	resp.Schema.tableETag = resp.ETag
+	return &resp.Schema, nil
+}
+
+// GetTableMetadata calls tables.get
+func (b *RESTBackend) GetTableMetadata(ctx context.Context, project, dataset, table string) (*TableMetadata, error) {
+	var metadata TableMetadata
+	if err := b.get(ctx, tablePath(project, dataset, table), nil, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to get table metadata: %w", err)
+	}
+	return &metadata, nil
+}
+
+// TableChanged calls tables.get for the etag alone, conditional on it
+// having changed. BigQuery answers 304 Not Modified or the etag itself,
+// which is compared in case the condition was ignored.
+func (b *RESTBackend) TableChanged(ctx context.Context, project, dataset, table, etag string) (bool, error) {
+	var resp struct {
+		ETag string `json:"etag"`
+	}
+	header := http.Header{"If-None-Match": {etag}}
+	status, err := b.send(ctx, http.MethodGet, tablePath(project, dataset, table), url.Values{"fields": {"etag"}}, header, nil, &resp)
+	if err != nil {
+		return false, fmt.Errorf("failed to revalidate table metadata: %w", err)
+	}
+	return status != http.StatusNotModified && resp.ETag != etag, nil
+}
+
+// ListProjects calls projects.list, following page tokens
+func (b *RESTBackend) ListProjects(ctx context.Context) ([]ProjectInfo, error) {
+	query := url.Values{"maxResults": {strconv.Itoa(config.TableListPageSize)}}
+
+	projects := []ProjectInfo{}
+	for {
+		var resp projectListResponse
+		if err := b.get(ctx, "/projects", query, &resp); err != nil {
+			return nil, fmt.Errorf("failed to list projects: %w", err)
+		}
+		projects = append(projects, resp.Projects...)
+		if resp.NextPageToken == "" {
+			return projects, nil
+		}
+		query.Set("pageToken", resp.NextPageToken)
+	}
+}
+
+// ListDatasets calls datasets.list, which leaves out creation time, default
+// expiration and table counts; Client fills those in from its cache
+func (b *RESTBackend) ListDatasets(ctx context.Context, project string) ([]DatasetInfo, error) {
+	path := fmt.Sprintf("/projects/%s/datasets", url.PathEscape(project))
+	query := url.Values{"maxResults": {strconv.Itoa(config.TableListPageSize)}}
+
+	datasets := []DatasetInfo{}
+	for {
+		var resp datasetListResponse
+		if err := b.get(ctx, path, query, &resp); err != nil {
+			return nil, fmt.Errorf("failed to list datasets: %w", err)
+		}
+		datasets = append(datasets, resp.Datasets...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		query.Set("pageToken", resp.NextPageToken)
+	}
+
+	return datasets, nil
+}
+
+// GetDatasetMetadata calls datasets.get
+func (b *RESTBackend) GetDatasetMetadata(ctx context.Context, project, dataset string) (*DatasetMetadata, error) {
+	var metadata DatasetMetadata
+	if err := b.get(ctx, datasetPath(project, dataset), nil, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to get dataset metadata: %w", err)
+	}
+	return &metadata, nil
+}
+
+// ListTableMetadata runs the bulk INFORMATION_SCHEMA query through jobs.query
+func (b *RESTBackend) ListTableMetadata(ctx context.Context, project, dataset string) ([]TableMetadata, error) {
+	rows, err := b.query(ctx, project, datasetSchemaQuery(project, dataset))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table metadata: %w", err)
+	}
+
+	// Re-encode as the flat objects bq query --format=json prints
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return nil, err
+	}
+	var schemaRows []informationSchemaRow
+	if err := json.Unmarshal(data, &schemaRows); err != nil {
+		return nil, fmt.Errorf("failed to parse table metadata: %w", err)
+	}
+
+	return tableMetadataFromRows(project, dataset, schemaRows)
+}
+
+// ListPartitions queries INFORMATION_SCHEMA.PARTITIONS through jobs.query
+func (b *RESTBackend) ListPartitions(ctx context.Context, project, dataset, table string) ([]PartitionInfo, error) {
+	rows, err := b.query(ctx, project, partitionsQuery(project, dataset, table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query partitions: %w", err)
+	}
+
+	// Re-encode as the flat objects bq query --format=json prints
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return nil, err
+	}
+	partitions := []PartitionInfo{}
+	if err := json.Unmarshal(data, &partitions); err != nil {
+		return nil, fmt.Errorf("failed to parse partitions: %w", err)
+	}
+	return partitions, nil
+}
+
+// ListTableStorage queries INFORMATION_SCHEMA.TABLE_STORAGE through jobs.query
+func (b *RESTBackend) ListTableStorage(ctx context.Context, project, location string) ([]TableStorage, error) {
+	rows, err := b.query(ctx, project, tableStorageQuery(project, location))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table storage: %w", err)
+	}
+
+	// Re-encode as the flat objects bq query --format=json prints
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return nil, err
+	}
+	tables := []TableStorage{}
+	if err := json.Unmarshal(data, &tables); err != nil {
+		return nil, fmt.Errorf("failed to parse table storage: %w", err)
+	}
+	return tables, nil
+}
+
+// ListTableJobs queries INFORMATION_SCHEMA.JOBS_BY_PROJECT through jobs.query
+func (b *RESTBackend) ListTableJobs(ctx context.Context, project, location, dataset, table string, days int) ([]TableJob, error) {
+	rows, err := b.query(ctx, project, tableJobsQuery(project, location, dataset, table, days))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query jobs: %w", err)
+	}
+
+	// Re-encode as the flat objects bq query --format=json prints
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return nil, err
+	}
+	jobs := []TableJob{}
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to parse jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// ListTableReads queries INFORMATION_SCHEMA.JOBS_BY_PROJECT through jobs.query
+func (b *RESTBackend) ListTableReads(ctx context.Context, project, location, dataset string, days int) ([]TableReads, error) {
+	rows, err := b.query(ctx, project, tableReadsQuery(project, location, dataset, days))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query jobs: %w", err)
+	}
+
+	// Re-encode as the flat objects bq query --format=json prints
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return nil, err
+	}
+	reads := []TableReads{}
+	if err := json.Unmarshal(data, &reads); err != nil {
+		return nil, fmt.Errorf("failed to parse table reads: %w", err)
+	}
+	return reads, nil
+}
+
+// ListRows calls tabledata.list, following page tokens until maxResults rows
+// have arrived. The schema is fetched first to name and decode the cells.
+func (b *RESTBackend) ListRows(ctx context.Context, project, dataset, table string, maxResults int) ([]map[string]interface{}, error) {
+	schema, err := b.GetSchema(ctx, project, dataset, table)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{"formatOptions.useInt64Timestamp": {"true"}}
+	rows := []map[string]interface{}{}
+	for len(rows) < maxResults {
+		query.Set("maxResults", strconv.Itoa(maxResults-len(rows)))
+		var resp tableDataResponse
+		if err := b.get(ctx, tablePath(project, dataset, table)+"/data", query, &resp); err != nil {
+			return nil, fmt.Errorf("failed to read rows: %w", err)
+		}
+		for _, row := range resp.Rows {
+			rows = append(rows, decodeTableDataRow(schema.Fields, row))
+		}
+		if resp.PageToken == "" || len(resp.Rows) == 0 {
+			break
+		}
+		query.Set("pageToken", resp.PageToken)
+	}
+
+	return rows, nil
+}
+
+// DryRunQuery calls jobs.query with dryRun set. BigQuery validates the query
+// and returns its schema and byte estimate without creating a job.
+func (b *RESTBackend) DryRunQuery(ctx context.Context, project, sql string) (*QueryEstimate, error) {
+	var resp struct {
+		TotalBytesProcessed int64  `json:"totalBytesProcessed,string"`
+		StatementType       string `json:"statementType"`
+		Schema              Schema `json:"schema"`
+	}
+	req := queryRequest{Query: sql, DryRun: true}
+	if err := b.post(ctx, fmt.Sprintf("/projects/%s/queries", url.PathEscape(project)), req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to dry-run query: %w", err)
+	}
+
+	return &QueryEstimate{
+		TotalBytesProcessed: resp.TotalBytesProcessed,
+		StatementType:       resp.StatementType,
+		Schema:              &resp.Schema,
+	}, nil
+}
+
+// RunQuery runs a query through jobs.query with the byte limit enforced by
+// BigQuery. The request ID makes retries reuse the first attempt's job.
+func (b *RESTBackend) RunQuery(ctx context.Context, project, sql string, opts QueryOptions) (*QueryResult, error) {
+	req := queryRequest{
+		Query:              sql,
+		MaxResults:         config.QueryPageSize,
+		TimeoutMs:          config.QueryWaitTimeout.Milliseconds(),
+		MaximumBytesBilled: opts.MaxBytesBilled,
+		RequestID:          opts.RequestID,
+		FormatOptions:      &formatOptions{UseInt64Timestamp: true},
+	}
+	if opts.MaxRows > 0 && opts.MaxRows < req.MaxResults {
+		req.MaxResults = opts.MaxRows
+	}
+
+	result, err := b.runQuery(ctx, project, req, opts.MaxRows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run query: %w", err)
+	}
+	return result, nil
+}
+
+// IdempotentQueries implements IdempotentQuerier: jobs.query deduplicates
+// requests by requestId
+func (b *RESTBackend) IdempotentQueries() bool {
+	return true
+}
+
+// query runs an internal GoogleSQL query and returns every row as a column
+// name to value map
+func (b *RESTBackend) query(ctx context.Context, project, sql string) ([]map[string]interface{}, error) {
+	req := queryRequest{
+		Query:      sql,
+		MaxResults: config.QueryPageSize,
+		TimeoutMs:  config.QueryWaitTimeout.Milliseconds(),
+	}
+	result, err := b.runQuery(ctx, project, req, 0)
+	if err != nil {
+		return nil, err
+	}
+	return result.Rows, nil
+}
+
+// runQuery posts req to jobs.query, waits for the job and follows result
+// pages with jobs.getQueryResults until maxRows rows (0 for all) have arrived
+func (b *RESTBackend) runQuery(ctx context.Context, project string, req queryRequest, maxRows int) (*QueryResult, error) {
+	var resp queryResponse
+	if err := b.post(ctx, fmt.Sprintf("/projects/%s/queries", url.PathEscape(project)), req, &resp); err != nil {
+		return nil, err
+	}
+
+	job := resp.JobReference
+	result := &QueryResult{TablePreview: TablePreview{Rows: []map[string]interface{}{}}}
+	for {
+		if resp.JobComplete {
+			result.Schema = &resp.Schema
+			result.TotalBytesProcessed = resp.TotalBytesProcessed
+			result.CacheHit = resp.CacheHit
+			for _, row := range resp.Rows {
+				if maxRows > 0 && len(result.Rows) == maxRows {
+					return result, nil
+				}
+				result.Rows = append(result.Rows, decodeTableDataRow(resp.Schema.Fields, row))
+			}
+			if resp.PageToken == "" || (maxRows > 0 && len(result.Rows) == maxRows) {
+				return result, nil
+			}
+		}
+
+		query := url.Values{
+			"maxResults": {strconv.Itoa(req.MaxResults)},
+			"timeoutMs":  {strconv.FormatInt(req.TimeoutMs, 10)},
+		}
+		if job.Location != "" {
+			query.Set("location", job.Location)
+		}
+		if req.FormatOptions != nil && req.FormatOptions.UseInt64Timestamp {
+			query.Set("formatOptions.useInt64Timestamp", "true")
+		}
+		if resp.JobComplete {
+			query.Set("pageToken", resp.PageToken)
+		}
+		path := fmt.Sprintf("/projects/%s/queries/%s", url.PathEscape(job.ProjectID), url.PathEscape(job.JobID))
+		resp = queryResponse{}
+		if err := b.get(ctx, path, query, &resp); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// datasetPath builds the datasets.get path for a dataset
+func datasetPath(project, dataset string) string {
+	return fmt.Sprintf("/projects/%s/datasets/%s", url.PathEscape(project), url.PathEscape(dataset))
+}
+
+// tablePath builds the tables.get path for a table
+func tablePath(project, dataset, table string) string {
+	return fmt.Sprintf("/projects/%s/datasets/%s/tables/%s",
+		url.PathEscape(project), url.PathEscape(dataset), url.PathEscape(table))
+}
+
+// get performs an authenticated GET and decodes the JSON response into out
+func (b *RESTBackend) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	return b.do(ctx, http.MethodGet, path, query, nil, out)
+}
+
+// post performs an authenticated POST of a JSON body and decodes the JSON
+// response into out
+func (b *RESTBackend) post(ctx context.Context, path string, body, out interface{}) error {
+	return b.do(ctx, http.MethodPost, path, nil, body, out)
+}
+
+// do performs an authenticated request and decodes the JSON response into out
+func (b *RESTBackend) do(ctx context.Context, method, path string, query url.Values, in, out interface{}) error {
+	_, err := b.send(ctx, method, path, query, nil, in, out)
+	return err
+}
+
+// send performs an authenticated request with extra headers and decodes the
+// JSON response into out, returning the status code. A 304 Not Modified
+// answer to a conditional request leaves out alone.
+func (b *RESTBackend) send(ctx context.Context, method, path string, query url.Values, header http.Header, in, out interface{}) (int, error) {
+	reqURL := b.endpoint + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
+	if err != nil {
+		return 0, err
+	}
+
+	token, err := b.tokens.Token(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotModified && header.Get("If-None-Match") != "" {
+		return resp.StatusCode, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, parseAPIError(resp, body)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return resp.StatusCode, nil
+}
+
+// parseAPIError converts a non-2xx response into an errors.APIError
+func parseAPIError(resp *http.Response, body []byte) *errors.APIError {
+	apiErr := &errors.APIError{
+		StatusCode: resp.StatusCode,
+		Message:    http.StatusText(resp.StatusCode),
+	}
+
+	var payload apiErrorResponse
+	if err := json.Unmarshal(body, &payload); err == nil {
+		if payload.Error.Message != "" {
+			apiErr.Message = payload.Error.Message
+		}
+		if len(payload.Error.Errors) > 0 {
+			apiErr.Reason = payload.Error.Errors[0].Reason
+		}
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+
+	return apiErr
+}
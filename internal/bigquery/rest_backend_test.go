@@ -0,0 +1,390 @@
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bqs/internal/auth"
+	"bqs/internal/cache"
+	"bqs/internal/errors"
+)
+
+// newTestRESTServer stands in for the BigQuery v2 API
+func newTestRESTServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/demo-project/datasets/analytics/tables", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("maxResults") == "" {
+			t.Error("Expected maxResults on tables.list")
+		}
+		if r.URL.Query().Get("pageToken") == "" {
+			w.Write([]byte(`{
+				"tables": [{"tableReference": {"projectId": "demo-project", "datasetId": "analytics", "tableId": "events"}, "type": "TABLE", "creationTime": "1733047800000"}],
+				"nextPageToken": "page-2",
+				"totalItems": 2
+			}`))
+			return
+		}
+		w.Write([]byte(`{
+			"tables": [{"tableReference": {"projectId": "demo-project", "datasetId": "analytics", "tableId": "daily_users"}, "type": "VIEW", "creationTime": "1733150520000"}],
+			"totalItems": 2
+		}`))
+	})
+	mux.HandleFunc("/projects/demo-project/datasets/analytics/tables/events", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fields") == "schema,etag" {
+			w.Write([]byte(`{"schema": {"fields": [{"name": "event_id", "type": "STRING", "mode": "REQUIRED"}]}, "etag": "\"events-v2\""}`))
+			return
+		}
+		if r.URL.Query().Get("fields") == "etag" {
+			if r.Header.Get("If-None-Match") == `"events-v2"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Write([]byte(`{"etag": "\"events-v2\""}`))
+			return
+		}
+		w.Write([]byte(`{
+			"tableReference": {"projectId": "demo-project", "datasetId": "analytics", "tableId": "events"},
+			"etag": "\"events-v2\"",
+			"type": "TABLE",
+			"numRows": "42",
+			"numBytes": "1024",
+			"schema": {"fields": [{"name": "event_id", "type": "STRING", "mode": "REQUIRED"}]}
+		}`))
+	})
+	mux.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("pageToken") == "" {
+			w.Write([]byte(`{"projects": [{"projectReference": {"projectId": "demo-project"}, "friendlyName": "Demo"}], "nextPageToken": "page-2"}`))
+			return
+		}
+		w.Write([]byte(`{"projects": [{"projectReference": {"projectId": "other-project"}}]}`))
+	})
+	mux.HandleFunc("/projects/demo-project/datasets", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"datasets": [{"datasetReference": {"projectId": "demo-project", "datasetId": "analytics"}, "location": "US"}]}`))
+	})
+	mux.HandleFunc("/projects/demo-project/datasets/analytics", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"creationTime": "1733047200000", "defaultTableExpirationMs": "86400000", "labels": {"team": "growth"}}`))
+	})
+	mux.HandleFunc("/projects/demo-project/datasets/analytics/tables/events/data", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("formatOptions.useInt64Timestamp") != "true" {
+			t.Error("Expected integer timestamps on tabledata.list")
+		}
+		w.Write([]byte(`{"totalRows": "42", "rows": [
+			{"f": [{"v": "e-1"}]},
+			{"f": [{"v": null}]}
+		]}`))
+	})
+	mux.HandleFunc("/projects/demo-project/queries", func(w http.ResponseWriter, r *http.Request) {
+		var req queryRequest
+		if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&req) != nil || req.UseLegacySQL {
+			t.Errorf("Expected a GoogleSQL jobs.query POST, got %s", r.Method)
+		}
+		switch {
+		case req.DryRun:
+			w.Write([]byte(`{"totalBytesProcessed": "1048576", "statementType": "SELECT", "jobComplete": true,
+				"schema": {"fields": [{"name": "ts", "type": "TIMESTAMP"}]}}`))
+			return
+		case req.Query == "SELECT ts FROM t":
+			if req.MaximumBytesBilled != 1<<30 || req.RequestID != "run-1" || req.FormatOptions == nil || req.MaxResults != 1 {
+				t.Errorf("Unexpected query request: %+v", req)
+			}
+			w.Write([]byte(`{"jobComplete": true, "totalBytesProcessed": "1048576", "cacheHit": true, "pageToken": "more",
+				"schema": {"fields": [{"name": "ts", "type": "TIMESTAMP"}]},
+				"rows": [{"f": [{"v": "1733047800000000"}]}]}`))
+			return
+		case req.Query == "SELECT broken":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"code": 400, "message": "Syntax error: Unexpected end of script", "errors": [{"reason": "invalidQuery"}]}}`))
+			return
+		case !strings.Contains(req.Query, "`demo-project.analytics.INFORMATION_SCHEMA.COLUMN_FIELD_PATHS`"):
+			t.Errorf("Unexpected query: %s", req.Query)
+		}
+		// Not finished within timeoutMs; the client has to poll getQueryResults
+		w.Write([]byte(`{"jobComplete": false, "jobReference": {"projectId": "demo-project", "jobId": "job-1", "location": "US"}}`))
+	})
+	mux.HandleFunc("/projects/demo-project/queries/job-1", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("location") != "US" {
+			t.Error("Expected location on getQueryResults")
+		}
+		fields := `"schema": {"fields": [{"name": "table_name"}, {"name": "table_type"}, {"name": "row_count"}, {"name": "columns"}]}`
+		if r.URL.Query().Get("pageToken") == "" {
+			w.Write([]byte(`{"jobComplete": true, ` + fields + `, "pageToken": "page-2", "rows": [
+				{"f": [{"v": "events"}, {"v": "BASE TABLE"}, {"v": "42"}, {"v": "[{\"field_path\":\"event_id\",\"data_type\":\"STRING\",\"is_nullable\":\"NO\",\"ordinal_position\":1}]"}]}
+			]}`))
+			return
+		}
+		w.Write([]byte(`{"jobComplete": true, ` + fields + `, "rows": [
+			{"f": [{"v": "daily_users"}, {"v": "VIEW"}, {"v": null}, {"v": null}]}
+		]}`))
+	})
+	mux.HandleFunc("/projects/demo-project/datasets/analytics/tables/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": {"code": 404, "message": "Not found: Table demo-project:analytics.missing", "errors": [{"reason": "notFound"}]}}`))
+	})
+	mux.HandleFunc("/projects/demo-project/datasets/analytics/tables/throttled", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error": {"code": 403, "message": "Exceeded rate limits", "errors": [{"reason": "rateLimitExceeded"}]}}`))
+	})
+	mux.HandleFunc("/projects/demo-project/datasets/analytics/tables/secret", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error": {"code": 403, "message": "Access Denied", "errors": [{"reason": "accessDenied"}]}}`))
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": {"code": 401, "message": "Invalid Credentials", "errors": [{"reason": "authError"}]}}`))
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRESTBackendListAndGet(t *testing.T) {
+	ctx := context.Background()
+	server := newTestRESTServer(t)
+	client := NewClientWithBackend(cache.NewMockService(), NewRESTBackend(server.URL, auth.StaticTokenSource("test-token")))
+
+	pages := 0
+	tables, err := client.ListTablesWithProgress(ctx, "demo-project", "analytics", func(loaded, total int) {
+		pages++
+		if total != 2 {
+			t.Errorf("Expected totalItems of 2, got %d", total)
+		}
+	})
+	if err != nil {
+		t.Fatalf("ListTables returned error: %v", err)
+	}
+	if pages != 2 {
+		t.Errorf("Expected 2 pages, got %d", pages)
+	}
+	if len(tables) != 2 || tables[0].TableID != "events" || tables[1].Type != "VIEW" {
+		t.Errorf("Unexpected tables: %+v", tables)
+	}
+
+	metadata, err := client.GetTableMetadata(ctx, "demo-project", "analytics", "events")
+	if err != nil {
+		t.Fatalf("GetTableMetadata returned error: %v", err)
+	}
+	if metadata.NumRows != 42 || metadata.Schema == nil || len(metadata.Schema.Fields) != 1 {
+		t.Errorf("Unexpected metadata: %+v", metadata)
+	}
+
+	schema, err := client.GetSchema(ctx, "demo-project", "analytics", "events")
+	if err != nil {
+		t.Fatalf("GetSchema returned error: %v", err)
+	}
+	if len(schema.Fields) != 1 || schema.Fields[0].Mode != "REQUIRED" {
+		t.Errorf("Unexpected schema: %+v", schema)
+	}
+
+	projects, err := NewRESTBackend(server.URL, auth.StaticTokenSource("test-token")).ListProjects(ctx)
+	if err != nil {
+		t.Fatalf("ListProjects returned error: %v", err)
+	}
+	if len(projects) != 2 || projects[0].FriendlyName != "Demo" || projects[1].ProjectReference.ProjectID != "other-project" {
+		t.Errorf("Unexpected projects: %+v", projects)
+	}
+
+	datasets, err := NewRESTBackend(server.URL, auth.StaticTokenSource("test-token")).ListDatasets(ctx, "demo-project")
+	if err != nil {
+		t.Fatalf("ListDatasets returned error: %v", err)
+	}
+	if len(datasets) != 1 || datasets[0].DatasetReference.DatasetID != "analytics" {
+		t.Errorf("Unexpected datasets: %+v", datasets)
+	}
+	datasetMetadata, err := NewRESTBackend(server.URL, auth.StaticTokenSource("test-token")).GetDatasetMetadata(ctx, "demo-project", "analytics")
+	if err != nil {
+		t.Fatalf("GetDatasetMetadata returned error: %v", err)
+	}
+	if datasetMetadata.Labels["team"] != "growth" || datasetMetadata.DefaultTableExpirationMs != 86400000 {
+		t.Errorf("Unexpected dataset metadata: %+v", datasetMetadata)
+	}
+
+	// Listing doesn't fan out to datasets.get or tables.list per dataset
+	if ds := datasets[0]; ds.CreationTime != 0 || ds.TableCount != nil {
+		t.Errorf("Expected list-level dataset fields only, got %+v", ds)
+	}
+
+	// The client fills in details already cached: the table list from above
+	// and the dataset metadata fetched here
+	if _, err := client.GetDatasetMetadata(ctx, "demo-project", "analytics"); err != nil {
+		t.Fatalf("GetDatasetMetadata returned error: %v", err)
+	}
+	datasets, err = client.ListDatasets(ctx, "demo-project")
+	if err != nil {
+		t.Fatalf("ListDatasets returned error: %v", err)
+	}
+	if ds := datasets[0]; ds.CreationTime != 1733047200000 || ds.DefaultTableExpirationMs != 86400000 ||
+		ds.Location != "US" || ds.TableCount == nil || *ds.TableCount != 2 {
+		t.Errorf("Expected dataset details to be filled in from the cache, got %+v", ds)
+	}
+}
+
+func TestRESTBackendTableChanged(t *testing.T) {
+	ctx := context.Background()
+	server := newTestRESTServer(t)
+	backend := NewRESTBackend(server.URL, auth.StaticTokenSource("test-token"))
+
+	metadata, err := backend.GetTableMetadata(ctx, "demo-project", "analytics", "events")
+	if err != nil {
+		t.Fatalf("GetTableMetadata returned error: %v", err)
+	}
+	if metadata.ETag != `"events-v2"` {
+		t.Fatalf("Expected the table's etag, got %q", metadata.ETag)
+	}
+
+	if changed, err := backend.TableChanged(ctx, "demo-project", "analytics", "events", metadata.ETag); err != nil || changed {
+		t.Errorf("TableChanged with the current etag returned %v, %v; want 304 Not Modified read as unchanged", changed, err)
+	}
+	if changed, err := backend.TableChanged(ctx, "demo-project", "analytics", "events", `"events-v1"`); err != nil || !changed {
+		t.Errorf("TableChanged with an old etag returned %v, %v; want changed", changed, err)
+	}
+	if _, err := backend.TableChanged(ctx, "demo-project", "analytics", "secret", `"secret-v1"`); err == nil {
+		t.Error("Expected TableChanged to fail for a table it can't read")
+	}
+}
+
+func TestRESTBackendListTableMetadata(t *testing.T) {
+	server := newTestRESTServer(t)
+	backend := NewRESTBackend(server.URL, auth.StaticTokenSource("test-token"))
+
+	tables, err := backend.ListTableMetadata(context.Background(), "demo-project", "analytics")
+	if err != nil {
+		t.Fatalf("ListTableMetadata returned error: %v", err)
+	}
+	if len(tables) != 2 {
+		t.Fatalf("Expected 2 tables across both result pages, got %d", len(tables))
+	}
+	if events := tables[0]; events.TableReference.TableID != "events" || events.Type != "TABLE" || events.NumRows != 42 ||
+		len(events.Schema.Fields) != 1 || events.Schema.Fields[0].Mode != "REQUIRED" {
+		t.Errorf("Unexpected events metadata: %+v", events)
+	}
+	if view := tables[1]; view.Type != "VIEW" || len(view.Schema.Fields) != 0 {
+		t.Errorf("Unexpected view metadata: %+v", view)
+	}
+}
+
+func TestRESTBackendListRows(t *testing.T) {
+	server := newTestRESTServer(t)
+	backend := NewRESTBackend(server.URL, auth.StaticTokenSource("test-token"))
+
+	rows, err := backend.ListRows(context.Background(), "demo-project", "analytics", "events", 10)
+	if err != nil {
+		t.Fatalf("ListRows returned error: %v", err)
+	}
+	if len(rows) != 2 || rows[0]["event_id"] != "e-1" || rows[1]["event_id"] != nil {
+		t.Errorf("Unexpected rows: %+v", rows)
+	}
+}
+
+func TestRESTBackendQueries(t *testing.T) {
+	ctx := context.Background()
+	server := newTestRESTServer(t)
+	backend := NewRESTBackend(server.URL, auth.StaticTokenSource("test-token"))
+
+	estimate, err := backend.DryRunQuery(ctx, "demo-project", "SELECT ts FROM t")
+	if err != nil {
+		t.Fatalf("DryRunQuery returned error: %v", err)
+	}
+	if estimate.TotalBytesProcessed != 1<<20 || estimate.StatementType != "SELECT" || len(estimate.Schema.Fields) != 1 {
+		t.Errorf("Unexpected estimate: %+v", estimate)
+	}
+
+	// One row is enough, so the second page is never requested
+	result, err := backend.RunQuery(ctx, "demo-project", "SELECT ts FROM t",
+		QueryOptions{MaxRows: 1, MaxBytesBilled: 1 << 30, RequestID: "run-1"})
+	if err != nil {
+		t.Fatalf("RunQuery returned error: %v", err)
+	}
+	if len(result.Rows) != 1 || result.Rows[0]["ts"] != "2024-12-01 10:10:00" || !result.CacheHit {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	client := NewClientWithBackend(cache.NewMockService(), backend)
+	_, err = client.RunQuery(ctx, "demo-project", "SELECT broken", QueryOptions{MaxRows: 1})
+	if bqsErr, ok := err.(*errors.BQSError); !ok || bqsErr.Retryable || !strings.Contains(bqsErr.Message, "Syntax error") {
+		t.Errorf("Expected a non-retryable query error, got %v", err)
+	}
+}
+
+func TestDecodeTableDataRow(t *testing.T) {
+	fields := []SchemaField{
+		{Name: "ts", Type: "TIMESTAMP"},
+		{Name: "tags", Type: "STRING", Mode: "REPEATED"},
+		{Name: "params", Type: "RECORD", Mode: "REPEATED", Fields: []SchemaField{
+			{Name: "key", Type: "STRING"},
+			{Name: "value", Type: "INTEGER"},
+		}},
+	}
+	var row interface{}
+	data := `{"f": [
+		{"v": "1733047800000000"},
+		{"v": [{"v": "a"}, {"v": "b"}]},
+		{"v": [{"v": {"f": [{"v": "page"}, {"v": "3"}]}}]}
+	]}`
+	if err := json.Unmarshal([]byte(data), &row); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"ts":     "2024-12-01 10:10:00",
+		"tags":   []interface{}{"a", "b"},
+		"params": []interface{}{map[string]interface{}{"key": "page", "value": "3"}},
+	}
+	if got := decodeTableDataRow(fields, row); !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeTableDataRow() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRESTBackendErrorClassification(t *testing.T) {
+	ctx := context.Background()
+	server := newTestRESTServer(t)
+	backend := NewRESTBackend(server.URL, auth.StaticTokenSource("test-token"))
+
+	tests := []struct {
+		table     string
+		errType   errors.ErrorType
+		retryable bool
+	}{
+		{"missing", errors.ErrorTypeNotFound, false},
+		{"throttled", errors.ErrorTypeQuota, true},
+		{"secret", errors.ErrorTypePermission, false},
+	}
+
+	for _, test := range tests {
+		_, err := backend.GetTableMetadata(ctx, "demo-project", "analytics", test.table)
+		if err == nil {
+			t.Fatalf("Expected error for %s", test.table)
+		}
+		bqsErr := errors.WrapBigQueryError(err, "get_metadata", "demo-project", "analytics", test.table)
+		if bqsErr.Type != test.errType {
+			t.Errorf("%s: expected error type %v, got %v (%v)", test.table, test.errType, bqsErr.Type, err)
+		}
+		if bqsErr.IsRetryable() != test.retryable {
+			t.Errorf("%s: expected retryable=%v", test.table, test.retryable)
+		}
+	}
+
+	// Retry-After is honoured for throttled requests
+	_, err := backend.GetTableMetadata(ctx, "demo-project", "analytics", "throttled")
+	if got := errors.WrapBigQueryError(err, "get_metadata", "demo-project", "analytics", "throttled").GetRetryAfter(); got.Seconds() != 7 {
+		t.Errorf("Expected Retry-After of 7s, got %v", got)
+	}
+
+	// Bad credentials are reported as auth errors
+	badBackend := NewRESTBackend(server.URL, auth.StaticTokenSource("wrong"))
+	_, err = badBackend.ListTablesPage(ctx, "demo-project", "analytics", "")
+	if bqsErr := errors.WrapBigQueryError(err, "list_tables", "demo-project", "analytics", ""); bqsErr.Type != errors.ErrorTypeAuth {
+		t.Errorf("Expected auth error, got %v", bqsErr.Type)
+	}
+}
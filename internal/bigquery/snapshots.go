@@ -0,0 +1,189 @@
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"bqs/internal/cache"
+	"bqs/internal/config"
+	"bqs/internal/errors"
+	"bqs/internal/retry"
+)
+
+// SnapshotDefinition is set on table snapshots: the table copied and the
+// point in time it was copied as of
+type SnapshotDefinition struct {
+	BaseTableReference TableReference `json:"baseTableReference"`
+	SnapshotTime       time.Time      `json:"snapshotTime"`
+}
+
+// CloneDefinition is set on table clones: the table cloned and the point in
+// time it was cloned as of
+type CloneDefinition struct {
+	BaseTableReference TableReference `json:"baseTableReference"`
+	CloneTime          time.Time      `json:"cloneTime"`
+}
+
+// TableSnapshot is a snapshot or clone of a base table
+type TableSnapshot struct {
+	Table     TableReference
+	BaseTable TableReference
+	Time      time.Time // Point in time of the base table the copy was taken as of
+	Clone     bool
+}
+
+// SnapshotInfo describes the table as a copy of its base table. ok is false
+// for tables that are neither snapshots nor clones.
+func (t *TableMetadata) SnapshotInfo() (TableSnapshot, bool) {
+	switch {
+	case t.Snapshot != nil:
+		return TableSnapshot{Table: t.TableReference, BaseTable: t.Snapshot.BaseTableReference, Time: t.Snapshot.SnapshotTime}, true
+	case t.Clone != nil:
+		return TableSnapshot{Table: t.TableReference, BaseTable: t.Clone.BaseTableReference, Time: t.Clone.CloneTime, Clone: true}, true
+	default:
+		return TableSnapshot{}, false
+	}
+}
+
+// ListSnapshots returns the snapshots and clones in a dataset, grouped by base
+// table and oldest first. It only looks at cached metadata, so it never runs
+// a billed query: copies not cached yet are left out until the tables are
+// fetched or the dataset's cache is warmed (WarmDatasetCache). tables.list
+// can't tell clones from tables, so every cached table is looked at.
+func (c *Client) ListSnapshots(ctx context.Context, project, dataset string) ([]TableSnapshot, error) {
+	infos, err := c.ListTables(ctx, project, dataset)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []TableSnapshot
+	for _, info := range infos {
+		metadata := c.cachedTableMetadata(project, dataset, info.TableID)
+		if metadata == nil {
+			continue
+		}
+		if snapshot, ok := metadata.SnapshotInfo(); ok {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		a, b := snapshots[i], snapshots[j]
+		if a.BaseTable != b.BaseTable {
+			return a.BaseTable.String() < b.BaseTable.String()
+		}
+		if !a.Time.Equal(b.Time) {
+			return a.Time.Before(b.Time)
+		}
+		return a.Table.TableID < b.Table.TableID
+	})
+	return snapshots, nil
+}
+
+// DatasetTables returns the metadata of every table in a dataset, from the
+// cache when all of it is cached and otherwise from one bulk query, which
+// caches the tables not cached yet
+func (c *Client) DatasetTables(ctx context.Context, project, dataset string) ([]TableMetadata, error) {
+	tables, err := c.cachedDatasetMetadata(ctx, project, dataset)
+	if err != nil {
+		return nil, err
+	}
+	if tables == nil {
+		if tables, _, err = c.bulkLoadTableMetadata(ctx, project, dataset); err != nil {
+			return nil, err
+		}
+	}
+	return tables, nil
+}
+
+// cachedTableMetadata returns a table's metadata if it is cached, without
+// revalidating or fetching it
+func (c *Client) cachedTableMetadata(project, dataset, table string) *TableMetadata {
+	entry, err := c.cache.Get(cache.MetadataKey(project, dataset, table))
+	if err != nil {
+		return nil
+	}
+	var metadata TableMetadata
+	if err := json.Unmarshal([]byte(entry.Data), &metadata); err != nil {
+		return nil
+	}
+	return &metadata
+}
+
+// cachedDatasetMetadata returns the cached metadata of every table in the
+// dataset, or nil if any of it isn't cached
+func (c *Client) cachedDatasetMetadata(ctx context.Context, project, dataset string) ([]TableMetadata, error) {
+	infos, err := c.ListTables(ctx, project, dataset)
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		if !c.IsTableMetadataCached(project, dataset, info.TableID) {
+			return nil, nil
+		}
+	}
+
+	tables := make([]TableMetadata, 0, len(infos))
+	for _, info := range infos {
+		metadata, err := c.GetTableMetadata(ctx, project, dataset, info.TableID)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, *metadata)
+	}
+	return tables, nil
+}
+
+// timeTravelQuery selects a table as of a point in time. The result isn't
+// meant to be run: a dry run of it reports the table's schema at that time.
+func timeTravelQuery(project, dataset, table string, at time.Time) string {
+	return fmt.Sprintf("SELECT * FROM %s FOR SYSTEM_TIME AS OF TIMESTAMP %s",
+		sqlIdent(project, dataset, table), sqlString(at.UTC().Format(time.RFC3339Nano)))
+}
+
+// GetSchemaAt retrieves a table's schema as it was at a point in time within
+// the time travel window. BigQuery has no API for past table metadata, so the
+// schema comes from a free dry run of a FOR SYSTEM_TIME AS OF query; field
+// descriptions aren't part of it.
+func (c *Client) GetSchemaAt(ctx context.Context, project, dataset, table string, at time.Time) (*Schema, error) {
+	now := time.Now()
+	if at.After(now) {
+		return nil, fmt.Errorf("%s is in the future", at.UTC().Format(time.RFC3339))
+	}
+	if now.Sub(at) > config.TimeTravelWindow {
+		return nil, fmt.Errorf("%s is outside the %d-day time travel window",
+			at.UTC().Format(time.RFC3339), int(config.TimeTravelWindow/(24*time.Hour)))
+	}
+
+	cacheKey := cache.SchemaAtKey(project, dataset, table, at)
+
+	// Try cache first
+	if entry, err := c.cache.Get(cacheKey); err == nil {
+		var schema Schema
+		if err := json.Unmarshal([]byte(entry.Data), &schema); err == nil {
+			return &schema, nil
+		}
+	}
+
+	// Cache miss, dry run with retry
+	return fetchShared(ctx, c, cacheKey, config.SchemaTTL, "schema", func() (*Schema, error) {
+		var estimate *QueryEstimate
+		err := retry.WithDefaultRetry(ctx, "get schema at time", func() error {
+			var fetchErr error
+			estimate, fetchErr = c.backend.DryRunQuery(ctx, project, timeTravelQuery(project, dataset, table, at))
+			if fetchErr != nil {
+				return errors.WrapBigQueryError(fetchErr, "get_schema", project, dataset, table)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if estimate.Schema == nil {
+			return &Schema{}, nil
+		}
+		return estimate.Schema, nil
+	})
+}
@@ -0,0 +1,185 @@
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"bqs/internal/cache"
+)
+
+func TestInformationSchemaSnapshotRows(t *testing.T) {
+	data := `[
+		{"table_name": "events_snap", "table_type": "SNAPSHOT", "creation_time": "1759276800000",
+		 "base_table_catalog": "p", "base_table_schema": "raw", "base_table_name": "events", "snapshot_time": "1759276800000"},
+		{"table_name": "events_dev", "table_type": "CLONE", "creation_time": "1759363200000",
+		 "base_table_catalog": "p", "base_table_schema": "raw", "base_table_name": "events", "snapshot_time": "1759363200000"},
+		{"table_name": "events", "table_type": "BASE TABLE", "creation_time": "1733047800000",
+		 "base_table_catalog": null, "base_table_schema": null, "base_table_name": null, "snapshot_time": null}
+	]`
+	var rows []informationSchemaRow
+	if err := json.Unmarshal([]byte(data), &rows); err != nil {
+		t.Fatalf("Failed to decode rows: %v", err)
+	}
+	tables, err := tableMetadataFromRows("p", "raw", rows)
+	if err != nil {
+		t.Fatalf("tableMetadataFromRows returned error: %v", err)
+	}
+
+	base := TableReference{ProjectID: "p", DatasetID: "raw", TableID: "events"}
+	snapshot, ok := tables[0].SnapshotInfo()
+	if !ok || snapshot.Clone || snapshot.BaseTable != base || !snapshot.Time.Equal(time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected snapshot: %+v", snapshot)
+	}
+	clone, ok := tables[1].SnapshotInfo()
+	if !ok || !clone.Clone || tables[1].Type != "TABLE" || clone.BaseTable != base {
+		t.Errorf("Unexpected clone: %+v (type %s)", clone, tables[1].Type)
+	}
+	if details := SnapshotDetails(&tables[1]); len(details) != 1 || details[0].Value != "p.raw.events as of 2025-10-02 00:00:00 UTC" {
+		t.Errorf("Unexpected clone details: %+v", details)
+	}
+	if _, ok := tables[2].SnapshotInfo(); ok {
+		t.Error("Expected a base table not to be a snapshot")
+	}
+}
+
+// writeTableFixture writes a table fixture under root/project/dataset
+func writeTableFixture(t *testing.T, root string, metadata *TableMetadata) {
+	t.Helper()
+	ref := metadata.TableReference
+	dir := filepath.Join(root, ref.ProjectID, ref.DatasetID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ref.TableID+".json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClientListSnapshots(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	base := TableReference{ProjectID: "p", DatasetID: "raw", TableID: "events"}
+	table := func(id string) *TableMetadata {
+		metadata := &TableMetadata{}
+		metadata.Type = "TABLE"
+		metadata.TableReference = TableReference{ProjectID: "p", DatasetID: "raw", TableID: id}
+		return metadata
+	}
+
+	writeTableFixture(t, root, table("events"))
+	late := table("events_late")
+	late.Type = "SNAPSHOT"
+	late.Snapshot = &SnapshotDefinition{BaseTableReference: base, SnapshotTime: time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC)}
+	writeTableFixture(t, root, late)
+	early := table("events_early")
+	early.Type = "SNAPSHOT"
+	early.Snapshot = &SnapshotDefinition{BaseTableReference: base, SnapshotTime: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)}
+	writeTableFixture(t, root, early)
+	dev := table("dev")
+	dev.Clone = &CloneDefinition{BaseTableReference: base, CloneTime: time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC)}
+	writeTableFixture(t, root, dev)
+
+	client := NewClientWithBackend(cache.NewMockService(), NewFixtureBackend(root))
+	list := func() []string {
+		t.Helper()
+		snapshots, err := client.ListSnapshots(ctx, "p", "raw")
+		if err != nil {
+			t.Fatalf("ListSnapshots returned error: %v", err)
+		}
+		var got []string
+		for _, s := range snapshots {
+			got = append(got, fmt.Sprintf("%s<-%s clone=%v", s.Table.TableID, s.BaseTable.TableID, s.Clone))
+		}
+		return got
+	}
+
+	// Only cached metadata is looked at: nothing yet, and nothing is loaded
+	if got := list(); len(got) != 0 {
+		t.Errorf("ListSnapshots() = %v with nothing cached, want none", got)
+	}
+	if client.IsTableMetadataCached("p", "raw", "dev") {
+		t.Fatal("Expected ListSnapshots not to load the dataset's metadata")
+	}
+
+	if _, err := client.GetTableMetadata(ctx, "p", "raw", "dev"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := list(), []string{"dev<-events clone=true"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("ListSnapshots() = %v, want %v", got, want)
+	}
+
+	// Warming the cache finds them all
+	if _, _, err := client.WarmDatasetCache(ctx, "p", "raw"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Join(root, "p", "raw")); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"events_early<-events clone=false", "events_late<-events clone=false", "dev<-events clone=true"}
+	if got := list(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("ListSnapshots() = %v, want %v", got, want)
+	}
+}
+
+func TestClientGetSchemaAt(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	at := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	queries := []queryFixture{{
+		Query:         timeTravelQuery("p", "raw", "events", at),
+		StatementType: "SELECT",
+		Schema:        &Schema{Fields: []SchemaField{{Name: "id", Type: "INTEGER", Mode: "NULLABLE"}}},
+	}}
+	data, err := json.Marshal(queries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "p"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	queryFile := filepath.Join(root, "p", "_queries.json")
+	if err := os.WriteFile(queryFile, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClientWithBackend(cache.NewMockService(), NewFixtureBackend(root))
+	schema, err := client.GetSchemaAt(ctx, "p", "raw", "events", at)
+	if err != nil {
+		t.Fatalf("GetSchemaAt returned error: %v", err)
+	}
+	if len(schema.Fields) != 1 || schema.Fields[0].Name != "id" {
+		t.Errorf("Unexpected schema: %+v", schema)
+	}
+
+	// Past schemas are served from the cache
+	if err := os.Remove(queryFile); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetSchemaAt(ctx, "p", "raw", "events", at); err != nil {
+		t.Errorf("Expected a cached schema, got %v", err)
+	}
+
+	for _, outside := range []time.Time{time.Now().Add(time.Hour), time.Now().Add(-8 * 24 * time.Hour)} {
+		if _, err := client.GetSchemaAt(ctx, "p", "raw", "events", outside); err == nil {
+			t.Errorf("Expected an error for %s, outside the time travel window", outside)
+		}
+	}
+
+	// Refreshing the table drops its past schemas too
+	if err := client.InvalidateCache("p", "raw", "events"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetSchemaAt(ctx, "p", "raw", "events", at); err == nil {
+		t.Error("Expected the past schema to be fetched again after InvalidateCache")
+	}
+}
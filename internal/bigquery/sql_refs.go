@@ -0,0 +1,226 @@
+package bigquery
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"bqs/internal/utils"
+)
+
+// sqlLexemeKind classifies the lexemes the lineage parser works on
+type sqlLexemeKind int
+
+const (
+	lexWord    sqlLexemeKind = iota // Unquoted identifier or non-reserved word
+	lexKeyword                      // Reserved word, upper-cased in text
+	lexQuoted                       // Backtick-quoted identifier, quotes removed
+	lexPunct                        // Single punctuation character
+	lexLiteral                      // String or number
+)
+
+// sqlLexeme is one significant token of a query; whitespace and comments are dropped
+type sqlLexeme struct {
+	kind sqlLexemeKind
+	text string
+}
+
+// is reports whether the lexeme is the given keyword or punctuation
+func (l sqlLexeme) is(text string) bool {
+	return (l.kind == lexKeyword || l.kind == lexPunct) && l.text == text
+}
+
+// lexSQL splits a query into lexemes, building on the highlighter's tokenizer
+func lexSQL(sql string) []sqlLexeme {
+	var lexemes []sqlLexeme
+	for _, token := range utils.TokenizeSQL(sql) {
+		switch token.Kind {
+		case utils.SQLComment:
+		case utils.SQLKeyword:
+			lexemes = append(lexemes, sqlLexeme{lexKeyword, strings.ToUpper(token.Text)})
+		case utils.SQLQuotedIdentifier:
+			text := strings.TrimSuffix(strings.TrimPrefix(token.Text, "`"), "`")
+			lexemes = append(lexemes, sqlLexeme{lexQuoted, text})
+		case utils.SQLString, utils.SQLNumber:
+			lexemes = append(lexemes, sqlLexeme{lexLiteral, token.Text})
+		default:
+			// Plain text runs merge identifiers, punctuation and whitespace
+			runes := []rune(token.Text)
+			for i := 0; i < len(runes); {
+				start := i
+				switch r := runes[i]; {
+				case unicode.IsSpace(r):
+					i++
+					continue
+				case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+					for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+						i++
+					}
+					lexemes = append(lexemes, sqlLexeme{lexWord, string(runes[start:i])})
+				default:
+					i++
+					lexemes = append(lexemes, sqlLexeme{lexPunct, string(r)})
+				}
+			}
+		}
+	}
+	return lexemes
+}
+
+// isIdentifier reports whether the lexeme can be part of a name
+func (l sqlLexeme) isIdentifier() bool {
+	return l.kind == lexWord || l.kind == lexQuoted
+}
+
+// parsePath reads a dotted name such as `p.d.t`, p.d.t or my-project.d.t
+// starting at i. Unquoted project IDs may contain dashes. It returns the name
+// parts and the index just past the path.
+func parsePath(lexemes []sqlLexeme, i int) ([]string, int) {
+	var parts []string
+	for i < len(lexemes) && lexemes[i].isIdentifier() {
+		part := lexemes[i].text
+		i++
+		// Unquoted dashed project IDs lex as word - word. A numeric segment
+		// followed by a dot lexes as a number that swallows the dot.
+		dotted := false
+		for len(parts) == 0 && !dotted && lexemes[i-1].kind == lexWord && i+1 < len(lexemes) &&
+			lexemes[i].is("-") && (lexemes[i+1].kind == lexWord || lexemes[i+1].kind == lexLiteral) {
+			segment := lexemes[i+1].text
+			if lexemes[i+1].kind == lexLiteral && strings.HasSuffix(segment, ".") {
+				segment = strings.TrimSuffix(segment, ".")
+				dotted = true
+			}
+			part += "-" + segment
+			i += 2
+		}
+		// Wildcard tables end in *
+		if i < len(lexemes) && lexemes[i].is("*") && len(parts) > 0 {
+			part += "*"
+			i++
+		}
+		if lexemes[i-1].kind == lexQuoted {
+			parts = append(parts, strings.Split(part, ".")...)
+		} else {
+			parts = append(parts, part)
+		}
+		if dotted && i < len(lexemes) && lexemes[i].isIdentifier() {
+			continue
+		}
+		if i+1 < len(lexemes) && lexemes[i].is(".") && lexemes[i+1].isIdentifier() {
+			i++
+			continue
+		}
+		break
+	}
+	return parts, i
+}
+
+// ExtractTableReferences returns the tables a GoogleSQL query reads from,
+// sorted and without duplicates. Names with only a dataset and table are
+// resolved against defaultProject. CTEs, UNNEST, subqueries, table functions
+// and INFORMATION_SCHEMA views are not tables and are skipped.
+func ExtractTableReferences(sql, defaultProject string) []TableReference {
+	lexemes := lexSQL(sql)
+
+	// CTE names (name AS ( ...) and table aliases shadow table names
+	local := map[string]bool{}
+	for i := 0; i+2 < len(lexemes); i++ {
+		if lexemes[i].isIdentifier() && lexemes[i+1].is("AS") && lexemes[i+2].is("(") {
+			local[strings.ToLower(lexemes[i].text)] = true
+		}
+	}
+
+	seen := map[string]bool{}
+	var refs []TableReference
+	// openers[d] is the lexeme before the paren that opened depth d+1
+	var openers []sqlLexeme
+	for i := 0; i < len(lexemes); i++ {
+		lexeme := lexemes[i]
+		switch {
+		case lexeme.is("("):
+			opener := sqlLexeme{}
+			if i > 0 {
+				opener = lexemes[i-1]
+			}
+			openers = append(openers, opener)
+			continue
+		case lexeme.is(")"):
+			if len(openers) > 0 {
+				openers = openers[:len(openers)-1]
+			}
+			continue
+		case !lexeme.is("FROM") && !lexeme.is("JOIN"):
+			continue
+		}
+
+		// EXTRACT(part FROM x) and IS DISTINCT FROM aren't table sources
+		if lexeme.is("FROM") {
+			if i > 0 && lexemes[i-1].is("DISTINCT") {
+				continue
+			}
+			if len(openers) > 0 && openers[len(openers)-1].is("EXTRACT") {
+				continue
+			}
+		}
+
+		// A FROM clause lists one or more sources separated by commas
+		for j := i + 1; j < len(lexemes); {
+			parts, next := parsePath(lexemes, j)
+			if len(parts) == 0 {
+				break
+			}
+			isFunction := next < len(lexemes) && lexemes[next].is("(")
+			if ref, ok := tableReference(parts, defaultProject, local); ok && !isFunction {
+				if id := ref.String(); !seen[id] {
+					seen[id] = true
+					refs = append(refs, ref)
+				}
+			}
+
+			// Skip FOR SYSTEM_TIME AS OF and the alias, remembering the alias
+			j = next
+			if j < len(lexemes) && lexemes[j].is("AS") {
+				j++
+			}
+			if j < len(lexemes) && lexemes[j].kind == lexWord {
+				local[strings.ToLower(lexemes[j].text)] = true
+				j++
+			}
+			if j+1 < len(lexemes) && lexemes[j].is(",") && lexemes[j+1].isIdentifier() {
+				j++
+				continue
+			}
+			break
+		}
+	}
+
+	sort.Slice(refs, func(i, j int) bool { return refs[i].String() < refs[j].String() })
+	return refs
+}
+
+// tableReference resolves name parts to a table, skipping local names and
+// INFORMATION_SCHEMA
+func tableReference(parts []string, defaultProject string, local map[string]bool) (TableReference, bool) {
+	for _, part := range parts {
+		if strings.EqualFold(part, "INFORMATION_SCHEMA") {
+			return TableReference{}, false
+		}
+	}
+	if local[strings.ToLower(parts[0])] {
+		return TableReference{}, false
+	}
+
+	switch len(parts) {
+	case 2:
+		return TableReference{ProjectID: defaultProject, DatasetID: parts[0], TableID: parts[1]}, true
+	case 3:
+		return TableReference{ProjectID: parts[0], DatasetID: parts[1], TableID: parts[2]}, true
+	default:
+		return TableReference{}, false
+	}
+}
+
+// String returns the project.dataset.table form of the reference
+func (r TableReference) String() string {
+	return r.ProjectID + "." + r.DatasetID + "." + r.TableID
+}
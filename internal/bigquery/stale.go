@@ -0,0 +1,214 @@
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"bqs/internal/cache"
+	"bqs/internal/config"
+	"bqs/internal/errors"
+	"bqs/internal/retry"
+)
+
+// TableReads counts the jobs that read one table, as a row of the reads
+// query in the form bq query --format=json prints
+type TableReads struct {
+	TableID  string `json:"table_id"`
+	Reads    int64  `json:"reads,string"`
+	LastRead int64  `json:"last_read,string"` // Unix milliseconds
+}
+
+// tableReadsQuery counts the jobs of the last days that read each table of a
+// dataset. Jobs writing a table don't count as reading it, even though DML
+// lists its target among the referenced tables.
+func tableReadsQuery(project, location, dataset string, days int) string {
+	return fmt.Sprintf(`SELECT
+  r.table_id,
+  COUNT(*) AS reads,
+  UNIX_MILLIS(MAX(j.creation_time)) AS last_read
+FROM %s AS j, UNNEST(j.referenced_tables) AS r
+WHERE j.creation_time >= TIMESTAMP_SUB(CURRENT_TIMESTAMP(), INTERVAL %d DAY)
+  AND r.project_id = %s AND r.dataset_id = %s
+  AND NOT IFNULL(j.destination_table.project_id = r.project_id
+    AND j.destination_table.dataset_id = r.dataset_id
+    AND j.destination_table.table_id = r.table_id, FALSE)
+GROUP BY r.table_id
+ORDER BY r.table_id`, sqlIdent(project, "region-"+location, "INFORMATION_SCHEMA.JOBS_BY_PROJECT"), days,
+		sqlString(project), sqlString(dataset))
+}
+
+// ListTableReads retrieves how often and when each table of a dataset was
+// last read in the last days, with caching and retry logic. Tables not read
+// are left out. Like TableJobs it needs bigquery.jobs.listAll.
+func (c *Client) ListTableReads(ctx context.Context, project, dataset string, days int) ([]TableReads, error) {
+	cacheKey := cache.TableReadsKey(project, dataset, days)
+
+	// Try cache first
+	if entry, err := c.cache.Get(cacheKey); err == nil {
+		var reads []TableReads
+		if err := json.Unmarshal([]byte(entry.Data), &reads); err == nil {
+			return reads, nil
+		}
+	}
+
+	// Cache miss, fetch from BigQuery with retry
+	return fetchShared(ctx, c, cacheKey, config.JobHistoryTTL, "table reads", func() ([]TableReads, error) {
+		// The jobs view is regional, so the dataset's location is needed first
+		location, err := c.datasetLocation(ctx, project, dataset)
+		if err != nil {
+			return nil, err
+		}
+
+		var reads []TableReads
+		err = retry.WithDefaultRetry(ctx, "list table reads", func() error {
+			var fetchErr error
+			reads, fetchErr = c.backend.ListTableReads(ctx, project, location, dataset, days)
+			if fetchErr != nil {
+				return errors.WrapBigQueryError(fetchErr, "list_jobs", project, dataset, "")
+			}
+			return nil
+		})
+		return reads, err
+	})
+}
+
+// StaleCriteria selects tables that look unused. A zero duration ignores
+// that criterion.
+type StaleCriteria struct {
+	Unmodified time.Duration // Not modified for at least this long
+	Unqueried  time.Duration // Not read for at least this long, by job history
+	MinSize    int64         // Logical bytes
+}
+
+// DefaultStaleCriteria are the criteria of the browser's stale table toggle
+func DefaultStaleCriteria() StaleCriteria {
+	return StaleCriteria{Unmodified: config.StaleUnmodified, Unqueried: config.StaleUnqueried}
+}
+
+// history is the job history needed to judge the unqueried criterion,
+// capped at what JOBS_BY_PROJECT retains
+func (c StaleCriteria) history() time.Duration {
+	if c.Unqueried > config.JobHistoryRetention {
+		return config.JobHistoryRetention
+	}
+	return c.Unqueried
+}
+
+// historyDays is history in whole days
+func (c StaleCriteria) historyDays() int {
+	return int(math.Ceil(c.history().Hours() / 24))
+}
+
+// StaleTable is a table matching the stale criteria
+type StaleTable struct {
+	TableInfo
+	LastRead   int64 // Unix milliseconds, 0 if not read in the job history looked at
+	ReadsKnown bool  // Job history was available
+}
+
+// StorageCost estimates the table's monthly storage cost in USD under the
+// logical billing model. tables.get reports long-term bytes; otherwise a
+// table unmodified long enough is assumed to be entirely long-term.
+func (t StaleTable) StorageCost(now time.Time) float64 {
+	longTerm := t.NumLongTermBytes
+	if longTerm == 0 && t.LastModifiedTime > 0 && now.Sub(time.UnixMilli(t.LastModifiedTime)) >= config.LongTermStorageAge {
+		longTerm = t.NumBytes
+	}
+	return StorageBytes{ActiveLogicalBytes: t.NumBytes - longTerm, LongTermLogicalBytes: longTerm}.LogicalCost()
+}
+
+// StaleReport is the outcome of looking for stale tables in a dataset
+type StaleReport struct {
+	Tables     []StaleTable  // Largest first
+	Checked    int           // Tables with storage that were looked at
+	HistoryErr error         // Why job history couldn't be used, if it couldn't
+	HistoryCap time.Duration // The job history looked at, when shorter than criteria.Unqueried
+}
+
+// FindStaleTables looks for tables in a dataset matching the criteria. Table
+// metadata comes from the cache, or from one bulk query when any of it isn't
+// cached. When the caller lacks access to job history the unqueried
+// criterion is skipped and the reason reported in HistoryErr. JOBS_BY_PROJECT
+// only reaches back config.JobHistoryRetention; a longer Unqueried is judged
+// by that much history, reported in HistoryCap, so tables last read before it
+// count as unqueried.
+func (c *Client) FindStaleTables(ctx context.Context, project, dataset string, criteria StaleCriteria, now time.Time) (*StaleReport, error) {
+	tables, err := c.DatasetTables(ctx, project, dataset)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]TableInfo, len(tables))
+	for i := range tables {
+		infos[i] = tables[i].TableInfo
+	}
+
+	var lastReads map[string]int64
+	var historyErr error
+	var historyCap time.Duration
+	if criteria.Unqueried > 0 {
+		if history := criteria.history(); history < criteria.Unqueried {
+			historyCap = history
+		}
+		reads, err := c.ListTableReads(ctx, project, dataset, criteria.historyDays())
+		if bqsErr, ok := err.(*errors.BQSError); ok && bqsErr.Type == errors.ErrorTypePermission {
+			historyErr = err
+		} else if err != nil {
+			return nil, err
+		} else {
+			lastReads = make(map[string]int64, len(reads))
+			for _, r := range reads {
+				lastReads[r.TableID] = r.LastRead
+			}
+		}
+	}
+
+	stale, checked := MatchStaleTables(infos, lastReads, criteria, now)
+	return &StaleReport{Tables: stale, Checked: checked, HistoryErr: historyErr, HistoryCap: historyCap}, nil
+}
+
+// MatchStaleTables returns the tables matching the criteria, largest first,
+// and the number of tables with storage looked at. lastReads maps table IDs
+// to their last read; nil means job history is unavailable and the
+// unqueried criterion can't be judged. Views and external tables have no
+// storage to clean up and are skipped.
+func MatchStaleTables(tables []TableInfo, lastReads map[string]int64, criteria StaleCriteria, now time.Time) ([]StaleTable, int) {
+	var stale []StaleTable
+	checked := 0
+	for _, t := range tables {
+		if t.Type == "VIEW" || t.Type == "EXTERNAL" {
+			continue
+		}
+		checked++
+		if t.TableID == "" {
+			t.TableID = t.TableReference.TableID
+		}
+
+		if t.NumBytes < criteria.MinSize {
+			continue
+		}
+		if criteria.Unmodified > 0 {
+			// Unknown modification times don't make a table stale
+			if t.LastModifiedTime == 0 || now.Sub(time.UnixMilli(t.LastModifiedTime)) < criteria.Unmodified {
+				continue
+			}
+		}
+		lastRead := lastReads[t.TableID]
+		if criteria.Unqueried > 0 && lastReads != nil && lastRead > 0 &&
+			now.Sub(time.UnixMilli(lastRead)) < criteria.Unqueried {
+			continue
+		}
+		stale = append(stale, StaleTable{TableInfo: t, LastRead: lastRead, ReadsKnown: lastReads != nil})
+	}
+
+	sort.SliceStable(stale, func(i, j int) bool {
+		if stale[i].NumBytes != stale[j].NumBytes {
+			return stale[i].NumBytes > stale[j].NumBytes
+		}
+		return stale[i].TableID < stale[j].TableID
+	})
+	return stale, checked
+}
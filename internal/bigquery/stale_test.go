@@ -0,0 +1,136 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"bqs/internal/cache"
+	"bqs/internal/config"
+)
+
+func TestMatchStaleTables(t *testing.T) {
+	now := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) int64 {
+		return now.AddDate(0, 0, -days).UnixMilli()
+	}
+	table := func(id, kind string, modifiedDaysAgo int, bytes int64) TableInfo {
+		return TableInfo{TableID: id, Type: kind, LastModifiedTime: daysAgo(modifiedDaysAgo), NumBytes: bytes}
+	}
+	tables := []TableInfo{
+		table("fresh", "TABLE", 10, 500),
+		table("old_small", "TABLE", 400, 10),
+		table("old_read", "TABLE", 400, 300),
+		table("old_unread", "TABLE", 400, 200),
+		table("old_view", "VIEW", 400, 0),
+		table("old_mv", "MATERIALIZED_VIEW", 200, 100),
+		{TableID: "unknown", Type: "TABLE", NumBytes: 1000},
+	}
+	lastReads := map[string]int64{"old_read": daysAgo(5), "old_mv": daysAgo(120)}
+	criteria := StaleCriteria{Unmodified: 180 * 24 * time.Hour, Unqueried: 90 * 24 * time.Hour, MinSize: 50}
+
+	ids := func(stale []StaleTable) string {
+		var ids []string
+		for _, s := range stale {
+			ids = append(ids, s.TableID)
+		}
+		return fmt.Sprint(ids)
+	}
+
+	stale, checked := MatchStaleTables(tables, lastReads, criteria, now)
+	if got := ids(stale); got != "[old_unread old_mv]" || checked != 6 {
+		t.Errorf("MatchStaleTables() = %s, %d checked, want [old_unread old_mv], 6 checked", got, checked)
+	}
+	if !stale[1].ReadsKnown || stale[1].LastRead != daysAgo(120) {
+		t.Errorf("Expected old_mv's last read to be kept: %+v", stale[1])
+	}
+
+	// Without job history only modification and size count
+	stale, _ = MatchStaleTables(tables, nil, criteria, now)
+	if got := ids(stale); got != "[old_read old_unread old_mv]" || stale[0].ReadsKnown {
+		t.Errorf("MatchStaleTables(no history) = %s, want [old_read old_unread old_mv]", got)
+	}
+
+	// Zero criteria are ignored
+	stale, _ = MatchStaleTables(tables, lastReads, StaleCriteria{}, now)
+	if got := ids(stale); got != "[unknown fresh old_read old_unread old_mv old_small]" {
+		t.Errorf("MatchStaleTables(no criteria) = %s", got)
+	}
+}
+
+func TestStaleTableStorageCost(t *testing.T) {
+	const gib = 1 << 30
+	now := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	recent := StaleTable{TableInfo: TableInfo{NumBytes: 100 * gib, LastModifiedTime: now.AddDate(0, 0, -10).UnixMilli()}}
+	if cost := recent.StorageCost(now); math.Abs(cost-2.0) > 1e-9 {
+		t.Errorf("StorageCost(recent) = %f, want 2.00", cost)
+	}
+	// Unmodified for 90 days, all of it is long-term
+	old := StaleTable{TableInfo: TableInfo{NumBytes: 100 * gib, LastModifiedTime: now.AddDate(0, 0, -100).UnixMilli()}}
+	if cost := old.StorageCost(now); math.Abs(cost-1.0) > 1e-9 {
+		t.Errorf("StorageCost(old) = %f, want 1.00", cost)
+	}
+	// Reported long-term bytes win
+	partial := StaleTable{TableInfo: TableInfo{NumBytes: 100 * gib, NumLongTermBytes: 40 * gib, LastModifiedTime: now.AddDate(0, 0, -100).UnixMilli()}}
+	if cost := partial.StorageCost(now); math.Abs(cost-1.6) > 1e-9 {
+		t.Errorf("StorageCost(partial) = %f, want 1.60", cost)
+	}
+}
+
+func TestClientFindStaleTables(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	now := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	for _, id := range []string{"read", "unread"} {
+		metadata := &TableMetadata{}
+		metadata.Type = "TABLE"
+		metadata.TableReference = TableReference{ProjectID: "p", DatasetID: "raw", TableID: id}
+		metadata.LastModifiedTime = now.AddDate(-1, 0, 0).UnixMilli()
+		metadata.NumBytes = 100
+		writeTableFixture(t, root, metadata)
+	}
+	if err := os.WriteFile(filepath.Join(root, "p", "raw.json"), []byte(`{"location": "US"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	jobsDir := filepath.Join(root, "p", "raw", "_jobs")
+	if err := os.MkdirAll(jobsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := fmt.Sprintf(`[
+		{"job_id": "j1", "user_email": "a@x", "job_type": "QUERY", "creation_time": "%d", "total_bytes_processed": "1", "failed": "false", "wrote": "false"},
+		{"job_id": "j2", "user_email": "a@x", "job_type": "QUERY", "creation_time": "%d", "total_bytes_processed": "1", "failed": "false", "wrote": "true"}]`,
+		now.AddDate(0, 0, -3).UnixMilli(), now.AddDate(0, 0, -1).UnixMilli())
+	if err := os.WriteFile(filepath.Join(jobsDir, "read.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Only written, never read
+	if err := os.WriteFile(filepath.Join(jobsDir, "unread.json"), []byte(`[{"job_id": "j3", "creation_time": "1", "total_bytes_processed": "1", "failed": "false", "wrote": "true"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClientWithBackend(cache.NewMockService(), NewFixtureBackend(root))
+	report, err := client.FindStaleTables(ctx, "p", "raw", DefaultStaleCriteria(), now)
+	if err != nil {
+		t.Fatalf("FindStaleTables returned error: %v", err)
+	}
+	if len(report.Tables) != 1 || report.Tables[0].TableID != "unread" || report.Checked != 2 || report.HistoryErr != nil || report.HistoryCap != 0 {
+		t.Errorf("FindStaleTables() = %+v, want only unread of 2", report)
+	}
+
+	// More history than JOBS_BY_PROJECT keeps is capped, and the cap reported
+	criteria := DefaultStaleCriteria()
+	criteria.Unqueried = config.JobHistoryRetention + 30*24*time.Hour
+	report, err = client.FindStaleTables(ctx, "p", "raw", criteria, now)
+	if err != nil || report.HistoryCap != config.JobHistoryRetention {
+		t.Errorf("FindStaleTables() = %+v, %v; want the history capped at %s", report, err, config.JobHistoryRetention)
+	}
+
+	reads, err := client.ListTableReads(ctx, "p", "raw", 90)
+	if err != nil || len(reads) != 1 || reads[0] != (TableReads{TableID: "read", Reads: 1, LastRead: now.AddDate(0, 0, -3).UnixMilli()}) {
+		t.Errorf("ListTableReads() = %+v, %v", reads, err)
+	}
+}
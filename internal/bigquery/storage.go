@@ -0,0 +1,216 @@
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"bqs/internal/cache"
+	"bqs/internal/config"
+	"bqs/internal/errors"
+	"bqs/internal/retry"
+)
+
+// StorageBytes counts the storage of a table, or the sum over many. Active
+// physical bytes include time travel bytes; fail-safe bytes are counted apart.
+type StorageBytes struct {
+	ActiveLogicalBytes      int64 `json:"active_logical_bytes,string"`
+	LongTermLogicalBytes    int64 `json:"long_term_logical_bytes,string"`
+	ActivePhysicalBytes     int64 `json:"active_physical_bytes,string"`
+	LongTermPhysicalBytes   int64 `json:"long_term_physical_bytes,string"`
+	TimeTravelPhysicalBytes int64 `json:"time_travel_physical_bytes,string"`
+	FailSafePhysicalBytes   int64 `json:"fail_safe_physical_bytes,string"`
+}
+
+// TableStorage is one table's row of INFORMATION_SCHEMA.TABLE_STORAGE, in the
+// form bq query --format=json prints
+type TableStorage struct {
+	DatasetID string `json:"table_schema"`
+	TableID   string `json:"table_name"`
+	TotalRows int64  `json:"total_rows,string"`
+	Deleted   bool   `json:"deleted,string"` // Dropped, but still held for time travel or fail-safe
+	StorageBytes
+}
+
+// Add sums another count into b
+func (b *StorageBytes) Add(other StorageBytes) {
+	b.ActiveLogicalBytes += other.ActiveLogicalBytes
+	b.LongTermLogicalBytes += other.LongTermLogicalBytes
+	b.ActivePhysicalBytes += other.ActivePhysicalBytes
+	b.LongTermPhysicalBytes += other.LongTermPhysicalBytes
+	b.TimeTravelPhysicalBytes += other.TimeTravelPhysicalBytes
+	b.FailSafePhysicalBytes += other.FailSafePhysicalBytes
+}
+
+// LogicalBytes is the uncompressed size, active and long-term
+func (b StorageBytes) LogicalBytes() int64 {
+	return b.ActiveLogicalBytes + b.LongTermLogicalBytes
+}
+
+// PhysicalBytes is the compressed size, active and long-term, including time
+// travel but not fail-safe bytes, like TABLE_STORAGE's total_physical_bytes
+func (b StorageBytes) PhysicalBytes() int64 {
+	return b.ActivePhysicalBytes + b.LongTermPhysicalBytes
+}
+
+// LogicalCost estimates the monthly storage cost in USD under the logical
+// billing model, where time travel and fail-safe storage are free
+func (b StorageBytes) LogicalCost() float64 {
+	return gib(b.ActiveLogicalBytes)*config.LogicalActivePricePerGiB +
+		gib(b.LongTermLogicalBytes)*config.LogicalLongTermPricePerGiB
+}
+
+// PhysicalCost estimates the monthly storage cost in USD under the physical
+// billing model, which charges time travel and fail-safe bytes as active
+func (b StorageBytes) PhysicalCost() float64 {
+	return gib(b.ActivePhysicalBytes+b.FailSafePhysicalBytes)*config.PhysicalActivePricePerGiB +
+		gib(b.LongTermPhysicalBytes)*config.PhysicalLongTermPricePerGiB
+}
+
+func gib(bytes int64) float64 {
+	return float64(bytes) / (1 << 30)
+}
+
+// tableStorageQuery lists the storage of every table of a project in one
+// region, given in lower case. TABLE_STORAGE only exists as a regional view.
+func tableStorageQuery(project, location string) string {
+	return fmt.Sprintf(`SELECT
+  table_schema,
+  table_name,
+  total_rows,
+  active_logical_bytes,
+  long_term_logical_bytes,
+  active_physical_bytes,
+  long_term_physical_bytes,
+  time_travel_physical_bytes,
+  fail_safe_physical_bytes,
+  deleted
+FROM %s
+ORDER BY table_schema, table_name`, sqlIdent(project, "region-"+location, "INFORMATION_SCHEMA.TABLE_STORAGE"))
+}
+
+// ListTableStorage retrieves the storage of every table in a project, or in
+// one dataset when dataset isn't empty, from the TABLE_STORAGE views of the
+// regions the datasets live in. Each region is cached separately.
+func (c *Client) ListTableStorage(ctx context.Context, project, dataset string) ([]TableStorage, error) {
+	var locations []string
+	if dataset != "" {
+		location, err := c.datasetLocation(ctx, project, dataset)
+		if err != nil {
+			return nil, err
+		}
+		locations = append(locations, location)
+	} else {
+		datasets, err := c.ListDatasets(ctx, project)
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool)
+		for _, ds := range datasets {
+			location := strings.ToLower(ds.Location)
+			if location != "" && !seen[location] {
+				seen[location] = true
+				locations = append(locations, location)
+			}
+		}
+	}
+
+	tables := []TableStorage{}
+	for _, location := range locations {
+		regional, err := c.regionTableStorage(ctx, project, location)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range regional {
+			if dataset == "" || t.DatasetID == dataset {
+				tables = append(tables, t)
+			}
+		}
+	}
+
+	sort.SliceStable(tables, func(i, j int) bool {
+		if tables[i].DatasetID != tables[j].DatasetID {
+			return tables[i].DatasetID < tables[j].DatasetID
+		}
+		return tables[i].TableID < tables[j].TableID
+	})
+	return tables, nil
+}
+
+// regionTableStorage retrieves the TABLE_STORAGE rows of a project in one
+// region, given in lower case, with caching and retry logic
+func (c *Client) regionTableStorage(ctx context.Context, project, location string) ([]TableStorage, error) {
+	cacheKey := cache.TableStorageKey(project, location)
+
+	// Try cache first
+	if entry, err := c.cache.Get(cacheKey); err == nil {
+		var tables []TableStorage
+		if err := json.Unmarshal([]byte(entry.Data), &tables); err == nil {
+			return tables, nil
+		}
+	}
+
+	// Cache miss, fetch from BigQuery with retry
+	return fetchShared(ctx, c, cacheKey, config.TableStorageTTL, "table storage", func() ([]TableStorage, error) {
+		var tables []TableStorage
+		err := retry.WithDefaultRetry(ctx, "list table storage", func() error {
+			var fetchErr error
+			tables, fetchErr = c.backend.ListTableStorage(ctx, project, location)
+			if fetchErr != nil {
+				return errors.WrapBigQueryError(fetchErr, "list_table_storage", project, "", "")
+			}
+			return nil
+		})
+		return tables, err
+	})
+}
+
+// StorageUsage is the storage of a project, dataset or table, summed over
+// the tables below it
+type StorageUsage struct {
+	Name     string         // Project, dataset or table ID
+	Deleted  bool           // A dropped table still held for time travel or fail-safe
+	Rows     int64          // Rows of tables that still exist
+	Children []StorageUsage // Largest logical size first
+	StorageBytes
+}
+
+// StorageTree sums table storage into a project, its datasets and their
+// tables, each level sorted largest first like sorted du output
+func StorageTree(project string, tables []TableStorage) StorageUsage {
+	root := StorageUsage{Name: project}
+	datasets := make(map[string]int)
+	for _, t := range tables {
+		i, ok := datasets[t.DatasetID]
+		if !ok {
+			i = len(root.Children)
+			datasets[t.DatasetID] = i
+			root.Children = append(root.Children, StorageUsage{Name: t.DatasetID})
+		}
+		ds := &root.Children[i]
+		ds.Children = append(ds.Children, StorageUsage{Name: t.TableID, Deleted: t.Deleted, Rows: t.TotalRows, StorageBytes: t.StorageBytes})
+		ds.Add(t.StorageBytes)
+		root.Add(t.StorageBytes)
+		if !t.Deleted {
+			ds.Rows += t.TotalRows
+			root.Rows += t.TotalRows
+		}
+	}
+
+	sortUsage(root.Children)
+	for i := range root.Children {
+		sortUsage(root.Children[i].Children)
+	}
+	return root
+}
+
+func sortUsage(usage []StorageUsage) {
+	sort.SliceStable(usage, func(i, j int) bool {
+		if usage[i].LogicalBytes() != usage[j].LogicalBytes() {
+			return usage[i].LogicalBytes() > usage[j].LogicalBytes()
+		}
+		return usage[i].Name < usage[j].Name
+	})
+}
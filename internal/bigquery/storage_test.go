@@ -0,0 +1,108 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"bqs/internal/cache"
+)
+
+func TestStorageBytesCost(t *testing.T) {
+	const gib = 1 << 30
+	b := StorageBytes{
+		ActiveLogicalBytes:      100 * gib,
+		LongTermLogicalBytes:    200 * gib,
+		ActivePhysicalBytes:     30 * gib, // Includes the 10 GiB of time travel
+		LongTermPhysicalBytes:   50 * gib,
+		TimeTravelPhysicalBytes: 10 * gib,
+		FailSafePhysicalBytes:   20 * gib,
+	}
+	if b.LogicalBytes() != 300*gib || b.PhysicalBytes() != 80*gib {
+		t.Errorf("Unexpected sizes: logical %d, physical %d", b.LogicalBytes(), b.PhysicalBytes())
+	}
+	// 100 × 0.02 + 200 × 0.01
+	if cost := b.LogicalCost(); math.Abs(cost-4.0) > 1e-9 {
+		t.Errorf("LogicalCost() = %f, want 4.00", cost)
+	}
+	// (30 + 20) × 0.04 + 50 × 0.02
+	if cost := b.PhysicalCost(); math.Abs(cost-3.0) > 1e-9 {
+		t.Errorf("PhysicalCost() = %f, want 3.00", cost)
+	}
+}
+
+func TestStorageTree(t *testing.T) {
+	tables := []TableStorage{
+		{DatasetID: "a", TableID: "small", TotalRows: 1, StorageBytes: StorageBytes{ActiveLogicalBytes: 10}},
+		{DatasetID: "a", TableID: "big", TotalRows: 2, StorageBytes: StorageBytes{ActiveLogicalBytes: 30, LongTermLogicalBytes: 5}},
+		{DatasetID: "b", TableID: "huge", TotalRows: 3, StorageBytes: StorageBytes{LongTermLogicalBytes: 100}},
+		{DatasetID: "b", TableID: "gone", TotalRows: 4, Deleted: true, StorageBytes: StorageBytes{FailSafePhysicalBytes: 7}},
+	}
+	root := StorageTree("p", tables)
+
+	var got []string
+	var walk func(u StorageUsage, depth int)
+	walk = func(u StorageUsage, depth int) {
+		got = append(got, fmt.Sprintf("%d:%s=%d/%d", depth, u.Name, u.LogicalBytes(), u.Rows))
+		for _, child := range u.Children {
+			walk(child, depth+1)
+		}
+	}
+	walk(root, 0)
+	want := []string{"0:p=145/6", "1:b=100/3", "2:huge=100/3", "2:gone=0/4", "1:a=45/3", "2:big=35/2", "2:small=10/1"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("StorageTree() = %v, want %v", got, want)
+	}
+	if root.FailSafePhysicalBytes != 7 || !root.Children[0].Children[1].Deleted {
+		t.Errorf("Expected the deleted table's fail-safe bytes to be counted: %+v", root)
+	}
+}
+
+func TestClientListTableStorage(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	for dataset, location := range map[string]string{"us_data": "US", "eu_data": "EU"} {
+		if err := os.MkdirAll(filepath.Join(root, "p", dataset), 0755); err != nil {
+			t.Fatal(err)
+		}
+		data := fmt.Sprintf(`{"location": %q}`, location)
+		if err := os.WriteFile(filepath.Join(root, "p", dataset+".json"), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	storageFile := filepath.Join(root, "p", "_storage.json")
+	data := `[
+		{"table_schema": "us_data", "table_name": "t2", "total_rows": "5", "deleted": "false", "active_logical_bytes": "50"},
+		{"table_schema": "us_data", "table_name": "t1", "total_rows": null, "deleted": "true", "fail_safe_physical_bytes": "9"},
+		{"table_schema": "eu_data", "table_name": "t3", "total_rows": "7", "deleted": "false", "long_term_logical_bytes": "70"}
+	]`
+	if err := os.WriteFile(storageFile, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClientWithBackend(cache.NewMockService(), NewFixtureBackend(root))
+	tables, err := client.ListTableStorage(ctx, "p", "")
+	if err != nil {
+		t.Fatalf("ListTableStorage returned error: %v", err)
+	}
+	var got []string
+	for _, table := range tables {
+		got = append(got, fmt.Sprintf("%s.%s deleted=%v", table.DatasetID, table.TableID, table.Deleted))
+	}
+	want := []string{"eu_data.t3 deleted=false", "us_data.t1 deleted=true", "us_data.t2 deleted=false"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("ListTableStorage() = %v, want %v", got, want)
+	}
+
+	// Both regions are cached now
+	if err := os.Remove(storageFile); err != nil {
+		t.Fatal(err)
+	}
+	tables, err = client.ListTableStorage(ctx, "p", "eu_data")
+	if err != nil || len(tables) != 1 || tables[0].LongTermLogicalBytes != 70 {
+		t.Errorf("Expected the cached eu_data table, got %+v, %v", tables, err)
+	}
+}
@@ -98,6 +98,39 @@ func (c *Cache) Get(key string) (*CacheEntry, error) {
 	return &entry, nil
 }
 
+// GetStale retrieves a cache entry whether or not it has expired, so an
+// expired entry can be revalidated with its etag instead of fetched again
+func (c *Cache) GetStale(key string) (*CacheEntry, error) {
+	var entry CacheEntry
+	var createdAtUnix, expiresAtUnix int64
+
+	query := `
+		SELECT key, data, created_at, expires_at, COALESCE(etag, '')
+		FROM metadata_cache
+		WHERE key = ?
+	`
+
+	err := c.db.QueryRow(query, key).Scan(
+		&entry.Key,
+		&entry.Data,
+		&createdAtUnix,
+		&expiresAtUnix,
+		&entry.ETag,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrCacheMiss
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cache entry: %w", err)
+	}
+
+	entry.CreatedAt = time.Unix(createdAtUnix, 0)
+	entry.ExpiresAt = time.Unix(expiresAtUnix, 0)
+
+	return &entry, nil
+}
+
 // Set stores metadata in cache with optional TTL override
 func (c *Cache) Set(key, data string, ttl *time.Duration, etag ...string) error {
 	cacheTTL := c.defaultTTL
@@ -127,12 +160,37 @@ func (c *Cache) Set(key, data string, ttl *time.Duration, etag ...string) error
 	return nil
 }
 
+// Touch extends the life of a cache entry, expired or not, to the TTL
+// override or the default TTL from now, leaving its data alone
+func (c *Cache) Touch(key string, ttl *time.Duration) error {
+	cacheTTL := c.defaultTTL
+	if ttl != nil {
+		cacheTTL = *ttl
+	}
+
+	result, err := c.db.Exec("UPDATE metadata_cache SET expires_at = ? WHERE key = ?", time.Now().Add(cacheTTL).Unix(), key)
+	if err != nil {
+		return fmt.Errorf("failed to touch cache entry: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrCacheMiss
+	}
+
+	return nil
+}
+
 // Delete removes a cache entry
 func (c *Cache) Delete(key string) error {
 	_, err := c.db.Exec("DELETE FROM metadata_cache WHERE key = ?", key)
 	return err
 }
 
+// DeletePrefix removes every cache entry whose key starts with prefix
+func (c *Cache) DeletePrefix(prefix string) error {
+	_, err := c.db.Exec("DELETE FROM metadata_cache WHERE substr(key, 1, ?) = ?", len(prefix), prefix)
+	return err
+}
+
 // Clear removes all cache entries
 func (c *Cache) Clear() error {
 	_, err := c.db.Exec("DELETE FROM metadata_cache")
@@ -243,6 +301,18 @@ var (
 )
 
 // Helper functions for common cache keys
+func ProjectListKey() string {
+	return "projects"
+}
+
+func DatasetListKey(project string) string {
+	return fmt.Sprintf("datasets:%s", project)
+}
+
+func DatasetMetadataKey(project, dataset string) string {
+	return fmt.Sprintf("dataset_metadata:%s.%s", project, dataset)
+}
+
 func TableListKey(project, dataset string) string {
 	return fmt.Sprintf("tables:%s.%s", project, dataset)
 }
@@ -251,6 +321,31 @@ func SchemaKey(project, dataset, table string) string {
 	return fmt.Sprintf("schema:%s.%s.%s", project, dataset, table)
 }
 
+func SchemaAtKey(project, dataset, table string, at time.Time) string {
+	return fmt.Sprintf("%s%d", SchemaAtPrefix(project, dataset, table), at.UnixMilli())
+}
+
+// SchemaAtPrefix is the key prefix shared by every SchemaAtKey of a table
+func SchemaAtPrefix(project, dataset, table string) string {
+	return fmt.Sprintf("schema:%s.%s.%s@", project, dataset, table)
+}
+
+func PartitionsKey(project, dataset, table string) string {
+	return fmt.Sprintf("partitions:%s.%s.%s", project, dataset, table)
+}
+
+func TableStorageKey(project, location string) string {
+	return fmt.Sprintf("storage:%s@%s", project, location)
+}
+
+func TableJobsKey(project, dataset, table string, days int) string {
+	return fmt.Sprintf("jobs:%s.%s.%s@%dd", project, dataset, table, days)
+}
+
+func TableReadsKey(project, dataset string, days int) string {
+	return fmt.Sprintf("reads:%s.%s@%dd", project, dataset, days)
+}
+
 func MetadataKey(project, dataset, table string) string {
 	return fmt.Sprintf("metadata:%s.%s.%s", project, dataset, table)
 }
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRevalidatingExpiredEntries(t *testing.T) {
+	t.Setenv("BQS_CACHE_DIR", t.TempDir())
+	sqlite, err := New(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlite.Close()
+
+	for name, service := range map[string]Service{"sqlite": sqlite, "mock": NewMockService()} {
+		t.Run(name, func(t *testing.T) {
+			expired := -time.Minute
+			if err := service.Set("metadata:p.d.t", "data", &expired, `"etag-1"`); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := service.Get("metadata:p.d.t"); err != ErrCacheMiss {
+				t.Fatalf("Expected ErrCacheMiss for the expired entry, got %v", err)
+			}
+
+			entry, err := service.GetStale("metadata:p.d.t")
+			if err != nil {
+				t.Fatalf("GetStale failed: %v", err)
+			}
+			if entry.Data != "data" || entry.ETag != `"etag-1"` {
+				t.Errorf("GetStale returned %q with etag %q", entry.Data, entry.ETag)
+			}
+
+			ttl := 5 * time.Minute
+			if err := service.Touch("metadata:p.d.t", &ttl); err != nil {
+				t.Fatalf("Touch failed: %v", err)
+			}
+			entry, err = service.Get("metadata:p.d.t")
+			if err != nil {
+				t.Fatalf("Get after Touch failed: %v", err)
+			}
+			if entry.Data != "data" || entry.ETag != `"etag-1"` || time.Until(entry.ExpiresAt) < 4*time.Minute {
+				t.Errorf("Touched entry %+v, want the same data and etag for another 5 minutes", entry)
+			}
+
+			if _, err := service.GetStale("metadata:p.d.other"); err != ErrCacheMiss {
+				t.Errorf("Expected ErrCacheMiss from GetStale for a missing key, got %v", err)
+			}
+			if err := service.Touch("metadata:p.d.other", &ttl); err != ErrCacheMiss {
+				t.Errorf("Expected ErrCacheMiss from Touch for a missing key, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDeletePrefix(t *testing.T) {
+	t.Setenv("BQS_CACHE_DIR", t.TempDir())
+	sqlite, err := New(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlite.Close()
+
+	at := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	for name, service := range map[string]Service{"sqlite": sqlite, "mock": NewMockService()} {
+		t.Run(name, func(t *testing.T) {
+			ttl := time.Hour
+			keys := []string{
+				SchemaKey("p", "d", "t"),
+				SchemaAtKey("p", "d", "t", at),
+				SchemaAtKey("p", "d", "t", at.Add(time.Hour)),
+				SchemaAtKey("p", "d", "t2", at),
+			}
+			for _, key := range keys {
+				if err := service.Set(key, "data", &ttl); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := service.DeletePrefix(SchemaAtPrefix("p", "d", "t")); err != nil {
+				t.Fatalf("DeletePrefix failed: %v", err)
+			}
+			for i, key := range keys {
+				_, err := service.Get(key)
+				if deleted := i == 1 || i == 2; deleted != (err == ErrCacheMiss) {
+					t.Errorf("Get(%q) returned %v after deleting the prefix; deleted=%v", key, err, deleted)
+				}
+			}
+		})
+	}
+}
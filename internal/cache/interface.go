@@ -5,9 +5,12 @@ import "time"
 // Service defines the interface for cache operations
 type Service interface {
 	Get(key string) (*CacheEntry, error)
+	GetStale(key string) (*CacheEntry, error)
 	Set(key, data string, ttl *time.Duration, etag ...string) error
+	Touch(key string, ttl *time.Duration) error
 	Exists(key string) (bool, error)
 	Delete(key string) error
+	DeletePrefix(prefix string) error
 	Clear() error
 	Cleanup() error
 	Stats() (*CacheStats, error)
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"strings"
 	"sync"
 	"time"
 )
@@ -30,6 +31,17 @@ func (m *MockService) Get(key string) (*CacheEntry, error) {
 	return entry, nil
 }
 
+func (m *MockService) GetStale(key string) (*CacheEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	
+	entry, exists := m.data[key]
+	if !exists {
+		return nil, ErrCacheMiss
+	}
+	return entry, nil
+}
+
 func (m *MockService) Set(key, data string, ttl *time.Duration, etag ...string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -57,6 +69,24 @@ func (m *MockService) Set(key, data string, ttl *time.Duration, etag ...string)
 	return nil
 }
 
+func (m *MockService) Touch(key string, ttl *time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	entry, exists := m.data[key]
+	if !exists {
+		return ErrCacheMiss
+	}
+	cacheTTL := 15 * time.Minute
+	if ttl != nil {
+		cacheTTL = *ttl
+	}
+	touched := *entry
+	touched.ExpiresAt = time.Now().Add(cacheTTL)
+	m.data[key] = &touched
+	return nil
+}
+
 func (m *MockService) Exists(key string) (bool, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -75,6 +105,17 @@ func (m *MockService) Delete(key string) error {
 	return nil
 }
 
+func (m *MockService) DeletePrefix(prefix string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key := range m.data {
+		if strings.HasPrefix(key, prefix) {
+			delete(m.data, key)
+		}
+	}
+	return nil
+}
+
 func (m *MockService) Clear() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
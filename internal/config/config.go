@@ -4,9 +4,77 @@ import "time"
 
 // Cache TTL configuration
 const (
-	TableListTTL = 5 * time.Minute  // Table lists change infrequently
-	MetadataTTL  = 15 * time.Minute // Table metadata changes moderately  
-	SchemaTTL    = 30 * time.Minute // Schemas change rarely
+	ProjectListTTL = 30 * time.Minute // Project access changes rarely
+	DatasetListTTL = 5 * time.Minute  // Datasets are created and dropped rarely
+	DatasetMetadataTTL = 15 * time.Minute // Dataset settings and ACLs change rarely
+	TableListTTL  = 5 * time.Minute  // Table lists change infrequently
+	MetadataTTL   = 15 * time.Minute // Table metadata changes moderately  
+	SchemaTTL     = 30 * time.Minute // Schemas change rarely
+	PartitionsTTL = 10 * time.Minute // Partitions change with every load
+	TableStorageTTL = 30 * time.Minute // TABLE_STORAGE itself lags by minutes to hours
+	JobHistoryTTL = 2 * time.Minute // Jobs run all the time; just avoid re-querying while browsing
+)
+
+// BigQuery API configuration
+const (
+	TableListPageSize = 1000 // Tables requested per tables.list page
+	QueryPageSize     = 1000 // Rows requested per jobs.query or jobs.getQueryResults page
+	
+	// bq ls doesn't expose page tokens but follows them internally up to
+	// --max_results, so the CLI backend asks for everything in one call
+	CLIMaxTableListResults = 1000000
+	
+	// A persistent bq worker (BQS_BQ_WORKER) saves starting Python for every
+	// bq call; requests it can't serve run one-shot instead
+	BQWorkerRequestTimeout = 1 * time.Minute // Requests the worker is this slow to answer run one-shot
+	BQWorkerMaxRestarts    = 3               // Worker failures in a row before giving up on it
+	
+	// The browser prefetches table metadata in the background, visible rows
+	// first, pausing while the user waits on a foreground load
+	PrefetchParallelism = 4  // Default parallel tables.get calls
+	PrefetchRate        = 10 // Default tables.get calls per second
+	
+	DefaultOperationTimeout = 2 * time.Minute // Upper bound for a single BigQuery operation
+	RESTRequestTimeout  = 60 * time.Second // Upper bound for a single REST API request
+	TokenRequestTimeout = 30 * time.Second // Upper bound for fetching or refreshing an access token
+	QueryWaitTimeout = 10 * time.Second // Server-side wait per jobs.query/getQueryResults call
+	
+	// Table previews read stored rows (tabledata.list / bq head), which is free
+	PreviewRows     = 100 // Rows loaded by the browser's preview grid
+	HeadDefaultRows = 20  // Default row count of bqs head
+	
+	// bqs query always dry-runs first and refuses to run above the byte limit
+	QueryDefaultRows      = 100            // Default row count of bqs query
+	DefaultMaxBytesBilled = 10 * (1 << 30) // 10 GiB
+	OnDemandPricePerTiB   = 6.25           // USD per TiB processed, on-demand pricing
+	
+	// Lineage is parsed from view definitions, one dataset scan per hop
+	LineageDefaultDepth = 3 // Default levels walked by bqs lineage
+	
+	// FOR SYSTEM_TIME AS OF reaches back at most this far; datasets may be
+	// configured with a shorter window, which BigQuery then enforces
+	TimeTravelWindow = 7 * 24 * time.Hour
+	
+	// Storage list prices in USD per GiB per month (US multi-region), used by
+	// bqs du to compare the logical and physical billing models
+	LogicalActivePricePerGiB    = 0.02
+	LogicalLongTermPricePerGiB  = 0.01
+	PhysicalActivePricePerGiB   = 0.04
+	PhysicalLongTermPricePerGiB = 0.02
+	
+	// Job history comes from INFORMATION_SCHEMA.JOBS_BY_PROJECT, which bills
+	// the bytes it scans, so the browser only loads it on request
+	JobHistoryLimit     = 10000 // Most recent jobs fetched per table
+	UsageDefaultDays    = 30    // Default window of bqs usage
+	ActivityDays        = 7     // Window of the browser's activity pane
+	JobHistoryRetention = 180 * 24 * time.Hour // How far back JOBS_BY_PROJECT reaches
+	
+	// Default criteria of bqs stale and the browser's stale table toggle
+	StaleUnmodified = 180 * 24 * time.Hour
+	StaleUnqueried  = 90 * 24 * time.Hour
+	
+	// Tables and partitions left unmodified this long are billed at long-term rates
+	LongTermStorageAge = 90 * 24 * time.Hour
 )
 
 // UI configuration
@@ -19,9 +87,38 @@ const (
 	TableColumnWidth   = 35
 	TypeColumnWidth    = 8
 	CreatedColumnWidth = 20
+
+	// Project list column widths
+	FavoriteColumnWidth     = 3
+	ProjectColumnWidth      = 30
+	FriendlyNameColumnWidth = 30
+
+	// Dataset list column widths
+	DatasetColumnWidth    = 30
+	LocationColumnWidth   = 12
+	ExpirationColumnWidth = 10
+	TableCountColumnWidth = 7
+	LabelsColumnWidth     = 30
+
+	// Preview grid column width; longer values are truncated
+	PreviewColumnWidth = 24
+
+	// Source columns shown after a view column in the schema tree
+	SchemaSourcesShown = 3
+	
+	// Source URIs shown in an external table's details
+	ExternalURIsShown = 3
+	
+	// Days of rows per day sparkline in a partitioned table's details
+	PartitionHistogramDays = 60
 	
 	// UI spacing and timing
 	HeaderFooterPadding = 8  // Account for header, footer, padding in table height
+	DatasetDetailPadding = 24 // Lines of the dataset detail pane not used by access entries
+	ViewSQLPadding      = 16 // Lines of the view SQL pane not used by the query
+	PreviewPadding      = 14 // Lines of the preview grid not used by rows
+	PartitionsPadding   = 12 // Lines of the partitions pane not used by partitions
+	ActivityPadding     = 12 // Lines of the activity pane not used by jobs
 	StatusMessageTTL    = 3 * time.Second // How long status messages are shown
 	
 	// UI styling constants  
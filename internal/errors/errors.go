@@ -1,7 +1,9 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
+	"net/http"
 	"os/exec"
 	"strings"
 	"time"
@@ -72,6 +74,38 @@ func (e *BQSError) GetRetryAfter() time.Duration {
 	}
 }
 
+// APIError is a structured error returned by the BigQuery REST API
+type APIError struct {
+	StatusCode int
+	Reason     string // First error.errors[].reason, e.g. notFound, accessDenied, rateLimitExceeded
+	Message    string
+	RetryAfter time.Duration // From the Retry-After header, if present
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("BigQuery API error %d (%s): %s", e.StatusCode, e.Reason, e.Message)
+	}
+	return fmt.Sprintf("BigQuery API error %d: %s", e.StatusCode, e.Message)
+}
+
+// CommandError is a bq command that exited with a non-zero status in a
+// persistent bq worker or a replayed cassette, the counterpart of an
+// exec.ExitError
+type CommandError struct {
+	ExitCode int
+	Stderr   []byte
+}
+
+// Error implements the error interface, in the form exec.ExitError uses
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("exit status %d", e.ExitCode)
+}
+
+// ErrNotRecorded marks a bq command a replayed cassette has no response to
+var ErrNotRecorded = stderrors.New("no recorded response")
+
 // WrapBigQueryError wraps a BigQuery command error with context and classification
 func WrapBigQueryError(err error, operation, project, dataset, table string) *BQSError {
 	if err == nil {
@@ -87,12 +121,38 @@ func WrapBigQueryError(err error, operation, project, dataset, table string) *BQ
 		context["table"] = table
 	}
 
+	// Structured API errors carry enough detail to classify without string matching
+	var apiErr *APIError
+	if stderrors.As(err, &apiErr) {
+		return classifyAPIError(apiErr, err, operation, project, dataset, table, context)
+	}
+
+	// Asking a cassette again won't make it answer
+	if stderrors.Is(err, ErrNotRecorded) {
+		return &BQSError{
+			Type:       ErrorTypeUnknown,
+			Message:    err.Error(),
+			Underlying: err,
+			Retryable:  false,
+			Context:    context,
+		}
+	}
+
 	// Analyze the error to determine type and message
 	errorText := err.Error()
 	lowerError := strings.ToLower(errorText)
 
 	// Check for specific BigQuery error patterns
 	switch {
+	case isQueryFailure(lowerError):
+		return &BQSError{
+			Type:       ErrorTypeAPI,
+			Message:    fmt.Sprintf("Query failed: %s", cleanErrorOutput(errorText)),
+			Underlying: err,
+			Retryable:  false,
+			Context:    context,
+		}
+
 	case strings.Contains(lowerError, "not found"):
 		return &BQSError{
 			Type:       ErrorTypeNotFound,
@@ -105,7 +165,7 @@ func WrapBigQueryError(err error, operation, project, dataset, table string) *BQ
 	case strings.Contains(lowerError, "permission denied") || strings.Contains(lowerError, "access denied"):
 		return &BQSError{
 			Type:       ErrorTypePermission,
-			Message:    fmt.Sprintf("Access denied to %s.%s - check BigQuery permissions", project, dataset),
+			Message:    determinePermissionMessage(operation, project, dataset),
 			Underlying: err,
 			Retryable:  false,
 			Context:    context,
@@ -152,9 +212,28 @@ func WrapBigQueryError(err error, operation, project, dataset, table string) *BQ
 
 	case isExitError(err):
 		// Handle bq command exit errors
-		exitErr := err.(*exec.ExitError)
-		stderr := string(exitErr.Stderr)
+		stderr := exitErrorStderr(err)
 		
+		if isQueryFailure(strings.ToLower(stderr)) {
+			return &BQSError{
+				Type:       ErrorTypeAPI,
+				Message:    fmt.Sprintf("Query failed: %s", cleanErrorOutput(stderr)),
+				Underlying: err,
+				Retryable:  false,
+				Context:    context,
+			}
+		}
+
+		if lowerStderr := strings.ToLower(stderr); strings.Contains(lowerStderr, "access denied") || strings.Contains(lowerStderr, "permission") {
+			return &BQSError{
+				Type:       ErrorTypePermission,
+				Message:    determinePermissionMessage(operation, project, dataset),
+				Underlying: err,
+				Retryable:  false,
+				Context:    context,
+			}
+		}
+
 		if strings.Contains(strings.ToLower(stderr), "not found") {
 			return &BQSError{
 				Type:       ErrorTypeNotFound,
@@ -184,6 +263,81 @@ func WrapBigQueryError(err error, operation, project, dataset, table string) *BQ
 	}
 }
 
+// classifyAPIError maps HTTP status codes and error reasons to BQSError types
+func classifyAPIError(apiErr *APIError, err error, operation, project, dataset, table string, context map[string]string) *BQSError {
+	switch {
+	case apiErr.StatusCode == http.StatusNotFound || apiErr.Reason == "notFound":
+		return &BQSError{
+			Type:       ErrorTypeNotFound,
+			Message:    determineNotFoundMessage(operation, project, dataset, table),
+			Underlying: err,
+			Retryable:  false,
+			Context:    context,
+		}
+
+	case apiErr.Reason == "invalidQuery" || apiErr.Reason == "bytesBilledLimitExceeded":
+		return &BQSError{
+			Type:       ErrorTypeAPI,
+			Message:    fmt.Sprintf("Query failed: %s", cleanErrorOutput(apiErr.Message)),
+			Underlying: err,
+			Retryable:  false,
+			Context:    context,
+		}
+
+	case apiErr.StatusCode == http.StatusTooManyRequests ||
+		apiErr.Reason == "rateLimitExceeded" || apiErr.Reason == "quotaExceeded":
+		retryAfter := apiErr.RetryAfter
+		if retryAfter == 0 {
+			retryAfter = 30 * time.Second
+		}
+		return &BQSError{
+			Type:       ErrorTypeQuota,
+			Message:    "BigQuery quota exceeded - retrying with backoff",
+			Underlying: err,
+			Retryable:  true,
+			RetryAfter: retryAfter,
+			Context:    context,
+		}
+
+	case apiErr.StatusCode == http.StatusUnauthorized || apiErr.Reason == "authError":
+		return &BQSError{
+			Type:       ErrorTypeAuth,
+			Message:    "Authentication failed - run 'gcloud auth application-default login' or check service account credentials",
+			Underlying: err,
+			Retryable:  false,
+			Context:    context,
+		}
+
+	case apiErr.StatusCode == http.StatusForbidden:
+		return &BQSError{
+			Type:       ErrorTypePermission,
+			Message:    determinePermissionMessage(operation, project, dataset),
+			Underlying: err,
+			Retryable:  false,
+			Context:    context,
+		}
+
+	case apiErr.StatusCode >= 500 || apiErr.Reason == "backendError" || apiErr.Reason == "internalError":
+		return &BQSError{
+			Type:       ErrorTypeAPI,
+			Message:    fmt.Sprintf("BigQuery service error: %s", cleanErrorOutput(apiErr.Message)),
+			Underlying: err,
+			Retryable:  true,
+			RetryAfter: apiErr.RetryAfter,
+			Context:    context,
+		}
+
+	default:
+		return &BQSError{
+			Type:       ErrorTypeAPI,
+			Message:    fmt.Sprintf("BigQuery request failed: %s", cleanErrorOutput(apiErr.Message)),
+			Underlying: err,
+			Retryable:  false,
+			Context:    context,
+		}
+	}
+}
+
 // WrapCacheError wraps cache-related errors
 func WrapCacheError(err error, operation string) *BQSError {
 	if err == nil {
@@ -217,8 +371,14 @@ func WrapValidationError(err error, input string) *BQSError {
 // determineNotFoundMessage creates specific not found messages
 func determineNotFoundMessage(operation, project, dataset, table string) string {
 	switch operation {
+	case "list_projects":
+		return "No accessible projects found"
+	case "list_datasets":
+		return fmt.Sprintf("Project %s not found", project)
 	case "list_tables":
 		return fmt.Sprintf("Dataset %s.%s not found or empty", project, dataset)
+	case "dry_run_query", "run_query":
+		return "Query references a table or dataset that was not found"
 	case "get_metadata", "get_schema":
 		if table != "" {
 			return fmt.Sprintf("Table %s.%s.%s not found", project, dataset, table)
@@ -232,6 +392,35 @@ func determineNotFoundMessage(operation, project, dataset, table string) string
 	}
 }
 
+// determinePermissionMessage creates specific access denied messages
+func determinePermissionMessage(operation, project, dataset string) string {
+	if operation == "list_jobs" {
+		// JOBS_BY_PROJECT needs jobs.listAll, which dataset readers rarely have
+		return fmt.Sprintf("Access denied to the job history of %s - listing other users' jobs needs bigquery.jobs.listAll", project)
+	}
+	return fmt.Sprintf("Access denied to %s - check BigQuery permissions", resourceName(project, dataset))
+}
+
+// isQueryFailure reports whether a lowercased error message describes a query
+// that BigQuery rejected. Running it again would fail the same way.
+func isQueryFailure(lowerError string) bool {
+	return strings.Contains(lowerError, "invalid query") ||
+		strings.Contains(lowerError, "error in query string") ||
+		strings.Contains(lowerError, "syntax error") ||
+		strings.Contains(lowerError, "exceeded limit for bytes billed")
+}
+
+// resourceName formats a project or project.dataset for messages
+func resourceName(project, dataset string) string {
+	if project == "" {
+		return "projects"
+	}
+	if dataset == "" {
+		return project
+	}
+	return project + "." + dataset
+}
+
 // cleanErrorOutput cleans up error messages for better user experience
 func cleanErrorOutput(errorText string) string {
 	// Remove common bq command noise
@@ -260,10 +449,25 @@ func cleanErrorOutput(errorText string) string {
 	return cleaned
 }
 
-// isExitError checks if an error is an exec.ExitError
+// isExitError checks if an error is, or wraps, an exec.ExitError or a
+// CommandError
 func isExitError(err error) bool {
-	_, ok := err.(*exec.ExitError)
-	return ok
+	var exitErr *exec.ExitError
+	var commandErr *CommandError
+	return stderrors.As(err, &exitErr) || stderrors.As(err, &commandErr)
+}
+
+// exitErrorStderr returns what the failed bq command printed to stderr
+func exitErrorStderr(err error) string {
+	var exitErr *exec.ExitError
+	if stderrors.As(err, &exitErr) {
+		return string(exitErr.Stderr)
+	}
+	var commandErr *CommandError
+	if stderrors.As(err, &commandErr) {
+		return string(commandErr.Stderr)
+	}
+	return ""
 }
 
 // UserFriendlyMessage returns a user-friendly error message
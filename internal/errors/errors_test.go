@@ -0,0 +1,93 @@
+package errors_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"bqs/internal/bigquery"
+	"bqs/internal/errors"
+)
+
+// TestWrapBigQueryErrorReplayed classifies bq failures replayed from a
+// cassette, the way they reach WrapBigQueryError from the bq backend
+func TestWrapBigQueryErrorReplayed(t *testing.T) {
+	cassette := bigquery.ReplayCassette("testdata/bq_errors.jsonl")
+	unused := func(ctx context.Context, args ...string) ([]byte, error) {
+		t.Fatalf("Replay ran bq %v", args)
+		return nil, nil
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		operation string
+		table     string
+		wantType  errors.ErrorType
+		retryable bool
+		message   string
+	}{
+		{
+			name:      "table not found",
+			args:      []string{"show", "--project_id=demo-project", "--format=json", "analytics.missing"},
+			operation: "get_metadata",
+			table:     "missing",
+			wantType:  errors.ErrorTypeNotFound,
+			message:   "Table demo-project.analytics.missing not found",
+		},
+		{
+			name:      "access denied",
+			args:      []string{"show", "--project_id=demo-project", "--format=json", "restricted.salaries"},
+			operation: "get_metadata",
+			table:     "salaries",
+			wantType:  errors.ErrorTypePermission,
+			message:   "Access denied to demo-project.analytics",
+		},
+		{
+			name:      "syntax error",
+			args:      []string{"query", "--project_id=demo-project", "--nouse_legacy_sql", "--format=json", "SELECT nonsense FROM"},
+			operation: "run_query",
+			wantType:  errors.ErrorTypeAPI,
+			message:   "Query failed: Error in query string",
+		},
+		{
+			name:      "bytes billed limit",
+			args:      []string{"query", "--project_id=demo-project", "--nouse_legacy_sql", "--format=json", "--maximum_bytes_billed=1073741824", "SELECT * FROM analytics.events"},
+			operation: "run_query",
+			wantType:  errors.ErrorTypeAPI,
+			message:   "exceeded limit for bytes billed",
+		},
+		{
+			name:      "transient backend error",
+			args:      []string{"ls", "--project_id=demo-project", "--format=json", "analytics"},
+			operation: "list_tables",
+			wantType:  errors.ErrorTypeAPI,
+			retryable: true,
+			message:   "BigQuery command failed: BigQuery error in ls operation",
+		},
+		{
+			name:      "not recorded",
+			args:      []string{"version"},
+			operation: "version",
+			wantType:  errors.ErrorTypeUnknown,
+			message:   "no recorded response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := cassette.Run(context.Background(), unused, tt.args...)
+			if err == nil {
+				t.Fatal("Expected the replayed command to fail")
+			}
+			bqsErr := errors.WrapBigQueryError(err, tt.operation, "demo-project", "analytics", tt.table)
+			if bqsErr.Type != tt.wantType || bqsErr.Retryable != tt.retryable {
+				t.Errorf("Classified as type %v, retryable %v; want type %v, retryable %v (%s)",
+					bqsErr.Type, bqsErr.Retryable, tt.wantType, tt.retryable, bqsErr.Message)
+			}
+			if !strings.Contains(bqsErr.Message, tt.message) {
+				t.Errorf("Message %q doesn't contain %q", bqsErr.Message, tt.message)
+			}
+		})
+	}
+}
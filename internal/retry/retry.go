@@ -53,6 +53,11 @@ func WithRetry(ctx context.Context, config *Config, operation string, fn func()
 
 		lastErr = err
 
+		// A cancelled or expired context makes further attempts pointless
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
 		// Check if this is a BQS error and if it's retryable
 		if bqsErr, ok := err.(*errors.BQSError); ok {
 			if !bqsErr.IsRetryable() {
@@ -151,6 +156,11 @@ func (ro *RetryableOperation) Execute(ctx context.Context, fn func() error) erro
 
 		lastErr = err
 
+		// A cancelled or expired context makes further attempts pointless
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
 		// Check if this is a BQS error and if it's retryable
 		if bqsErr, ok := err.(*errors.BQSError); ok {
 			if !bqsErr.IsRetryable() {
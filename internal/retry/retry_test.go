@@ -0,0 +1,80 @@
+package retry_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"bqs/internal/bigquery"
+	"bqs/internal/errors"
+	"bqs/internal/retry"
+)
+
+// replayShow returns an operation that replays bq show of a table from the
+// cassette, classifying failures as the client does, and counts its attempts
+func replayShow(t *testing.T, cassette *bigquery.Cassette, table string, attempts *int) func() error {
+	unused := func(ctx context.Context, args ...string) ([]byte, error) {
+		t.Fatalf("Replay ran bq %v", args)
+		return nil, nil
+	}
+	return func() error {
+		*attempts++
+		_, err := cassette.Run(context.Background(), unused, "show", "--project_id=demo-project", "--format=json", "analytics."+table)
+		if err != nil {
+			bqsErr := errors.WrapBigQueryError(err, "get_metadata", "demo-project", "analytics", table)
+			bqsErr.RetryAfter = time.Millisecond // Keep the test fast
+			return bqsErr
+		}
+		return nil
+	}
+}
+
+func TestWithRetryReplayed(t *testing.T) {
+	ctx := context.Background()
+	config := &retry.Config{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1}
+
+	t.Run("succeeds after transient failures", func(t *testing.T) {
+		attempts := 0
+		err := retry.WithRetry(ctx, config, "get_metadata", replayShow(t, bigquery.ReplayCassette("testdata/bq_retries.jsonl"), "events", &attempts))
+		if err != nil || attempts != 3 {
+			t.Errorf("WithRetry returned %v after %d attempts, want success on the third", err, attempts)
+		}
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		attempts := 0
+		err := retry.WithRetry(ctx, config, "get_metadata", replayShow(t, bigquery.ReplayCassette("testdata/bq_retries.jsonl"), "flaky", &attempts))
+		bqsErr, ok := err.(*errors.BQSError)
+		if !ok || attempts != 3 || !strings.Contains(bqsErr.Message, "failed after 3 attempts") {
+			t.Errorf("WithRetry returned %v after %d attempts, want a failure after all 3", err, attempts)
+		}
+	})
+
+	t.Run("doesn't retry permanent failures", func(t *testing.T) {
+		attempts := 0
+		err := retry.WithRetry(ctx, config, "get_metadata", replayShow(t, bigquery.ReplayCassette("testdata/bq_retries.jsonl"), "missing", &attempts))
+		bqsErr, ok := err.(*errors.BQSError)
+		if !ok || bqsErr.Type != errors.ErrorTypeNotFound || attempts != 1 {
+			t.Errorf("WithRetry returned %v after %d attempts, want not found after one", err, attempts)
+		}
+	})
+
+	t.Run("doesn't retry commands missing from the cassette", func(t *testing.T) {
+		attempts := 0
+		err := retry.WithRetry(ctx, config, "get_metadata", replayShow(t, bigquery.ReplayCassette("testdata/bq_retries.jsonl"), "unrecorded", &attempts))
+		if err == nil || attempts != 1 {
+			t.Errorf("WithRetry returned %v after %d attempts, want a failure after one", err, attempts)
+		}
+	})
+
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		attempts := 0
+		err := retry.WithRetry(cancelled, config, "get_metadata", replayShow(t, bigquery.ReplayCassette("testdata/bq_retries.jsonl"), "flaky", &attempts))
+		if err != context.Canceled || attempts != 1 {
+			t.Errorf("WithRetry returned %v after %d attempts, want context.Canceled after one", err, attempts)
+		}
+	})
+}
@@ -0,0 +1,18 @@
+package utils
+
+import (
+	"os"
+	"strings"
+)
+
+// FavoriteProjects returns the project IDs listed in BQS_FAVORITE_PROJECTS,
+// a comma-separated list shown first on the browser's project picker
+func FavoriteProjects() []string {
+	var projects []string
+	for _, project := range strings.Split(os.Getenv("BQS_FAVORITE_PROJECTS"), ",") {
+		if project = strings.TrimSpace(project); project != "" {
+			projects = append(projects, project)
+		}
+	}
+	return projects
+}
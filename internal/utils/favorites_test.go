@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFavoriteProjects(t *testing.T) {
+	t.Setenv("BQS_FAVORITE_PROJECTS", "")
+	if projects := FavoriteProjects(); projects != nil {
+		t.Errorf("Expected no favourites, got %v", projects)
+	}
+
+	t.Setenv("BQS_FAVORITE_PROJECTS", " my-project, ,bigquery-public-data,")
+	expected := []string{"my-project", "bigquery-public-data"}
+	if projects := FavoriteProjects(); !reflect.DeepEqual(projects, expected) {
+		t.Errorf("FavoriteProjects() = %v, expected %v", projects, expected)
+	}
+}
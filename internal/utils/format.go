@@ -1,6 +1,11 @@
 package utils
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // FormatBytes formats bytes in human readable format
 func FormatBytes(bytes int64) string {
@@ -14,4 +19,57 @@ func FormatBytes(bytes int64) string {
 		exp++
 	}
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
-}
\ No newline at end of file
+}
+
+// ParseBytes parses a byte count such as 1048576, 500MB or 1.5TiB. Units are
+// binary (1 GB = 1024 MB), matching FormatBytes.
+func ParseBytes(s string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "B"), "I")
+
+	multiplier := int64(1)
+	if n := len(value); n > 0 {
+		if exp := strings.IndexByte("KMGTPE", value[n-1]); exp >= 0 {
+			value = strings.TrimSpace(value[:n-1])
+			for i := 0; i <= exp; i++ {
+				multiplier *= 1024
+			}
+		}
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+	return int64(number * float64(multiplier)), nil
+}
+
+// ParseAge parses an age such as 180d, 26w or 12h. Days and weeks are
+// accepted on top of time.ParseDuration's units; 0 is zero.
+func ParseAge(s string) (time.Duration, error) {
+	value := strings.ToLower(strings.TrimSpace(s))
+	if value == "0" {
+		return 0, nil
+	}
+
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(value, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(value, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+	if unit != 0 {
+		number, err := strconv.ParseFloat(value[:len(value)-1], 64)
+		if err != nil || number < 0 {
+			return 0, fmt.Errorf("invalid age %q", s)
+		}
+		return time.Duration(number * float64(unit)), nil
+	}
+
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("invalid age %q: use a number of days (180d), weeks (26w) or hours (12h)", s)
+	}
+	return age, nil
+}
@@ -1,6 +1,9 @@
 package utils
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestFormatBytes(t *testing.T) {
 	tests := []struct {
@@ -28,4 +31,58 @@ func TestFormatBytes(t *testing.T) {
 			t.Errorf("FormatBytes(%d) = %s, expected %s", test.input, result, test.expected)
 		}
 	}
-}
\ No newline at end of file
+}
+func TestParseBytes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"0", 0},
+		{"1048576", 1048576},
+		{"512B", 512},
+		{"10GB", 10 << 30},
+		{"10 gib", 10 << 30},
+		{"1.5T", 3 << 39},
+		{"500MB", 500 << 20},
+	}
+
+	for _, test := range tests {
+		result, err := ParseBytes(test.input)
+		if err != nil || result != test.expected {
+			t.Errorf("ParseBytes(%q) = %d, %v, expected %d", test.input, result, err, test.expected)
+		}
+	}
+
+	for _, input := range []string{"", "GB", "ten", "-1GB"} {
+		if _, err := ParseBytes(input); err == nil {
+			t.Errorf("ParseBytes(%q) expected an error", input)
+		}
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"0", 0},
+		{"180d", 180 * 24 * time.Hour},
+		{"26w", 26 * 7 * 24 * time.Hour},
+		{"1.5d", 36 * time.Hour},
+		{"12h", 12 * time.Hour},
+		{" 90D ", 90 * 24 * time.Hour},
+	}
+
+	for _, test := range tests {
+		result, err := ParseAge(test.input)
+		if err != nil || result != test.expected {
+			t.Errorf("ParseAge(%q) = %v, %v, expected %v", test.input, result, err, test.expected)
+		}
+	}
+
+	for _, input := range []string{"", "d", "ten days", "-5d", "-1h", "180"} {
+		if _, err := ParseAge(input); err == nil {
+			t.Errorf("ParseAge(%q) expected an error", input)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SQLTokenKind classifies a SQL token for syntax highlighting
+type SQLTokenKind int
+
+const (
+	SQLText SQLTokenKind = iota // Identifiers, operators, punctuation and whitespace
+	SQLKeyword
+	SQLString
+	SQLNumber
+	SQLComment
+	SQLQuotedIdentifier // `project.dataset.table`
+)
+
+// SQLToken is a run of SQL source text of a single kind
+type SQLToken struct {
+	Kind SQLTokenKind
+	Text string
+}
+
+// sqlKeywords are the GoogleSQL reserved words and common clauses highlighted as keywords
+var sqlKeywords = map[string]bool{}
+
+func init() {
+	for _, kw := range strings.Fields(`
+		ALL AND ANY ARRAY AS ASC BETWEEN BY CASE CAST CREATE CROSS CUBE CURRENT DEFAULT
+		DESC DISTINCT ELSE END ESCAPE EXCEPT EXCLUDE EXISTS EXTRACT FALSE FETCH FOLLOWING
+		FOR FROM FULL GROUP GROUPING HAVING IF IGNORE IN INNER INTERSECT INTERVAL INTO IS
+		JOIN LATERAL LEFT LIKE LIMIT MERGE NATURAL NOT NULL NULLS OF OFFSET ON OR ORDER
+		OUTER OVER PARTITION PRECEDING QUALIFY RANGE RECURSIVE REPLACE RESPECT RIGHT ROLLUP
+		ROWS SAFE_CAST SELECT SET STRUCT TABLESAMPLE THEN TO TRUE UNBOUNDED UNION UNNEST
+		USING WHEN WHERE WINDOW WITH`) {
+		sqlKeywords[kw] = true
+	}
+}
+
+// TokenizeSQL splits SQL into tokens for highlighting. It is deliberately
+// forgiving: unterminated strings and comments run to the end of the input,
+// and concatenating the token texts always reproduces the input.
+func TokenizeSQL(sql string) []SQLToken {
+	var tokens []SQLToken
+	runes := []rune(sql)
+
+	emit := func(kind SQLTokenKind, start, end int) {
+		text := string(runes[start:end])
+		// Merge adjacent plain text to keep the token count down
+		if kind == SQLText && len(tokens) > 0 && tokens[len(tokens)-1].Kind == SQLText {
+			tokens[len(tokens)-1].Text += text
+			return
+		}
+		tokens = append(tokens, SQLToken{Kind: kind, Text: text})
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+
+		switch {
+		case r == '-' && hasPrefix(runes, i, "--"), r == '#':
+			i = indexFrom(runes, i, "\n")
+			emit(SQLComment, start, i)
+
+		case r == '/' && hasPrefix(runes, i, "/*"):
+			i = indexFrom(runes, i+2, "*/")
+			if i < len(runes) {
+				i += 2
+			}
+			emit(SQLComment, start, i)
+
+		case r == '\'' || r == '"':
+			quote := string(r)
+			if hasPrefix(runes, i, strings.Repeat(quote, 3)) {
+				i = indexFrom(runes, i+3, strings.Repeat(quote, 3))
+				if i < len(runes) {
+					i += 3
+				}
+			} else {
+				i = scanQuoted(runes, i, r)
+			}
+			emit(SQLString, start, i)
+
+		case r == '`':
+			i = scanQuoted(runes, i, r)
+			emit(SQLQuotedIdentifier, start, i)
+
+		case unicode.IsDigit(r):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == 'e' || runes[i] == 'E') {
+				i++
+			}
+			emit(SQLNumber, start, i)
+
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			if sqlKeywords[strings.ToUpper(string(runes[start:i]))] {
+				emit(SQLKeyword, start, i)
+			} else {
+				emit(SQLText, start, i)
+			}
+
+		default:
+			i++
+			emit(SQLText, start, i)
+		}
+	}
+
+	return tokens
+}
+
+// hasPrefix reports whether runes[i:] starts with prefix
+func hasPrefix(runes []rune, i int, prefix string) bool {
+	return strings.HasPrefix(string(runes[i:min(len(runes), i+len(prefix))]), prefix)
+}
+
+// indexFrom returns the index of the next occurrence of sep at or after i,
+// or len(runes) if there is none
+func indexFrom(runes []rune, i int, sep string) int {
+	for ; i < len(runes); i++ {
+		if hasPrefix(runes, i, sep) {
+			return i
+		}
+	}
+	return len(runes)
+}
+
+// scanQuoted returns the index just past the closing quote of the quoted run
+// starting at i, honouring backslash escapes
+func scanQuoted(runes []rune, i int, quote rune) int {
+	for i++; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+	return len(runes)
+}
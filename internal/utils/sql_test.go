@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokenizeSQL(t *testing.T) {
+	sql := "-- daily users\nSELECT DATE(ts) AS day, COUNT(*) AS n\nFROM `p.d.t` /* all rows */\nWHERE name = 'it\\'s' AND n > 10.5\n# done"
+
+	tokens := TokenizeSQL(sql)
+
+	var rebuilt strings.Builder
+	kinds := map[string]SQLTokenKind{}
+	for _, token := range tokens {
+		rebuilt.WriteString(token.Text)
+		kinds[token.Text] = token.Kind
+	}
+	if rebuilt.String() != sql {
+		t.Fatalf("Tokens don't reproduce the input:\n%q\n%q", rebuilt.String(), sql)
+	}
+
+	expected := map[string]SQLTokenKind{
+		"-- daily users": SQLComment,
+		"SELECT":         SQLKeyword,
+		"AS":             SQLKeyword,
+		"FROM":           SQLKeyword,
+		"`p.d.t`":        SQLQuotedIdentifier,
+		"/* all rows */": SQLComment,
+		"'it\\'s'":       SQLString,
+		"10.5":           SQLNumber,
+		"# done":         SQLComment,
+	}
+	for text, kind := range expected {
+		if got, ok := kinds[text]; !ok || got != kind {
+			t.Errorf("Token %q: expected kind %v, got %v (present=%v)", text, kind, got, ok)
+		}
+	}
+}
+
+func TestTokenizeSQLUnterminated(t *testing.T) {
+	for _, sql := range []string{"SELECT 'oops", "SELECT /* never closed", "SELECT '''multi\nline"} {
+		var rebuilt strings.Builder
+		for _, token := range TokenizeSQL(sql) {
+			rebuilt.WriteString(token.Text)
+		}
+		if rebuilt.String() != sql {
+			t.Errorf("Tokens don't reproduce %q, got %q", sql, rebuilt.String())
+		}
+	}
+}
@@ -44,6 +44,18 @@ func ValidateProjectDatasetTable(input string) error {
 	return nil
 }
 
+// ValidateResourcePath validates a project, project.dataset or
+// project.dataset.table identifier
+func ValidateResourcePath(input string) error {
+	if input != "" && !strings.Contains(input, ".") {
+		if err := ValidateProject(input); err != nil {
+			return fmt.Errorf("invalid project: %w", err)
+		}
+		return nil
+	}
+	return ValidateProjectDatasetTable(input)
+}
+
 // ValidateProject validates a BigQuery project ID
 func ValidateProject(project string) error {
 	if project == "" {
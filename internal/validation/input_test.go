@@ -31,6 +31,34 @@ func TestValidateProjectDatasetTable(t *testing.T) {
 	}
 }
 
+func TestValidateResourcePath(t *testing.T) {
+	validCases := []string{
+		"my-project",
+		"my-project.dataset",
+		"my-project.dataset.table",
+	}
+
+	invalidCases := []string{
+		"",
+		"proj",
+		"My-Project",
+		"my-project.",
+		"too.many.parts.here",
+	}
+
+	for _, valid := range validCases {
+		if err := ValidateResourcePath(valid); err != nil {
+			t.Errorf("Expected %s to be valid, got error: %v", valid, err)
+		}
+	}
+
+	for _, invalid := range invalidCases {
+		if err := ValidateResourcePath(invalid); err == nil {
+			t.Errorf("Expected %s to be invalid, but validation passed", invalid)
+		}
+	}
+}
+
 func TestValidateProject(t *testing.T) {
 	validCases := []string{
 		"my-project",
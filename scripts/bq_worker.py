@@ -0,0 +1,204 @@
+#!/usr/bin/env python3
+"""Persistent bq worker for bqs (BQS_BQ_WORKER).
+
+Loads bq once, then runs each command in a fork of this process, so commands
+skip the interpreter startup and imports that every one-shot bq pays. Speaks
+the framing of bqs's BQSession: one JSON request per line on stdin,
+
+    {"id": 7, "args": ["show", "--format=json", "my-project:analytics"]}
+
+answered as each command finishes, in any order, by a JSON header line on
+stdout followed by exactly as many bytes of the command's stdout and stderr
+as the header announces:
+
+    {"id": 7, "exit": 0, "stdout": 1234, "stderr": 0}
+
+Usage: bq_worker.py [BQ_SCRIPT]
+
+BQ_SCRIPT is bq's Python entry point, a script with a main() that reads
+sys.argv. It defaults to the bq on PATH, which must be a Python script, as a
+standalone bq is.
+
+The Cloud SDK's bq is a shell wrapper instead: its bin/bootstrapping/bq.py
+adds gcloud's credentials and flags and then starts platform/bq/bq.py in a
+new process for every command, so there is nothing to load once. Running
+platform/bq/bq.py directly would skip that setup, so the worker refuses the
+SDK's bq and exits; bqs then runs commands with one-shot bq.
+"""
+
+import importlib.util
+import json
+import os
+import runpy
+import selectors
+import shutil
+import sys
+import traceback
+
+
+SDK_REFUSAL = ("bq_worker: %s is the Cloud SDK's bq, which sets up gcloud credentials "
+               "in a new process for every command; run it one-shot instead")
+
+
+def find_bq():
+    """Returns the Python script behind the bq on PATH."""
+    path = shutil.which("bq")
+    if path is None:
+        sys.exit("bq_worker: bq not found on PATH")
+    path = os.path.realpath(path)
+    with open(path, "rb") as f:
+        if b"python" in f.readline():
+            return path
+    if is_sdk_script(os.path.join(os.path.dirname(os.path.dirname(path)), "platform", "bq", "bq.py")):
+        sys.exit(SDK_REFUSAL % path)
+    sys.exit("bq_worker: can't find the Python script behind " + path)
+
+
+def is_sdk_script(script):
+    """Reports whether script is the Cloud SDK's platform/bq/bq.py, which
+    expects bin/bootstrapping/bq.py to have run first."""
+    script = os.path.realpath(script)
+    if not script.endswith(os.path.join(os.sep + "platform", "bq", "bq.py")) or not os.path.exists(script):
+        return False
+    sdk = os.path.dirname(os.path.dirname(os.path.dirname(script)))
+    return os.path.exists(os.path.join(sdk, "bin", "bootstrapping", "bq.py"))
+
+
+def preload(script):
+    """Imports the bq script without running it, so forks start with bq and
+    everything it imports already loaded. Returns None if it can't be."""
+    sys.path.insert(0, os.path.dirname(script))
+    try:
+        spec = importlib.util.spec_from_file_location("bq_worker_main", script)
+        module = importlib.util.module_from_spec(spec)
+        spec.loader.exec_module(module)
+    except BaseException:  # Commands then load bq themselves
+        traceback.print_exc()
+        return None
+    return module if callable(getattr(module, "main", None)) else None
+
+
+def run(script, module, args, stdout, stderr):
+    """Runs one bq command in a forked child and never returns."""
+    code = 1
+    try:
+        null = os.open(os.devnull, os.O_RDONLY)
+        os.dup2(null, 0)
+        os.dup2(stdout, 1)
+        os.dup2(stderr, 2)
+        sys.stdin = open(0, closefd=False)
+        sys.stdout = open(1, "w", closefd=False)
+        sys.stderr = open(2, "w", closefd=False)
+        sys.argv = ["bq"] + args
+        try:
+            if module is not None:
+                module.main()
+            else:
+                runpy.run_path(script, run_name="__main__")
+            code = 0
+        except SystemExit as e:
+            if e.code is None or isinstance(e.code, int):
+                code = e.code or 0
+            else:
+                print(e.code, file=sys.stderr)
+    except BaseException:
+        traceback.print_exc()
+    finally:
+        try:
+            sys.stdout.flush()
+            sys.stderr.flush()
+        finally:
+            os._exit(code)
+
+
+class Command:
+    """A command running in a child, with the output read from it so far."""
+
+    def __init__(self, request_id, pid):
+        self.id = request_id
+        self.pid = pid
+        self.output = {1: [], 2: []}
+        self.open = 2
+
+
+def write_all(fd, data):
+    while data:
+        data = data[os.write(fd, data):]
+
+
+def answer(out, request_id, code, stdout=b"", stderr=b""):
+    header = {"id": request_id, "exit": code, "stdout": len(stdout), "stderr": len(stderr)}
+    write_all(out, json.dumps(header).encode() + b"\n" + stdout + stderr)
+
+
+def main():
+    script = sys.argv[1] if len(sys.argv) > 1 else find_bq()
+    if is_sdk_script(script):
+        sys.exit(SDK_REFUSAL % script)
+
+    # Answers go to the original stdout; anything else printed goes to stderr
+    # so it can't break the framing
+    out = os.dup(1)
+    os.dup2(2, 1)
+    module = preload(script)
+
+    selector = selectors.DefaultSelector()
+    selector.register(0, selectors.EVENT_READ)
+    pending = b""
+    reading = True
+    running = 0
+
+    # Single-threaded, so forking never copies a lock another thread holds
+    while reading or running:
+        for key, _ in selector.select():
+            if key.data is None:
+                chunk = os.read(0, 65536)
+                if not chunk:
+                    selector.unregister(0)
+                    reading = False
+                    continue
+                pending += chunk
+                while b"\n" in pending:
+                    line, pending = pending.split(b"\n", 1)
+                    try:
+                        request = json.loads(line)
+                        request_id, args = request["id"], request["args"]
+                    except (ValueError, KeyError, TypeError):
+                        continue  # No id to answer
+                    if not isinstance(args, list) or not all(isinstance(a, str) for a in args):
+                        answer(out, request_id, 2, stderr=b"bq_worker: args must be a list of strings")
+                        continue
+
+                    stdout_r, stdout_w = os.pipe()
+                    stderr_r, stderr_w = os.pipe()
+                    pid = os.fork()
+                    if pid == 0:
+                        os.close(out)
+                        run(script, module, args, stdout_w, stderr_w)
+                    os.close(stdout_w)
+                    os.close(stderr_w)
+                    command = Command(request_id, pid)
+                    selector.register(stdout_r, selectors.EVENT_READ, (command, 1))
+                    selector.register(stderr_r, selectors.EVENT_READ, (command, 2))
+                    running += 1
+                continue
+
+            command, stream = key.data
+            chunk = os.read(key.fd, 65536)
+            if chunk:
+                command.output[stream].append(chunk)
+                continue
+            selector.unregister(key.fd)
+            os.close(key.fd)
+            command.open -= 1
+            if command.open == 0:
+                _, status = os.waitpid(command.pid, 0)
+                code = os.waitstatus_to_exitcode(status)
+                if code < 0:  # Killed by a signal, reported as a shell would
+                    code = 128 - code
+                answer(out, command.id, code, b"".join(command.output[1]), b"".join(command.output[2]))
+                running -= 1
+
+
+if __name__ == "__main__":
+    main()